@@ -6,11 +6,17 @@ import (
 	"io"
 
 	"os"
+	_ "time/tzdata"
 	"todolist_gin_gorm/cmd/router"
 	"todolist_gin_gorm/internal/config"
 	"todolist_gin_gorm/internal/database"
 	"todolist_gin_gorm/internal/database/mysql"
+	"todolist_gin_gorm/internal/mailer"
+	"todolist_gin_gorm/internal/notifier"
+	"todolist_gin_gorm/internal/oidc"
+	"todolist_gin_gorm/internal/reminder"
 	"todolist_gin_gorm/internal/service"
+	"todolist_gin_gorm/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/kelseyhightower/envconfig"
@@ -54,11 +60,56 @@ func main() {
 	// initialize repositories
 	todolistRepository := database.NewTodoRepository(db)
 
+	// initialize mailer
+	mail, err := mailer.New(&cfg)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	// initialize attachment storage
+	attachmentStorage, err := storage.New(&cfg)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	options := []service.Option{
+		service.WithConfig(&cfg),
+		service.WithMailer(mail),
+		service.WithStorage(attachmentStorage),
+	}
+
+	// initialize identity provider
+	if cfg.OIDCIssuer != "" {
+		if cfg.OIDCStateSecret == "" {
+			logrus.Fatal("OIDC_STATE_SECRET is required when OIDC_ISSUER is set")
+		}
+
+		provider, err := oidc.Discover(ctx, oidc.Config{
+			Name:         cfg.OIDCProviderName,
+			Issuer:       cfg.OIDCIssuer,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+		}, nil)
+		if err != nil {
+			logrus.Fatalf("error initializing oidc provider %v", err)
+		}
+		options = append(options, service.WithIdentityProvider(provider))
+	}
+
+	// initialize reminder scheduler
+	reminderNotifier, err := notifier.New(&cfg, mail)
+	if err != nil {
+		logrus.Fatal(err)
+	}
+	go reminder.NewScheduler(todolistRepository, reminderNotifier, cfg.ReminderInterval).Run(ctx)
+
 	// initialize service
-	todolistHandler := service.NewHandlerImpl(todolistRepository)
+	todolistHandler := service.NewHandlerImpl(todolistRepository, options...)
 
 	// initialize router
-	routeBuilder := router.NewRouteBuilder(todolistHandler)
+	routeBuilder := router.NewRouteBuilder(todolistHandler, todolistRepository)
 	routerInit := routeBuilder.RouteInit()
 
 	err = routerInit.Run(":1234")
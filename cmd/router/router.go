@@ -2,6 +2,8 @@ package router
 
 import (
 	"todolist_gin_gorm/internal/middleware"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
 	"todolist_gin_gorm/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -9,11 +11,13 @@ import (
 
 type RouteBuilder struct {
 	todoHandler *service.HandlerImpl
+	repository  repository.Repository
 }
 
-func NewRouteBuilder(todoHandler *service.HandlerImpl) *RouteBuilder {
+func NewRouteBuilder(todoHandler *service.HandlerImpl, repository repository.Repository) *RouteBuilder {
 	return &RouteBuilder{
 		todoHandler: todoHandler,
+		repository:  repository,
 	}
 }
 
@@ -21,12 +25,11 @@ func (routeBuilder *RouteBuilder) RouteInit() *gin.Engine {
 	// create Gin - Router
 	router := gin.Default()
 
-	// router.Use(gin.Recovery(), middleware.Logger(), middleware.XAPIKEY())
-	router.Use(gin.Recovery(), middleware.Logger())
+	router.Use(gin.Recovery(), middleware.RequestID(), middleware.Logger())
 
-	// Group routes that require authentication
+	// Group routes that require authentication, either a Bearer JWT or an X-API-Key
 	authGroup := router.Group("/api")
-	authGroup.Use(middleware.AuthMiddlewareJWT()) // Apply authentication middleware
+	authGroup.Use(middleware.Authenticate(routeBuilder.repository)) // Apply authentication middleware
 
 	// register routes
 	authGroup.GET("/find_all_todolist", routeBuilder.todoHandler.GetAllHandlerTodolist)
@@ -34,10 +37,87 @@ func (routeBuilder *RouteBuilder) RouteInit() *gin.Engine {
 	authGroup.POST("/create_todolist", routeBuilder.todoHandler.CreateHandlerTodolist)
 	authGroup.PUT("/update_todolist/:todolistId", routeBuilder.todoHandler.UpdateHandlerTodolist)
 	authGroup.DELETE("/delete_todolist/:todolistId", routeBuilder.todoHandler.DeleteHandlerTodolist)
+	authGroup.GET("/todos/search", routeBuilder.todoHandler.SearchTodosHandler)
+	authGroup.GET("/todos/export", routeBuilder.todoHandler.ExportTodosHandler)
+	authGroup.POST("/todos/import", routeBuilder.todoHandler.ImportTodosHandler)
+	authGroup.POST("/todos/bulk", routeBuilder.todoHandler.BulkTodosHandler)
+	authGroup.PUT("/todos/:todolistId/list", routeBuilder.todoHandler.MoveTodoHandler)
+	authGroup.POST("/todos/:todolistId/move", routeBuilder.todoHandler.RepositionTodoHandler)
+	authGroup.POST("/todos/:todolistId/toggle", routeBuilder.todoHandler.ToggleTodoHandler)
+	authGroup.GET("/todos/:todolistId/history", routeBuilder.todoHandler.TodoHistoryHandler)
+	authGroup.POST("/undo/:operationId", routeBuilder.todoHandler.UndoHandler)
+	authGroup.POST("/todos/:todolistId/recurrence/skip", routeBuilder.todoHandler.SkipOccurrenceHandler)
+	authGroup.DELETE("/todos/:todolistId/recurrence", routeBuilder.todoHandler.StopRecurrenceHandler)
+	authGroup.GET("/todos/:todolistId/subtasks", routeBuilder.todoHandler.GetSubtasksHandler)
+	authGroup.POST("/todos/:todolistId/subtasks", routeBuilder.todoHandler.AddSubtaskHandler)
+	authGroup.PUT("/todos/:todolistId/subtasks/order", routeBuilder.todoHandler.ReorderSubtasksHandler)
+	authGroup.GET("/todos/:todolistId/attachments", routeBuilder.todoHandler.GetAttachmentsHandler)
+	authGroup.POST("/todos/:todolistId/attachments", routeBuilder.todoHandler.UploadAttachmentHandler)
+	authGroup.GET("/todos/:todolistId/attachments/:attachmentId", routeBuilder.todoHandler.DownloadAttachmentHandler)
+	authGroup.DELETE("/todos/:todolistId/attachments/:attachmentId", routeBuilder.todoHandler.DeleteAttachmentHandler)
+	authGroup.GET("/todos/:todolistId/comments", routeBuilder.todoHandler.GetCommentsHandler)
+	authGroup.POST("/todos/:todolistId/comments", routeBuilder.todoHandler.CreateCommentHandler)
+	authGroup.PATCH("/todos/:todolistId/comments/:commentId", routeBuilder.todoHandler.UpdateCommentHandler)
+	authGroup.DELETE("/todos/:todolistId/comments/:commentId", routeBuilder.todoHandler.DeleteCommentHandler)
+	authGroup.GET("/todos/:todolistId/shares", routeBuilder.todoHandler.GetTodoSharesHandler)
+	authGroup.POST("/todos/:todolistId/shares", routeBuilder.todoHandler.ShareTodoHandler)
+	authGroup.DELETE("/todos/:todolistId/shares/:shareId", routeBuilder.todoHandler.RevokeTodoShareHandler)
+	authGroup.PUT("/todos/:todolistId/tags/:tagId", routeBuilder.todoHandler.AttachTagHandler)
+	authGroup.DELETE("/todos/:todolistId/tags/:tagId", routeBuilder.todoHandler.DetachTagHandler)
+	authGroup.GET("/tags", routeBuilder.todoHandler.GetTagsHandler)
+	authGroup.POST("/tags", routeBuilder.todoHandler.CreateTagHandler)
+	authGroup.PATCH("/tags/:tagId", routeBuilder.todoHandler.RenameTagHandler)
+	authGroup.DELETE("/tags/:tagId", routeBuilder.todoHandler.DeleteTagHandler)
+	authGroup.GET("/lists", routeBuilder.todoHandler.GetListsHandler)
+	authGroup.POST("/lists", routeBuilder.todoHandler.CreateListHandler)
+	authGroup.GET("/lists/:listId", routeBuilder.todoHandler.GetListHandler)
+	authGroup.PATCH("/lists/:listId", routeBuilder.todoHandler.UpdateListHandler)
+	authGroup.DELETE("/lists/:listId", routeBuilder.todoHandler.DeleteListHandler)
+	authGroup.POST("/lists/:listId/archive", routeBuilder.todoHandler.ArchiveListHandler)
+	authGroup.POST("/lists/:listId/unarchive", routeBuilder.todoHandler.UnarchiveListHandler)
+	authGroup.GET("/lists/:listId/todos", routeBuilder.todoHandler.GetListTodosHandler)
+	authGroup.GET("/lists/:listId/shares", routeBuilder.todoHandler.GetListSharesHandler)
+	authGroup.POST("/lists/:listId/shares", routeBuilder.todoHandler.ShareListHandler)
+	authGroup.DELETE("/lists/:listId/shares/:shareId", routeBuilder.todoHandler.RevokeListShareHandler)
+	authGroup.GET("/shared_with_me", routeBuilder.todoHandler.SharedWithMeHandler)
+	authGroup.GET("/me", routeBuilder.todoHandler.GetProfileHandler)
+
+	// account and credential routes can't be reached with an api key
+	authGroup.PATCH("/me", middleware.RequireJWT(), routeBuilder.todoHandler.UpdateProfileHandler)
+	authGroup.DELETE("/me", middleware.RequireJWT(), routeBuilder.todoHandler.DeleteAccountHandler)
+	authGroup.PUT("/me/password", middleware.RequireJWT(), routeBuilder.todoHandler.ChangePasswordHandler)
+	authGroup.GET("/me/api_keys", middleware.RequireJWT(), routeBuilder.todoHandler.ListAPIKeysHandler)
+	authGroup.POST("/me/api_keys", middleware.RequireJWT(), routeBuilder.todoHandler.CreateAPIKeyHandler)
+	authGroup.DELETE("/me/api_keys/:keyId", middleware.RequireJWT(), routeBuilder.todoHandler.RevokeAPIKeyHandler)
+	authGroup.POST("/me/2fa/setup", middleware.RequireJWT(), routeBuilder.todoHandler.SetupTwoFactorHandler)
+	authGroup.POST("/me/2fa/confirm", middleware.RequireJWT(), routeBuilder.todoHandler.ConfirmTwoFactorHandler)
+	authGroup.DELETE("/me/2fa", middleware.RequireJWT(), routeBuilder.todoHandler.DisableTwoFactorHandler)
+	authGroup.GET("/me/sessions", middleware.RequireJWT(), routeBuilder.todoHandler.ListSessionsHandler)
+	authGroup.DELETE("/me/sessions/:sessionId", middleware.RequireJWT(), routeBuilder.todoHandler.RevokeSessionHandler)
+
+	// admin routes
+	adminGroup := authGroup.Group("/admin")
+	adminGroup.Use(middleware.RequireJWT(), middleware.RequireRole(entity.RoleAdmin))
+
+	adminGroup.GET("/users", routeBuilder.todoHandler.AdminListUsersHandler)
+	adminGroup.POST("/users/:userId/disable", routeBuilder.todoHandler.AdminDisableUserHandler)
+	adminGroup.POST("/users/:userId/enable", routeBuilder.todoHandler.AdminEnableUserHandler)
+	adminGroup.POST("/users/:userId/reset_password", routeBuilder.todoHandler.AdminResetPasswordHandler)
+	adminGroup.PUT("/users/:userId/role", routeBuilder.todoHandler.AdminUpdateRoleHandler)
+	adminGroup.GET("/users/:userId/todos", routeBuilder.todoHandler.AdminGetUserTodosHandler)
+	adminGroup.POST("/users/:userId/2fa/disable", routeBuilder.todoHandler.AdminDisableTwoFactorHandler)
+	adminGroup.GET("/audit_events", routeBuilder.todoHandler.AdminListAuditEventsHandler)
 
 	// public routes
 	router.POST("/register", routeBuilder.todoHandler.RegisterHandler)
 	router.POST("/login", routeBuilder.todoHandler.LoginHandler)
+	router.POST("/login/2fa", routeBuilder.todoHandler.LoginTwoFactorHandler)
+	router.GET("/verify_email", routeBuilder.todoHandler.VerifyEmailHandler)
+	router.POST("/resend_verification", routeBuilder.todoHandler.ResendVerificationHandler)
+	router.POST("/password/forgot", routeBuilder.todoHandler.ForgotPasswordHandler)
+	router.POST("/password/reset", routeBuilder.todoHandler.ResetPasswordHandler)
+	router.GET("/auth/oidc/login", routeBuilder.todoHandler.OIDCLoginHandler)
+	router.GET("/auth/oidc/callback", routeBuilder.todoHandler.OIDCCallbackHandler)
 
 	return router
 }
@@ -0,0 +1,127 @@
+// Package audit computes the changes recorded in the audit log.
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+// Change is the value of a field before and after a change.
+type Change struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// Diff compares the JSON forms of before and after, field by field. Either
+// may be nil, for a create or a delete. Fields named in ignore are left out,
+// and nil is returned when nothing changed.
+func Diff(before interface{}, after interface{}, ignore ...string) (json.RawMessage, error) {
+	beforeFields, err := fields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := fields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]Change{}
+	for name, value := range afterFields {
+		if !reflect.DeepEqual(beforeFields[name], value) {
+			changes[name] = Change{Before: beforeFields[name], After: value}
+		}
+	}
+	for name, value := range beforeFields {
+		if _, ok := afterFields[name]; !ok && value != nil {
+			changes[name] = Change{Before: value}
+		}
+	}
+	for _, name := range ignore {
+		delete(changes, name)
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	// map keys are marshalled in order, so equal changes give equal JSON
+	return json.Marshal(changes)
+}
+
+func fields(value interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	return fields, nil
+}
+
+var schemas sync.Map
+
+// Apply sets the fields of model, a pointer to a gorm model, from updates
+// keyed by column name as passed to gorm's Updates. It gives the state after
+// an update without reading it back. Unknown columns are skipped.
+func Apply(model interface{}, updates map[string]interface{}) error {
+	parsed, err := schema.Parse(model, &schemas, schema.NamingStrategy{})
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(model).Elem()
+	for column, update := range updates {
+		field, ok := parsed.FieldsByDBName[column]
+		if !ok {
+			continue
+		}
+
+		if err := set(value.FieldByIndex(field.StructField.Index), update); err != nil {
+			return fmt.Errorf("audit: column %s: %w", column, err)
+		}
+	}
+
+	return nil
+}
+
+// set assigns update to target, taking or making pointers as needed. nil
+// sets the zero value.
+func set(target reflect.Value, update interface{}) error {
+	source := reflect.ValueOf(update)
+	for source.IsValid() && source.Kind() == reflect.Pointer && !source.Type().AssignableTo(target.Type()) {
+		if source.IsNil() {
+			source = reflect.Value{}
+			break
+		}
+		source = source.Elem()
+	}
+
+	switch {
+	case !source.IsValid():
+		target.Set(reflect.Zero(target.Type()))
+	case source.Type().AssignableTo(target.Type()):
+		target.Set(source)
+	case target.Kind() == reflect.Pointer && source.Type().ConvertibleTo(target.Type().Elem()):
+		pointer := reflect.New(target.Type().Elem())
+		pointer.Elem().Set(source.Convert(target.Type().Elem()))
+		target.Set(pointer)
+	case source.Type().ConvertibleTo(target.Type()):
+		target.Set(source.Convert(target.Type()))
+	default:
+		return fmt.Errorf("can't set %s to %s", target.Type(), source.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type todo struct {
+	ID     int64    `json:"id"`
+	Title  string   `json:"title"`
+	DueAt  *string  `json:"due_at"`
+	Tags   []string `json:"tags"`
+	Secret string   `json:"-"`
+}
+
+func TestDiff(t *testing.T) {
+	dueAt := "2026-10-20T05:00:00Z"
+
+	testCases := []struct {
+		name     string
+		before   interface{}
+		after    interface{}
+		ignore   []string
+		expected string
+	}{
+		{
+			name:     "update",
+			before:   &todo{ID: 1, Title: "Sholat", Secret: "a"},
+			after:    &todo{ID: 1, Title: "Sholat subuh", DueAt: &dueAt, Secret: "b"},
+			expected: `{"due_at":{"before":null,"after":"2026-10-20T05:00:00Z"},"title":{"before":"Sholat","after":"Sholat subuh"}}`,
+		},
+		{
+			name:     "create",
+			after:    &todo{ID: 1, Title: "Sholat", Tags: []string{"ibadah"}},
+			ignore:   []string{"tags"},
+			expected: `{"id":{"before":null,"after":1},"title":{"before":null,"after":"Sholat"}}`,
+		},
+		{
+			name:     "delete",
+			before:   &todo{ID: 1, Title: "Sholat"},
+			after:    (*todo)(nil),
+			expected: `{"id":{"before":1,"after":null},"title":{"before":"Sholat","after":null}}`,
+		},
+		{
+			name:   "unchanged",
+			before: &todo{ID: 1, Title: "Sholat", Tags: []string{"ibadah"}},
+			after:  &todo{ID: 1, Title: "Sholat", Tags: []string{"ibadah"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := Diff(tc.before, tc.after, tc.ignore...)
+			require.NoError(t, err)
+
+			if tc.expected == "" {
+				assert.Nil(t, changes)
+				return
+			}
+			assert.JSONEq(t, tc.expected, string(changes))
+		})
+	}
+}
+
+type model struct {
+	ID          int64 `gorm:"primaryKey;column:model_id"`
+	Title       string
+	ListID      *int64
+	Occurrence  int
+	CompletedAt *time.Time
+	DueAt       *time.Time
+}
+
+func TestApply(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	listID := int64(3)
+	current := model{ID: 1, Title: "Sholat", DueAt: &now, CompletedAt: nil}
+
+	require.NoError(t, Apply(&current, map[string]interface{}{
+		"title":        "Sholat subuh",
+		"list_id":      int64(4),
+		"occurrence":   2,
+		"completed_at": now,
+		"due_at":       nil,
+		"unknown":      true,
+	}))
+
+	assert.Equal(t, "Sholat subuh", current.Title)
+	assert.Equal(t, int64(4), *current.ListID)
+	assert.Equal(t, 2, current.Occurrence)
+	assert.Equal(t, now, *current.CompletedAt)
+	assert.Nil(t, current.DueAt)
+
+	require.NoError(t, Apply(&current, map[string]interface{}{"list_id": &listID, "completed_at": (*time.Time)(nil)}))
+	assert.Equal(t, int64(3), *current.ListID)
+	assert.Nil(t, current.CompletedAt)
+
+	assert.Error(t, Apply(&current, map[string]interface{}{"title": []int{1}}))
+}
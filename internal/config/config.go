@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Config struct {
 	DBDriver   string `envconfig:"DB_DRIVER" default:"mysql"`
 	DBUser     string `envconfig:"DB_USER" default:"alwi09"`
@@ -7,4 +9,77 @@ type Config struct {
 	DBHost     string `envconfig:"DB_HOST" default:"localhost"`
 	DBPort     int    `envconfig:"DB_PORT" default:"3306"`
 	DBName     string `envconfig:"DB_NAME" default:"todolist"`
+
+	// base url used to build links sent by email
+	AppBaseURL string `envconfig:"APP_BASE_URL" default:"http://localhost:1234"`
+
+	// mailer: "smtp" sends real mail, "log" writes messages to MAIL_LOG_DIR (or the log when empty)
+	MailDriver   string `envconfig:"MAIL_DRIVER" default:"log"`
+	MailFrom     string `envconfig:"MAIL_FROM" default:"no-reply@todolist.local"`
+	MailLogDir   string `envconfig:"MAIL_LOG_DIR"`
+	SMTPHost     string `envconfig:"SMTP_HOST" default:"localhost"`
+	SMTPPort     int    `envconfig:"SMTP_PORT" default:"587"`
+	SMTPUsername string `envconfig:"SMTP_USERNAME"`
+	SMTPPassword string `envconfig:"SMTP_PASSWORD"`
+
+	// passwords
+	BcryptCost       int           `envconfig:"BCRYPT_COST" default:"10"`
+	PasswordResetTTL time.Duration `envconfig:"PASSWORD_RESET_TTL" default:"1h"`
+
+	// registration always answers 202 and tells the owner of the address by
+	// email, so the response doesn't reveal whether the email is registered
+	EnumerationSafeRegistration bool `envconfig:"ENUMERATION_SAFE_REGISTRATION" default:"false"`
+
+	// two-factor authentication, the issuer is the account name shown in authenticator apps
+	TOTPIssuer string `envconfig:"TOTP_ISSUER" default:"Todolist"`
+
+	// OpenID Connect login, enabled when OIDC_ISSUER is set
+	OIDCProviderName string   `envconfig:"OIDC_PROVIDER_NAME" default:"sso"`
+	OIDCIssuer       string   `envconfig:"OIDC_ISSUER"`
+	OIDCClientID     string   `envconfig:"OIDC_CLIENT_ID"`
+	OIDCClientSecret string   `envconfig:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL  string   `envconfig:"OIDC_REDIRECT_URL" default:"http://localhost:1234/auth/oidc/callback"`
+	OIDCScopes       []string `envconfig:"OIDC_SCOPES" default:"openid,email,profile"`
+	OIDCStateSecret  string   `envconfig:"OIDC_STATE_SECRET"`
+
+	// todo reminders: the scheduler checks every REMINDER_INTERVAL and delivers
+	// through "log", "email" or "webhook"
+	ReminderInterval      time.Duration `envconfig:"REMINDER_INTERVAL" default:"30s"`
+	ReminderNotifier      string        `envconfig:"REMINDER_NOTIFIER" default:"log"`
+	ReminderWebhookURL    string        `envconfig:"REMINDER_WEBHOOK_URL"`
+	ReminderWebhookSecret string        `envconfig:"REMINDER_WEBHOOK_SECRET"`
+
+	// comments can be edited by their author for COMMENT_EDIT_WINDOW after
+	// being posted, 0 allows editing at any time
+	CommentEditWindow time.Duration `envconfig:"COMMENT_EDIT_WINDOW" default:"15m"`
+
+	// attachments are kept by STORAGE_DRIVER, "local" below STORAGE_LOCAL_DIR
+	// or "s3" in a bucket of any S3-compatible service
+	StorageDriver     string `envconfig:"STORAGE_DRIVER" default:"local"`
+	StorageLocalDir   string `envconfig:"STORAGE_LOCAL_DIR" default:"uploads"`
+	S3Endpoint        string `envconfig:"S3_ENDPOINT"`
+	S3Region          string `envconfig:"S3_REGION" default:"us-east-1"`
+	S3Bucket          string `envconfig:"S3_BUCKET"`
+	S3AccessKeyID     string `envconfig:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey string `envconfig:"S3_SECRET_ACCESS_KEY"`
+
+	// uploads larger than ATTACHMENT_MAX_SIZE bytes, or whose sniffed type is
+	// not in ATTACHMENT_TYPES, are refused
+	AttachmentMaxSize int64    `envconfig:"ATTACHMENT_MAX_SIZE" default:"10485760"`
+	AttachmentTypes   []string `envconfig:"ATTACHMENT_TYPES" default:"image/png,image/jpeg,image/gif,image/webp,application/pdf,text/plain,text/csv"`
+
+	// POST /api/todos/bulk takes at most BULK_MAX_OPERATIONS operations, 0
+	// lifts the limit
+	BulkMaxOperations int `envconfig:"BULK_MAX_OPERATIONS" default:"100"`
+
+	// POST /api/todos/import refuses files larger than IMPORT_MAX_SIZE bytes
+	ImportMaxSize int64 `envconfig:"IMPORT_MAX_SIZE" default:"5242880"`
+
+	// updates and deletes of todos can be reverted with POST /api/undo for
+	// UNDO_WINDOW, 0 turns undo off
+	UndoWindow time.Duration `envconfig:"UNDO_WINDOW" default:"10m"`
+
+	// email verification
+	RequireEmailVerification bool          `envconfig:"REQUIRE_EMAIL_VERIFICATION" default:"false"`
+	EmailVerificationTTL     time.Duration `envconfig:"EMAIL_VERIFICATION_TTL" default:"24h"`
 }
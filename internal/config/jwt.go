@@ -1,23 +1,68 @@
 package config
 
 import (
+	"errors"
 	"time"
+	"todolist_gin_gorm/internal/model/entity"
 
 	"github.com/dgrijalva/jwt-go"
 )
 
 // untuk sign-in token
 const (
-	jwtSecretKey       = "secret-key"
-	jwtExpirationHours = 24
-	jwtAlgorithm       = "HS256"
+	jwtSecretKey = "secret-key"
+	jwtAlgorithm = "HS256"
+
+	// AccessTokenExpiration is also the lifetime of the session row recorded
+	// for each access token
+	AccessTokenExpiration = 24 * time.Hour
+
+	// a challenge token only proves the password was right, it is exchanged
+	// for an access token at /login/2fa
+	challengePurpose = "2fa_challenge"
+
+	// ChallengeExpiration is also the lifetime of the challenge row the jti
+	// of a challenge token points to
+	ChallengeExpiration = 5 * time.Minute
 )
 
-func CreateJWTToken(email string) (string, error) {
+// Claims is the payload of the access token. TokenVersion must match the
+// user's current version, bumping it revokes every token issued before.
+type Claims struct {
+	UserID       int64  `json:"user_id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	TokenVersion int    `json:"token_version"`
+	Purpose      string `json:"purpose,omitempty"`
+	jwt.StandardClaims
+}
+
+// CreateJWTToken signs an access token for the session identified by
+// sessionID, which is carried in the jti claim.
+func CreateJWTToken(user *entity.Users, sessionID string) (string, error) {
+	return signToken(user, "", sessionID, AccessTokenExpiration)
+}
+
+// CreateChallengeToken is returned by the login of a user with 2FA enabled.
+// challengeID, carried in the jti claim, names the challenge it answers.
+func CreateChallengeToken(user *entity.Users, challengeID string) (string, error) {
+	return signToken(user, challengePurpose, challengeID, ChallengeExpiration)
+}
+
+func signToken(user *entity.Users, purpose string, id string, expiration time.Duration) (string, error) {
 	// Buat payload token
-	claims := jwt.MapClaims{
-		"email": email,
-		"exp":   time.Now().Add(time.Hour * jwtExpirationHours).Unix(),
+	claims := Claims{
+		UserID:       user.UserID,
+		Email:        user.Email,
+		Role:         user.Role,
+		TokenVersion: user.TokenVersion,
+		Purpose:      purpose,
+		StandardClaims: jwt.StandardClaims{
+			Id:        id,
+			Subject:   user.Email,
+			IssuedAt:  time.Now().Unix(),
+			ExpiresAt: time.Now().Add(expiration).Unix(),
+		},
 	}
 
 	// Buat token JWT menggunakan kunci rahasia dan algoritma yang sesuai
@@ -29,3 +74,49 @@ func CreateJWTToken(email string) (string, error) {
 
 	return signedToken, nil
 }
+
+// ParseJWTToken parses an access token, challenge tokens are rejected.
+func ParseJWTToken(tokenString string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != "" {
+		return nil, errors.New("token is not an access token")
+	}
+
+	return claims, nil
+}
+
+func ParseChallengeToken(tokenString string) (*Claims, error) {
+	claims, err := parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Purpose != challengePurpose {
+		return nil, errors.New("token is not a challenge token")
+	}
+
+	return claims, nil
+}
+
+func parseToken(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() != jwtAlgorithm {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(jwtSecretKey), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, errors.New("token is not valid")
+	}
+
+	return claims, nil
+}
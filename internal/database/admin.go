@@ -0,0 +1,62 @@
+package database
+
+import (
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+// ListUsers pages through users whose username or email contains search.
+func (repository *TodoRepository) ListUsers(search string, limit int, offset int) ([]entity.Users, int64, error) {
+	query := repository.DB.Model(&entity.Users{})
+	if search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where(`username LIKE ? ESCAPE '\' OR email LIKE ? ESCAPE '\'`, pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []entity.Users
+	result := query.Order("user_id").Limit(limit).Offset(offset).Find(&users)
+
+	return users, total, result.Error
+}
+
+// SetUserDisabled disables or re-enables an account. Disabling also revokes
+// the tokens and API keys the user already holds.
+func (repository *TodoRepository) SetUserDisabled(userID int64, disabled bool) error {
+	if !disabled {
+		return repository.DB.Model(&entity.Users{}).Where("user_id = ?", userID).Update("disabled_at", nil).Error
+	}
+
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Users{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"disabled_at":   time.Now(),
+			"token_version": gorm.Expr("token_version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return revokeAPIKeys(tx, userID)
+	})
+}
+
+// SetUserRole changes the role and revokes tokens carrying the old one.
+func (repository *TodoRepository) SetUserRole(userID int64, role string) error {
+	return repository.DB.Model(&entity.Users{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"role":          role,
+		"token_version": gorm.Expr("token_version + 1"),
+	}).Error
+}
+
+func (repository *TodoRepository) GetAllByUser(userID int64) ([]entity.Todos, error) {
+	var todos []entity.Todos
+	result := repository.DB.Where("user_id = ?", userID).Find(&todos)
+
+	return todos, result.Error
+}
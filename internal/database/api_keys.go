@@ -0,0 +1,49 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) CreateAPIKey(key *entity.APIKeys) error {
+	return repository.DB.Create(key).Error
+}
+
+func (repository *TodoRepository) ListAPIKeys(userID int64) ([]entity.APIKeys, error) {
+	var keys []entity.APIKeys
+	result := repository.DB.Where("user_id = ?", userID).Order("key_id").Find(&keys)
+
+	return keys, result.Error
+}
+
+func (repository *TodoRepository) FindAPIKeyByHash(keyHash string) (*entity.APIKeys, error) {
+	var key entity.APIKeys
+	result := repository.DB.Where("key_hash = ?", keyHash).First(&key)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &key, result.Error
+}
+
+func (repository *TodoRepository) RevokeAPIKey(userID int64, keyID int64) (int64, error) {
+	result := repository.DB.Model(&entity.APIKeys{}).
+		Where("key_id = ? AND user_id = ? AND revoked_at IS NULL", keyID, userID).
+		Update("revoked_at", time.Now())
+
+	return result.RowsAffected, result.Error
+}
+
+func (repository *TodoRepository) TouchAPIKey(keyID int64) error {
+	return repository.DB.Model(&entity.APIKeys{}).Where("key_id = ?", keyID).Update("last_used_at", time.Now()).Error
+}
+
+// revokeAPIKeys revokes every key of the user that is still usable
+func revokeAPIKeys(tx *gorm.DB, userID int64) error {
+	return tx.Model(&entity.APIKeys{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
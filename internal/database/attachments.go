@@ -0,0 +1,67 @@
+package database
+
+import (
+	"errors"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) CreateAttachment(attachment *entity.Attachments) error {
+	return repository.DB.Create(attachment).Error
+}
+
+func (repository *TodoRepository) GetAttachments(todoID int64) ([]entity.Attachments, error) {
+	var attachments []entity.Attachments
+	result := repository.DB.Where("todos_id = ?", todoID).Order("attachment_id").Find(&attachments)
+
+	return attachments, result.Error
+}
+
+func (repository *TodoRepository) FindAttachment(attachmentID int64) (*entity.Attachments, error) {
+	var attachment entity.Attachments
+	result := repository.DB.Where("attachment_id = ?", attachmentID).First(&attachment)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &attachment, result.Error
+}
+
+func (repository *TodoRepository) DeleteAttachment(attachmentID int64) (int64, error) {
+	result := repository.DB.Delete(&entity.Attachments{}, attachmentID)
+
+	return result.RowsAffected, result.Error
+}
+
+// AttachmentKeys returns the storage keys of the files attached to the todo
+// and its subtasks, which go away with it when it is deleted.
+func (repository *TodoRepository) AttachmentKeys(todoID int64) ([]string, error) {
+	todoIDs := []int64{todoID}
+	parents := []int64{todoID}
+	for depth := 1; depth < entity.MaxSubtaskDepth && len(parents) > 0; depth++ {
+		var children []int64
+		if err := repository.DB.Model(&entity.Todos{}).Where("parent_id IN ?", parents).Pluck("todos_id", &children).Error; err != nil {
+			return nil, err
+		}
+
+		todoIDs = append(todoIDs, children...)
+		parents = children
+	}
+
+	var keys []string
+	err := repository.DB.Model(&entity.Attachments{}).Where("todos_id IN ?", todoIDs).Pluck("storage_key", &keys).Error
+
+	return keys, err
+}
+
+// UserAttachmentKeys returns the storage keys of the files that go away with
+// the user: those on their todos and those they uploaded to shared ones.
+func (repository *TodoRepository) UserAttachmentKeys(userID int64) ([]string, error) {
+	var keys []string
+	err := repository.DB.Model(&entity.Attachments{}).
+		Where("user_id = ? OR todos_id IN (?)", userID, repository.DB.Model(&entity.Todos{}).Select("todos_id").Where("user_id = ?", userID)).
+		Pluck("storage_key", &keys).Error
+
+	return keys, err
+}
@@ -0,0 +1,40 @@
+package database
+
+import (
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+func (repository *TodoRepository) CreateAuditEvent(event *entity.AuditEvents) error {
+	return repository.DB.Create(event).Error
+}
+
+// ListAuditEvents returns the events matching the query, newest first.
+func (repository *TodoRepository) ListAuditEvents(query *dto.AuditQuery) ([]entity.AuditEvents, error) {
+	db := repository.DB.Model(&entity.AuditEvents{})
+
+	if query.ActorID != 0 {
+		db = db.Where("actor_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.EntityType != "" {
+		db = db.Where("entity_type = ?", query.EntityType)
+	}
+	if query.EntityID != 0 {
+		db = db.Where("entity_id = ?", query.EntityID)
+	}
+	if !query.Since.IsZero() {
+		db = db.Where("created_at >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		db = db.Where("created_at < ?", query.Until)
+	}
+
+	limit, offset := query.LimitOffset()
+	events := []entity.AuditEvents{}
+	err := db.Order("event_id DESC").Limit(limit).Offset(offset).Find(&events).Error
+
+	return events, err
+}
@@ -0,0 +1,86 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) CreateComment(comment *entity.Comments) error {
+	return repository.DB.Create(comment).Error
+}
+
+// GetComments returns the thread of a todo, oldest first, with the authors'
+// emails. Deleted comments are left out.
+func (repository *TodoRepository) GetComments(todoID int64) ([]entity.Comments, error) {
+	var comments []entity.Comments
+	result := repository.DB.Table("comments").
+		Select("comments.*, users.email AS author_email").
+		Joins("JOIN users ON users.user_id = comments.user_id").
+		Where("comments.todos_id = ?", todoID).
+		Order("comments.comment_id").
+		Find(&comments)
+
+	return comments, result.Error
+}
+
+func (repository *TodoRepository) FindComment(commentID int64) (*entity.Comments, error) {
+	var comment entity.Comments
+	result := repository.DB.Where("comment_id = ?", commentID).First(&comment)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &comment, result.Error
+}
+
+func (repository *TodoRepository) UpdateComment(commentID int64, body string, editedAt time.Time) error {
+	return repository.DB.Model(&entity.Comments{}).
+		Where("comment_id = ?", commentID).
+		Updates(map[string]interface{}{"body": body, "edited_at": editedAt}).Error
+}
+
+// DeleteComment soft deletes the comment, it disappears from the thread.
+func (repository *TodoRepository) DeleteComment(commentID int64) (int64, error) {
+	result := repository.DB.Delete(&entity.Comments{}, commentID)
+
+	return result.RowsAffected, result.Error
+}
+
+// loadCommentCounts fills in how many comments the todos have with a single query.
+func (repository *TodoRepository) loadCommentCounts(todos []entity.Todos) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	todoIDs := make([]int64, len(todos))
+	for i, todo := range todos {
+		todoIDs[i] = todo.Id
+	}
+
+	var rows []struct {
+		TodosID int64
+		Count   int64
+	}
+	err := repository.DB.Table("comments").
+		Select("todos_id, COUNT(*) AS count").
+		Where("todos_id IN ? AND deleted_at IS NULL", todoIDs).
+		Group("todos_id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[row.TodosID] = row.Count
+	}
+
+	for i := range todos {
+		todos[i].CommentCount = counts[todos[i].Id]
+	}
+
+	return nil
+}
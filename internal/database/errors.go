@@ -0,0 +1,11 @@
+package database
+
+import "todolist_gin_gorm/internal/repository"
+
+// the method receivers are named repository, which shadows the package inside
+// method bodies, so the sentinel errors are aliased here
+var (
+	errTokenAlreadyUsed = repository.ErrTokenAlreadyUsed
+	errDuplicateTag     = repository.ErrDuplicateTag
+	errDuplicateTodo    = repository.ErrDuplicateTodo
+)
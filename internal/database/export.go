@@ -0,0 +1,25 @@
+package database
+
+import (
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+// exportBatchSize is how many todos an export holds in memory at a time
+const exportBatchSize = 500
+
+// ExportTodos passes the todos the viewer can see to fn in batches ordered by
+// id, subtasks included. The slice is reused between calls.
+func (repository *TodoRepository) ExportTodos(query *dto.TodoQuery, fn func(todos []entity.Todos) error) error {
+	var todos []entity.Todos
+
+	return filterTodos(repository.DB, query).FindInBatches(&todos, exportBatchSize, func(tx *gorm.DB, batch int) error {
+		if err := repository.loadTagNames(todos); err != nil {
+			return err
+		}
+
+		return fn(todos)
+	}).Error
+}
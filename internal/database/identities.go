@@ -0,0 +1,38 @@
+package database
+
+import (
+	"errors"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) FindUserIdentity(provider string, subject string) (*entity.UserIdentities, error) {
+	var identity entity.UserIdentities
+	result := repository.DB.Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &identity, result.Error
+}
+
+func (repository *TodoRepository) CreateUserIdentity(identity *entity.UserIdentities) error {
+	return repository.DB.Create(identity).Error
+}
+
+// CreateUserWithIdentity creates an account for a first-time external login.
+func (repository *TodoRepository) CreateUserWithIdentity(user *entity.Users, identity *entity.UserIdentities) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(entity.NewInboxList(user.UserID)).Error; err != nil {
+			return err
+		}
+
+		identity.UserID = user.UserID
+		return tx.Create(identity).Error
+	})
+}
@@ -0,0 +1,18 @@
+package database
+
+import "todolist_gin_gorm/internal/model/entity"
+
+// ImportedExternalIDs returns which of the external ids the user's todos
+// already carry.
+func (repository *TodoRepository) ImportedExternalIDs(userID int64, externalIDs []string) ([]string, error) {
+	imported := []string{}
+	if len(externalIDs) == 0 {
+		return imported, nil
+	}
+
+	err := repository.DB.Model(&entity.Todos{}).
+		Where("user_id = ? AND external_id IN ?", userID, externalIDs).
+		Pluck("external_id", &imported).Error
+
+	return imported, err
+}
@@ -0,0 +1,95 @@
+package database
+
+import (
+	"errors"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/rank"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) CreateList(list *entity.Lists) error {
+	return repository.DB.Create(list).Error
+}
+
+// GetLists returns the user's lists, inbox first. Archived lists are left out
+// unless includeArchived is set.
+func (repository *TodoRepository) GetLists(userID int64, includeArchived bool) ([]entity.Lists, error) {
+	var lists []entity.Lists
+	db := repository.DB.Where("user_id = ?", userID)
+	if !includeArchived {
+		db = db.Where("archived_at IS NULL")
+	}
+
+	result := db.Order("is_inbox DESC, list_id").Find(&lists)
+
+	return lists, result.Error
+}
+
+func (repository *TodoRepository) FindList(userID int64, listID int64) (*entity.Lists, error) {
+	var list entity.Lists
+	result := repository.DB.Where("list_id = ? AND user_id = ?", listID, userID).First(&list)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &list, result.Error
+}
+
+func (repository *TodoRepository) UpdateList(listID int64, updates map[string]interface{}) (*entity.Lists, error) {
+	var list entity.Lists
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Lists{}).Where("list_id = ?", listID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("list_id = ?", listID).First(&list).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// DeleteList removes a list and moves its todos to the owner's inbox, the top
+// level ones after the last todo already there, in their old order.
+func (repository *TodoRepository) DeleteList(list *entity.Lists) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		var inboxID *int64
+		var inbox entity.Lists
+		result := tx.Where("user_id = ? AND is_inbox = ?", list.UserID, true).Limit(1).Find(&inbox)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			inboxID = &inbox.ListID
+		}
+
+		last, err := (&TodoRepository{DB: tx}).LastPosition(inboxID)
+		if err != nil {
+			return err
+		}
+
+		var todoIDs []int64
+		err = tx.Model(&entity.Todos{}).Where("list_id = ? AND parent_id IS NULL", list.ListID).Order("position").Pluck("todos_id", &todoIDs).Error
+		if err != nil {
+			return err
+		}
+
+		for _, todoID := range todoIDs {
+			if last, err = rank.Between(last, ""); err != nil {
+				return err
+			}
+			if err := tx.Model(&entity.Todos{}).Where("todos_id = ?", todoID).Update("position", last).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&entity.Todos{}).Where("list_id = ?", list.ListID).Update("list_id", inboxID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(&entity.Lists{}, list.ListID).Error
+	})
+}
@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN user_id BIGINT NULL DEFAULT NULL AFTER todos_id, ADD CONSTRAINT fk_todos_user_id FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user' AFTER email_verified_at, ADD COLUMN disabled_at TIMESTAMP NULL DEFAULT NULL AFTER role;
@@ -0,0 +1,12 @@
+CREATE TABLE user_identities (
+    identity_id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    provider VARCHAR(55) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(55) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (identity_id),
+    UNIQUE KEY uq_user_identities_provider_subject (provider, subject),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
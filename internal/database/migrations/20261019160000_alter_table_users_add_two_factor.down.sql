@@ -0,0 +1 @@
+ALTER TABLE users DROP COLUMN two_factor_enabled_at, DROP COLUMN totp_last_step, DROP COLUMN totp_secret;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN totp_secret VARCHAR(64) NULL DEFAULT NULL AFTER disabled_at, ADD COLUMN totp_last_step BIGINT NOT NULL DEFAULT 0 AFTER totp_secret, ADD COLUMN two_factor_enabled_at TIMESTAMP NULL DEFAULT NULL AFTER totp_last_step;
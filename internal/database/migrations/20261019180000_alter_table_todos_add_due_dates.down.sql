@@ -0,0 +1 @@
+ALTER TABLE todos DROP INDEX idx_todos_reminder, DROP INDEX idx_todos_due_at, DROP COLUMN reminded_at, DROP COLUMN remind_at, DROP COLUMN timezone, DROP COLUMN due_at;
@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN due_at TIMESTAMP NULL DEFAULT NULL, ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC', ADD COLUMN remind_at TIMESTAMP NULL DEFAULT NULL, ADD COLUMN reminded_at TIMESTAMP NULL DEFAULT NULL, ADD INDEX idx_todos_due_at (due_at), ADD INDEX idx_todos_reminder (reminded_at, remind_at);
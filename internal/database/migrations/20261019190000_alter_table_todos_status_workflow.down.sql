@@ -0,0 +1 @@
+ALTER TABLE todos DROP INDEX idx_todos_status, DROP COLUMN completed_at, DROP COLUMN priority, MODIFY COLUMN status TINYINT DEFAULT FALSE;
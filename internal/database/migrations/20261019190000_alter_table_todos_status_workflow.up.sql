@@ -0,0 +1 @@
+ALTER TABLE todos MODIFY COLUMN status VARCHAR(16) NOT NULL DEFAULT 'todo', ADD COLUMN priority VARCHAR(8) NOT NULL DEFAULT 'medium' AFTER status, ADD COLUMN completed_at TIMESTAMP NULL DEFAULT NULL AFTER priority, ADD INDEX idx_todos_status (status);
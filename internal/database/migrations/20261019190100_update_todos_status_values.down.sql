@@ -0,0 +1 @@
+UPDATE todos SET status = IF(status = 'done', '1', '0');
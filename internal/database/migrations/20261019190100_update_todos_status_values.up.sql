@@ -0,0 +1 @@
+UPDATE todos SET completed_at = IF(status = '1', updated_at, NULL), status = IF(status = '1', 'done', 'todo');
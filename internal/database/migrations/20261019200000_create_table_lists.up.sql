@@ -0,0 +1,13 @@
+CREATE TABLE lists (
+    list_id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    name VARCHAR(99) NOT NULL,
+    is_inbox BOOLEAN NOT NULL DEFAULT FALSE,
+    sort_by VARCHAR(16) NOT NULL DEFAULT 'created',
+    archived_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (list_id),
+    KEY idx_lists_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE todos DROP FOREIGN KEY fk_todos_list_id, DROP COLUMN list_id;
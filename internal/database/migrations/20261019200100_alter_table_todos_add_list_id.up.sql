@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN list_id BIGINT NULL DEFAULT NULL AFTER user_id, ADD CONSTRAINT fk_todos_list_id FOREIGN KEY (list_id) REFERENCES lists(list_id) ON DELETE SET NULL;
@@ -0,0 +1 @@
+DELETE FROM lists WHERE is_inbox = TRUE;
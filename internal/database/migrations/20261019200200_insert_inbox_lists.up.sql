@@ -0,0 +1 @@
+INSERT INTO lists (user_id, name, is_inbox) SELECT user_id, 'Inbox', TRUE FROM users;
@@ -0,0 +1 @@
+UPDATE todos SET list_id = NULL;
@@ -0,0 +1 @@
+UPDATE todos JOIN lists ON lists.user_id = todos.user_id AND lists.is_inbox = TRUE SET todos.list_id = lists.list_id;
@@ -0,0 +1 @@
+ALTER TABLE todos DROP FOREIGN KEY fk_todos_parent_id, DROP COLUMN subtask_order, DROP COLUMN parent_id;
@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN parent_id BIGINT NULL DEFAULT NULL AFTER list_id, ADD COLUMN subtask_order INT NOT NULL DEFAULT 0 AFTER parent_id, ADD CONSTRAINT fk_todos_parent_id FOREIGN KEY (parent_id) REFERENCES todos(todos_id) ON DELETE CASCADE;
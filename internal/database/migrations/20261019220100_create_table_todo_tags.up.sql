@@ -0,0 +1,9 @@
+CREATE TABLE todo_tags (
+    todos_id BIGINT NOT NULL,
+    tag_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (todos_id, tag_id),
+    INDEX idx_todo_tags_tag_id (tag_id),
+    FOREIGN KEY (todos_id) REFERENCES todos(todos_id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(tag_id) ON DELETE CASCADE
+);
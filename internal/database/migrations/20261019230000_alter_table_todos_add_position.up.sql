@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN position VARCHAR(255) CHARACTER SET ascii COLLATE ascii_bin NOT NULL DEFAULT '' AFTER subtask_order, ADD INDEX idx_todos_list_id_position (list_id, position);
@@ -0,0 +1 @@
+UPDATE todos SET position = '';
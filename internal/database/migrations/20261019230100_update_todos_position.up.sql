@@ -0,0 +1,2 @@
+-- odd numbers never end in 0 in base 36, position keys must not end in 0
+UPDATE todos SET position = LOWER(LPAD(CONV(todos_id * 2 + 1, 10, 36), 8, '0')) WHERE position = '';
@@ -0,0 +1 @@
+ALTER TABLE lists MODIFY COLUMN sort_by VARCHAR(16) NOT NULL DEFAULT 'position';
@@ -0,0 +1 @@
+ALTER TABLE todos DROP COLUMN occurrence, DROP COLUMN recurrence_rule;
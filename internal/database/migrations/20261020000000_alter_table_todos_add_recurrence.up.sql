@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN recurrence_rule VARCHAR(255) NOT NULL DEFAULT '' AFTER reminded_at, ADD COLUMN occurrence INT NOT NULL DEFAULT 1 AFTER recurrence_rule;
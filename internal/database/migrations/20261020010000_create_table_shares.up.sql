@@ -0,0 +1,19 @@
+CREATE TABLE shares (
+    share_id BIGINT NOT NULL AUTO_INCREMENT,
+    owner_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    todos_id BIGINT NULL DEFAULT NULL,
+    list_id BIGINT NULL DEFAULT NULL,
+    role VARCHAR(8) NOT NULL DEFAULT 'viewer',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (share_id),
+    UNIQUE KEY uq_shares_user_id_todos_id (user_id, todos_id),
+    UNIQUE KEY uq_shares_user_id_list_id (user_id, list_id),
+    KEY idx_shares_todos_id (todos_id),
+    KEY idx_shares_list_id (list_id),
+    FOREIGN KEY (owner_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (todos_id) REFERENCES todos(todos_id) ON DELETE CASCADE,
+    FOREIGN KEY (list_id) REFERENCES lists(list_id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+CREATE TABLE comments (
+    comment_id BIGINT NOT NULL AUTO_INCREMENT,
+    todos_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    edited_at TIMESTAMP NULL DEFAULT NULL,
+    deleted_at TIMESTAMP NULL DEFAULT NULL,
+    PRIMARY KEY (comment_id),
+    KEY idx_comments_todos_id_deleted_at (todos_id, deleted_at),
+    FOREIGN KEY (todos_id) REFERENCES todos(todos_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
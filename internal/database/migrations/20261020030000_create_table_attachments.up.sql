@@ -0,0 +1,15 @@
+CREATE TABLE attachments (
+    attachment_id BIGINT NOT NULL AUTO_INCREMENT,
+    todos_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(127) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (attachment_id),
+    UNIQUE KEY uq_attachments_storage_key (storage_key),
+    KEY idx_attachments_todos_id (todos_id),
+    FOREIGN KEY (todos_id) REFERENCES todos(todos_id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
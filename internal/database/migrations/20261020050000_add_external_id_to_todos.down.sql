@@ -0,0 +1 @@
+ALTER TABLE todos DROP INDEX idx_todos_user_external_id, DROP COLUMN external_id;
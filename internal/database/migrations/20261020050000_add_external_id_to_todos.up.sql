@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN external_id VARCHAR(128) NULL, ADD INDEX idx_todos_user_external_id (user_id, external_id);
@@ -0,0 +1,15 @@
+CREATE TABLE audit_events (
+    event_id BIGINT NOT NULL AUTO_INCREMENT,
+    actor_id BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    entity_type VARCHAR(32) NOT NULL,
+    entity_id BIGINT NOT NULL,
+    changes JSON NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id),
+    KEY idx_audit_events_entity (entity_type, entity_id),
+    KEY idx_audit_events_actor (actor_id),
+    KEY idx_audit_events_created_at (created_at)
+);
@@ -0,0 +1,14 @@
+CREATE TABLE undo_operations (
+    operation_id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    todos_id BIGINT NOT NULL,
+    snapshot JSON NOT NULL,
+    result JSON NULL,
+    expires_at TIMESTAMP NOT NULL,
+    undone_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (operation_id),
+    KEY idx_undo_operations_user_expires_at (user_id, expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+CREATE TABLE two_factor_challenges (
+    challenge_id BIGINT NOT NULL AUTO_INCREMENT,
+    user_id BIGINT NOT NULL,
+    jti CHAR(64) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (challenge_id),
+    UNIQUE KEY uq_two_factor_challenges_jti (jti),
+    KEY idx_two_factor_challenges_user_expires_at (user_id, expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE todos DROP COLUMN reminder_retry_at, DROP COLUMN reminder_attempts;
@@ -0,0 +1 @@
+ALTER TABLE todos ADD COLUMN reminder_attempts INT NOT NULL DEFAULT 0 AFTER reminded_at, ADD COLUMN reminder_retry_at TIMESTAMP NULL DEFAULT NULL AFTER reminder_attempts;
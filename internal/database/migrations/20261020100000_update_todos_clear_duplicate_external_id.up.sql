@@ -0,0 +1 @@
+UPDATE todos t JOIN (SELECT user_id, external_id, MIN(todos_id) AS todos_id FROM todos WHERE external_id IS NOT NULL GROUP BY user_id, external_id) kept ON t.user_id = kept.user_id AND t.external_id = kept.external_id SET t.external_id = NULL WHERE t.todos_id <> kept.todos_id;
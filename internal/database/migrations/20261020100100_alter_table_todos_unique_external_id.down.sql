@@ -0,0 +1 @@
+ALTER TABLE todos DROP INDEX uq_todos_user_external_id, ADD INDEX idx_todos_user_external_id (user_id, external_id);
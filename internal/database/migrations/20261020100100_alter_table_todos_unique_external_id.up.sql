@@ -0,0 +1 @@
+ALTER TABLE todos DROP INDEX idx_todos_user_external_id, ADD UNIQUE KEY uq_todos_user_external_id (user_id, external_id);
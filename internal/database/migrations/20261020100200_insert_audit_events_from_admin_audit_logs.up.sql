@@ -0,0 +1 @@
+INSERT INTO audit_events (actor_id, action, entity_type, entity_id, changes, ip_address, created_at) SELECT actor_id, CASE action WHEN 'disable_user' THEN 'disable' WHEN 'enable_user' THEN 'enable' WHEN 'reset_password' THEN 'request_password_reset' WHEN 'update_role' THEN 'update' ELSE action END, 'user', COALESCE(target_user_id, 0), CASE action WHEN 'update_role' THEN JSON_OBJECT('role', JSON_OBJECT('before', JSON_EXTRACT(details, '$.from'), 'after', JSON_EXTRACT(details, '$.to'))) WHEN 'list_users' THEN JSON_OBJECT('q', JSON_OBJECT('before', NULL, 'after', JSON_EXTRACT(details, '$.q')), 'limit', JSON_OBJECT('before', NULL, 'after', JSON_EXTRACT(details, '$.limit')), 'offset', JSON_OBJECT('before', NULL, 'after', JSON_EXTRACT(details, '$.offset'))) END, ip_address, created_at FROM admin_audit_logs ORDER BY log_id;
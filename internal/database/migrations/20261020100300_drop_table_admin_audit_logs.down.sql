@@ -0,0 +1,12 @@
+CREATE TABLE admin_audit_logs (
+    log_id BIGINT NOT NULL AUTO_INCREMENT,
+    actor_id BIGINT NOT NULL,
+    action VARCHAR(55) NOT NULL,
+    target_user_id BIGINT NULL DEFAULT NULL,
+    details JSON NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (log_id),
+    KEY idx_admin_audit_logs_actor (actor_id),
+    KEY idx_admin_audit_logs_target (target_user_id)
+);
@@ -0,0 +1 @@
+UPDATE todos JOIN (SELECT MIN(user_id) AS user_id FROM users) owner JOIN lists ON lists.user_id = owner.user_id AND lists.is_inbox = TRUE SET todos.user_id = owner.user_id, todos.list_id = COALESCE(todos.list_id, lists.list_id) WHERE todos.user_id IS NULL;
@@ -0,0 +1 @@
+DELETE FROM todos WHERE user_id IS NULL;
@@ -0,0 +1 @@
+ALTER TABLE todos MODIFY user_id BIGINT NULL DEFAULT NULL;
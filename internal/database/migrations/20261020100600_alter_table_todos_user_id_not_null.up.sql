@@ -0,0 +1 @@
+ALTER TABLE todos MODIFY user_id BIGINT NOT NULL;
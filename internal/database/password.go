@@ -0,0 +1,79 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) CreatePasswordResetToken(token *entity.PasswordResetTokens) error {
+	return repository.DB.Create(token).Error
+}
+
+func (repository *TodoRepository) FindPasswordResetToken(tokenHash string) (*entity.PasswordResetTokens, error) {
+	var token entity.PasswordResetTokens
+	result := repository.DB.Where("token_hash = ?", tokenHash).First(&token)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &token, result.Error
+}
+
+// ResetPassword redeems the reset token and stores the new password hash.
+func (repository *TodoRepository) ResetPassword(token *entity.PasswordResetTokens, passwordHash string) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.PasswordResetTokens{}).
+			Where("token_id = ? AND used_at IS NULL", token.TokenID).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTokenAlreadyUsed
+		}
+
+		if err := updatePassword(tx, token.UserID, passwordHash); err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND token_id <> ?", token.UserID, token.TokenID).
+			Delete(&entity.PasswordResetTokens{}).Error
+	})
+}
+
+// UpdatePassword stores the new password hash and returns the user with its
+// bumped token version.
+func (repository *TodoRepository) UpdatePassword(userID int64, passwordHash string) (*entity.Users, error) {
+	var user entity.Users
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := updatePassword(tx, userID, passwordHash); err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).First(&user).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// updatePassword also bumps token_version and revokes the API keys, so
+// neither a token nor a key issued with the old password is accepted.
+func updatePassword(tx *gorm.DB, userID int64, passwordHash string) error {
+	err := tx.Model(&entity.Users{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"password":      passwordHash,
+			"token_version": gorm.Expr("token_version + 1"),
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	return revokeAPIKeys(tx, userID)
+}
@@ -0,0 +1,20 @@
+package database
+
+import (
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+// CreateNextOccurrence stores the next todo of a series, carrying over the
+// tags of the previous one.
+func (repository *TodoRepository) CreateNextOccurrence(next *entity.Todos, previousID int64) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		txRepository := &TodoRepository{DB: tx}
+		if err := txRepository.Create(next); err != nil {
+			return err
+		}
+
+		return tx.Exec("INSERT INTO todo_tags (todos_id, tag_id) SELECT ?, tag_id FROM todo_tags WHERE todos_id = ?", next.Id, previousID).Error
+	})
+}
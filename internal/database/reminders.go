@@ -0,0 +1,45 @@
+package database
+
+import (
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+// ListDueReminders returns open todos whose reminder time has passed and
+// that haven't been reminded yet, oldest first. A reminder whose delivery
+// failed waits for its retry time, so it doesn't hold up the others.
+func (repository *TodoRepository) ListDueReminders(now time.Time, limit int) ([]entity.Todos, error) {
+	var todos []entity.Todos
+	result := repository.DB.
+		Where("reminded_at IS NULL AND remind_at <= ? AND status NOT IN ?", now, closedStatuses).
+		Where("reminder_retry_at IS NULL OR reminder_retry_at <= ?", now).
+		Order("COALESCE(reminder_retry_at, remind_at)").
+		Limit(limit).
+		Find(&todos)
+
+	return todos, result.Error
+}
+
+// ClaimReminder marks the reminder as sent before it is delivered. Only one
+// caller can win the update, so a reminder is never fired twice, not even by
+// two servers or after a restart. remindAt guards against the reminder being
+// moved since it was listed.
+func (repository *TodoRepository) ClaimReminder(todoID int64, remindAt time.Time) (bool, error) {
+	result := repository.DB.Model(&entity.Todos{}).
+		Where("todos_id = ? AND remind_at = ? AND reminded_at IS NULL", todoID, remindAt).
+		Update("reminded_at", time.Now())
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReleaseReminder undoes a claim whose delivery failed, so it is retried at
+// retryAt. The failed attempt is counted.
+func (repository *TodoRepository) ReleaseReminder(todoID int64, retryAt time.Time) error {
+	return repository.DB.Model(&entity.Todos{}).Where("todos_id = ?", todoID).Updates(map[string]interface{}{
+		"reminded_at":       nil,
+		"reminder_attempts": gorm.Expr("reminder_attempts + 1"),
+		"reminder_retry_at": retryAt,
+	}).Error
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"strings"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/search"
+
+	"gorm.io/gorm"
+)
+
+const defaultSearchLimit = 20
+
+// SearchTodos finds the todos whose title or description contain every word
+// of the search, best matches first. MySQL uses the full-text index, other
+// databases fall back to LIKE.
+func (repository *TodoRepository) SearchTodos(query *dto.SearchQuery) ([]entity.TodoMatch, error) {
+	terms := search.Terms(query.Q)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	db := filterTodos(repository.DB, &query.TodoQuery).Table("todos")
+	if repository.DB.Dialector.Name() == "mysql" {
+		against := search.BooleanQuery(terms)
+		db = db.Select("todos.*, MATCH(title, description) AGAINST (? IN BOOLEAN MODE) AS score", against).
+			Where("MATCH(title, description) AGAINST (? IN BOOLEAN MODE)", against)
+	} else {
+		db = likeSearch(db, terms)
+	}
+
+	var matches []entity.TodoMatch
+	if err := db.Order("score DESC, todos_id").Limit(limit).Find(&matches).Error; err != nil {
+		return nil, err
+	}
+
+	todos := make([]entity.Todos, len(matches))
+	for i := range matches {
+		todos[i] = matches[i].Todos
+	}
+	if err := repository.loadTagNames(todos); err != nil {
+		return nil, err
+	}
+	for i := range matches {
+		matches[i].Tags = todos[i].Tags
+	}
+
+	return matches, nil
+}
+
+// likeSearch needs every term in the title or description, a term found in
+// the title scores higher than one in the description.
+func likeSearch(db *gorm.DB, terms []string) *gorm.DB {
+	scores := make([]string, len(terms))
+	var args []interface{}
+	for i, term := range terms {
+		pattern := "%" + escapeLike(term) + "%"
+		db = db.Where(`(LOWER(title) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\')`, pattern, pattern)
+		scores[i] = `(CASE WHEN LOWER(title) LIKE ? ESCAPE '\' THEN 2 ELSE 0 END + CASE WHEN LOWER(description) LIKE ? ESCAPE '\' THEN 1 ELSE 0 END)`
+		args = append(args, pattern, pattern)
+	}
+
+	return db.Select("todos.*, "+strings.Join(scores, " + ")+" AS score", args...)
+}
+
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+}
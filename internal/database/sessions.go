@@ -0,0 +1,48 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) CreateSession(session *entity.Sessions) error {
+	return repository.DB.Create(session).Error
+}
+
+func (repository *TodoRepository) FindSessionByJTI(jti string) (*entity.Sessions, error) {
+	var session entity.Sessions
+	result := repository.DB.Where("jti = ?", jti).First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &session, result.Error
+}
+
+// ListSessions returns the sessions that can still be used: not revoked, not
+// expired and signed with the user's current token version.
+func (repository *TodoRepository) ListSessions(userID int64) ([]entity.Sessions, error) {
+	var sessions []entity.Sessions
+	result := repository.DB.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Where("token_version = (?)", repository.DB.Model(&entity.Users{}).Select("token_version").Where("user_id = ?", userID)).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+
+	return sessions, result.Error
+}
+
+func (repository *TodoRepository) RevokeSession(userID int64, sessionID int64) (int64, error) {
+	result := repository.DB.Model(&entity.Sessions{}).
+		Where("session_id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", time.Now())
+
+	return result.RowsAffected, result.Error
+}
+
+func (repository *TodoRepository) TouchSession(sessionID int64) error {
+	return repository.DB.Model(&entity.Sessions{}).Where("session_id = ?", sessionID).Update("last_seen_at", time.Now()).Error
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"errors"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+// SaveShare shares a todo or list with a user, changing the role when it is
+// already shared with them.
+func (repository *TodoRepository) SaveShare(share *entity.Shares) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		var existing entity.Shares
+		result := shareTarget(tx.Where("user_id = ?", share.UserID), share.TodoID, share.ListID).Limit(1).Find(&existing)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return tx.Create(share).Error
+		}
+
+		if err := tx.Model(&existing).Update("role", share.Role).Error; err != nil {
+			return err
+		}
+
+		existing.Role = share.Role
+		*share = existing
+		return nil
+	})
+}
+
+func (repository *TodoRepository) FindShare(shareID int64) (*entity.Shares, error) {
+	var share entity.Shares
+	result := repository.DB.Where("share_id = ?", shareID).First(&share)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &share, result.Error
+}
+
+// ListShares returns who a todo or list is shared with.
+func (repository *TodoRepository) ListShares(todoID *int64, listID *int64) ([]entity.Shares, error) {
+	var shares []entity.Shares
+	db := repository.DB.Table("shares").
+		Select("shares.*, users.email AS user_email").
+		Joins("JOIN users ON users.user_id = shares.user_id")
+	result := shareTarget(db, todoID, listID).Order("shares.share_id").Find(&shares)
+
+	return shares, result.Error
+}
+
+// ListSharedWithUser returns everything shared with the user, with the todo
+// or list itself and the owner's email.
+func (repository *TodoRepository) ListSharedWithUser(userID int64) ([]entity.Shares, error) {
+	var shares []entity.Shares
+	result := repository.DB.Table("shares").
+		Select("shares.*, users.email AS owner_email").
+		Joins("JOIN users ON users.user_id = shares.owner_id").
+		Where("shares.user_id = ?", userID).
+		Preload("Todo").
+		Preload("List").
+		Order("shares.share_id").
+		Find(&shares)
+
+	return shares, result.Error
+}
+
+func (repository *TodoRepository) DeleteShare(shareID int64) (int64, error) {
+	result := repository.DB.Delete(&entity.Shares{}, shareID)
+
+	return result.RowsAffected, result.Error
+}
+
+// FindTodoForUser returns the todo together with the role userID has on it,
+// owner of their own todos. The todo is nil when it doesn't exist or isn't
+// shared with the user, so they can't tell the two apart.
+func (repository *TodoRepository) FindTodoForUser(userID int64, todoID int64) (*entity.Todos, string, error) {
+	todo, err := repository.GetID(todoID)
+	if err != nil || todo == nil {
+		return nil, "", err
+	}
+
+	if todo.UserID == userID {
+		return todo, entity.ShareOwner, nil
+	}
+
+	role, err := repository.FindTodoShareRole(userID, todo)
+	if err != nil || role == "" {
+		return nil, "", err
+	}
+
+	return todo, role, nil
+}
+
+// FindTodoShareRole returns the best role the user was given on the todo,
+// through a share of the todo, of one of its parents or of its list. It is
+// empty when the todo isn't shared with the user.
+func (repository *TodoRepository) FindTodoShareRole(userID int64, todo *entity.Todos) (string, error) {
+	todoIDs := []int64{todo.Id}
+	for parentID := todo.ParentID; parentID != nil && len(todoIDs) < entity.MaxSubtaskDepth; {
+		var parent entity.Todos
+		result := repository.DB.Select("todos_id", "parent_id").Where("todos_id = ?", *parentID).Limit(1).Find(&parent)
+		if result.Error != nil {
+			return "", result.Error
+		}
+		if result.RowsAffected == 0 {
+			break
+		}
+
+		todoIDs = append(todoIDs, parent.Id)
+		parentID = parent.ParentID
+	}
+
+	db := repository.DB.Where("todos_id IN ?", todoIDs)
+	if todo.ListID != nil {
+		db = db.Or("list_id = ?", *todo.ListID)
+	}
+
+	var roles []string
+	err := repository.DB.Model(&entity.Shares{}).Where("user_id = ?", userID).Where(db).Pluck("role", &roles).Error
+	if err != nil {
+		return "", err
+	}
+
+	best := ""
+	for _, role := range roles {
+		if entity.ShareRoleAllows(role, best) {
+			best = role
+		}
+	}
+
+	return best, nil
+}
+
+// FindSharedList returns a list someone shared with the user, together with
+// the user's role on it.
+func (repository *TodoRepository) FindSharedList(userID int64, listID int64) (*entity.Lists, string, error) {
+	var share entity.Shares
+	result := repository.DB.Where("user_id = ? AND list_id = ?", userID, listID).Preload("List").Limit(1).Find(&share)
+	if result.Error != nil || result.RowsAffected == 0 || share.List == nil {
+		return nil, "", result.Error
+	}
+
+	return share.List, share.Role, nil
+}
+
+// sharedWith restricts a todo query to the todos the user owns or was given
+// through a share of the todo or its list.
+func sharedWith(db *gorm.DB, viewerID int64) *gorm.DB {
+	sharedLists := db.Session(&gorm.Session{NewDB: true}).Model(&entity.Shares{}).Select("list_id").Where("user_id = ? AND list_id IS NOT NULL", viewerID)
+	sharedTodos := db.Session(&gorm.Session{NewDB: true}).Model(&entity.Shares{}).Select("todos_id").Where("user_id = ? AND todos_id IS NOT NULL", viewerID)
+
+	return db.Where("user_id = ? OR list_id IN (?) OR todos_id IN (?)", viewerID, sharedLists, sharedTodos)
+}
+
+func shareTarget(db *gorm.DB, todoID *int64, listID *int64) *gorm.DB {
+	if todoID != nil {
+		return db.Where("shares.todos_id = ?", *todoID)
+	}
+
+	return db.Where("shares.list_id = ?", *listID)
+}
@@ -0,0 +1,113 @@
+package database
+
+import (
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) GetSubtasks(parentID int64) ([]entity.Todos, error) {
+	var todos []entity.Todos
+	if err := repository.DB.Where("parent_id = ?", parentID).Order("subtask_order, todos_id").Find(&todos).Error; err != nil {
+		return nil, err
+	}
+
+	return todos, repository.loadTagNames(todos)
+}
+
+// SubtaskProgress counts the direct subtasks of a todo and how many are done.
+func (repository *TodoRepository) SubtaskProgress(parentID int64) (entity.SubtaskProgress, error) {
+	var progress entity.SubtaskProgress
+	result := repository.DB.Model(&entity.Todos{}).
+		Select("COUNT(*) AS total, COALESCE(SUM(status = ?), 0) AS done", entity.StatusDone).
+		Where("parent_id = ? AND status <> ?", parentID, entity.StatusCancelled).
+		Scan(&progress)
+
+	return progress, result.Error
+}
+
+// NextSubtaskOrder returns the order that puts a new subtask after its siblings.
+func (repository *TodoRepository) NextSubtaskOrder(parentID int64) (int, error) {
+	var order int
+	result := repository.DB.Model(&entity.Todos{}).
+		Select("COALESCE(MAX(subtask_order), -1) + 1").
+		Where("parent_id = ?", parentID).
+		Scan(&order)
+
+	return order, result.Error
+}
+
+// ReorderSubtasks stores the position of each id in order as its subtask_order.
+func (repository *TodoRepository) ReorderSubtasks(parentID int64, order []int64) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		for position, todoID := range order {
+			result := tx.Model(&entity.Todos{}).
+				Where("todos_id = ? AND parent_id = ?", todoID, parentID).
+				Update("subtask_order", position)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+
+		return nil
+	})
+}
+
+// CloseSubtasks gives every open subtask below parentID, at any depth, the
+// closing status of its parent.
+func (repository *TodoRepository) CloseSubtasks(parentID int64, status string, now time.Time) error {
+	updates := map[string]interface{}{"status": status}
+	if status == entity.StatusDone {
+		updates["completed_at"] = now
+	}
+
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		parents := []int64{parentID}
+		for depth := 1; depth < entity.MaxSubtaskDepth && len(parents) > 0; depth++ {
+			var children []int64
+			if err := tx.Model(&entity.Todos{}).Where("parent_id IN ?", parents).Pluck("todos_id", &children).Error; err != nil {
+				return err
+			}
+			if len(children) == 0 {
+				break
+			}
+
+			err := tx.Model(&entity.Todos{}).
+				Where("todos_id IN ? AND status NOT IN ?", children, closedStatuses).
+				Updates(updates).Error
+			if err != nil {
+				return err
+			}
+
+			parents = children
+		}
+
+		return nil
+	})
+}
+
+// MoveSubtasks puts every subtask below parentID, at any depth, in listID,
+// subtasks live in their parent's list.
+func (repository *TodoRepository) MoveSubtasks(parentID int64, listID *int64) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		parents := []int64{parentID}
+		for depth := 1; depth < entity.MaxSubtaskDepth && len(parents) > 0; depth++ {
+			var children []int64
+			if err := tx.Model(&entity.Todos{}).Where("parent_id IN ?", parents).Pluck("todos_id", &children).Error; err != nil {
+				return err
+			}
+			if len(children) == 0 {
+				break
+			}
+
+			if err := tx.Model(&entity.Todos{}).Where("todos_id IN ?", children).Update("list_id", listID).Error; err != nil {
+				return err
+			}
+
+			parents = children
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"errors"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mysqlDuplicateEntry is the MySQL error number for a unique key violation
+const mysqlDuplicateEntry = 1062
+
+func (repository *TodoRepository) CreateTag(tag *entity.Tags) error {
+	err := repository.DB.Create(tag).Error
+	if isDuplicateEntry(err) {
+		return errDuplicateTag
+	}
+
+	return err
+}
+
+func (repository *TodoRepository) GetTags(userID int64) ([]entity.Tags, error) {
+	var tags []entity.Tags
+	result := repository.DB.Where("user_id = ?", userID).Order("name").Find(&tags)
+
+	return tags, result.Error
+}
+
+func (repository *TodoRepository) FindTag(userID int64, tagID int64) (*entity.Tags, error) {
+	var tag entity.Tags
+	result := repository.DB.Where("tag_id = ? AND user_id = ?", tagID, userID).First(&tag)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &tag, result.Error
+}
+
+func (repository *TodoRepository) RenameTag(tag *entity.Tags, name string) error {
+	err := repository.DB.Model(tag).Update("name", name).Error
+	if isDuplicateEntry(err) {
+		return errDuplicateTag
+	}
+
+	return err
+}
+
+// DeleteTag removes the tag, the database drops it from every todo.
+func (repository *TodoRepository) DeleteTag(tagID int64) error {
+	return repository.DB.Delete(&entity.Tags{}, tagID).Error
+}
+
+// AttachTag tags a todo, tagging it twice is not an error.
+func (repository *TodoRepository) AttachTag(todoID int64, tagID int64) error {
+	return repository.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.TodoTags{TodoID: todoID, TagID: tagID}).Error
+}
+
+func (repository *TodoRepository) DetachTag(todoID int64, tagID int64) (int64, error) {
+	result := repository.DB.Where("todos_id = ? AND tag_id = ?", todoID, tagID).Delete(&entity.TodoTags{})
+
+	return result.RowsAffected, result.Error
+}
+
+// loadTagNames fills in the tag names of the todos with a single query.
+func (repository *TodoRepository) loadTagNames(todos []entity.Todos) error {
+	if len(todos) == 0 {
+		return nil
+	}
+
+	todoIDs := make([]int64, len(todos))
+	for i, todo := range todos {
+		todoIDs[i] = todo.Id
+	}
+
+	var rows []struct {
+		TodosID int64
+		Name    string
+	}
+	err := repository.DB.Table("todo_tags").
+		Select("todo_tags.todos_id, tags.name").
+		Joins("JOIN tags ON tags.tag_id = todo_tags.tag_id").
+		Where("todo_tags.todos_id IN ?", todoIDs).
+		Order("tags.name").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	names := make(map[int64][]string, len(todos))
+	for _, row := range rows {
+		names[row.TodosID] = append(names[row.TodosID], row.Name)
+	}
+
+	for i := range todos {
+		todos[i].Tags = names[todos[i].Id]
+		if todos[i].Tags == nil {
+			todos[i].Tags = []string{}
+		}
+	}
+
+	return nil
+}
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry
+}
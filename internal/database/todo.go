@@ -2,7 +2,10 @@ package database
 
 import (
 	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
 	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/rank"
 	"todolist_gin_gorm/internal/repository"
 
 	"gorm.io/gorm"
@@ -10,6 +13,9 @@ import (
 
 //adaptop pattern
 
+// closedStatuses are the statuses no reminder or overdue check applies to
+var closedStatuses = []string{entity.StatusDone, entity.StatusCancelled}
+
 type TodoRepository struct {
 	DB *gorm.DB
 }
@@ -20,21 +26,160 @@ func NewTodoRepository(DB *gorm.DB) repository.Repository {
 	}
 }
 
-func (repository *TodoRepository) Create(title string, description string) (*entity.Todos, error) {
-	todos := entity.Todos{
-		Title:       title,
-		Description: description,
+func (repository *TodoRepository) Transaction(fn func(repo repository.Repository) error) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&TodoRepository{DB: tx})
+	})
+}
+
+// Create stores the todo, in its owner's inbox when no list was chosen. A
+// top level todo goes to the end of its list.
+func (repository *TodoRepository) Create(todo *entity.Todos) error {
+	if todo.ListID == nil && todo.UserID != 0 {
+		var inbox entity.Lists
+		result := repository.DB.Where("user_id = ? AND is_inbox = ?", todo.UserID, true).Limit(1).Find(&inbox)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 1 {
+			todo.ListID = &inbox.ListID
+		}
 	}
 
-	result := repository.DB.Create(&todos)
-	return &todos, result.Error
+	if todo.ParentID == nil && todo.Position == "" {
+		last, err := repository.LastPosition(todo.ListID)
+		if err != nil {
+			return err
+		}
+
+		position, err := rank.Between(last, "")
+		if err != nil {
+			return err
+		}
+		todo.Position = position
+	}
+
+	err := repository.DB.Create(todo).Error
+	if isDuplicateEntry(err) {
+		return errDuplicateTodo
+	}
+
+	return err
 }
 
-func (repository *TodoRepository) GetAll() ([]entity.Todos, error) {
+// LastPosition returns the position of the last top level todo of a list,
+// empty when the list has none.
+func (repository *TodoRepository) LastPosition(listID *int64) (string, error) {
+	var position string
+	err := inList(repository.DB.Model(&entity.Todos{}), listID).
+		Where("parent_id IS NULL").
+		Select("COALESCE(MAX(position), '')").
+		Scan(&position).Error
+
+	return position, err
+}
+
+// AdjacentPosition returns the position of the top level todo right after
+// (or before) todo in its list, empty when todo is at that end.
+func (repository *TodoRepository) AdjacentPosition(todo *entity.Todos, after bool) (string, error) {
+	db := inList(repository.DB.Model(&entity.Todos{}), todo.ListID).Where("parent_id IS NULL")
+	if after {
+		db = db.Select("COALESCE(MIN(position), '')").Where("position > ?", todo.Position)
+	} else {
+		db = db.Select("COALESCE(MAX(position), '')").Where("position < ?", todo.Position)
+	}
+
+	var position string
+	err := db.Scan(&position).Error
+
+	return position, err
+}
+
+func inList(db *gorm.DB, listID *int64) *gorm.DB {
+	if listID == nil {
+		return db.Where("list_id IS NULL")
+	}
+
+	return db.Where("list_id = ?", *listID)
+}
+
+func (repository *TodoRepository) GetAll(query *dto.TodoQuery) ([]entity.Todos, error) {
 	var todos []entity.Todos
-	result := repository.DB.Find(&todos)
+	// subtasks are listed under their parent
+	db := filterTodos(repository.DB, query).Where("parent_id IS NULL")
+
+	if err := db.Order(todoOrder(query.Sort)).Find(&todos).Error; err != nil {
+		return nil, err
+	}
 
-	return todos, result.Error
+	if err := repository.loadTagNames(todos); err != nil {
+		return nil, err
+	}
+
+	return todos, repository.loadCommentCounts(todos)
+}
+
+// filterTodos applies the filters of the query to the todos the viewer can see.
+func filterTodos(base *gorm.DB, query *dto.TodoQuery) *gorm.DB {
+	db := sharedWith(base, query.ViewerID)
+
+	if !query.DueBefore.IsZero() {
+		db = db.Where("due_at < ?", query.DueBefore)
+	}
+	if !query.DueAfter.IsZero() {
+		db = db.Where("due_at > ?", query.DueAfter)
+	}
+	if query.Overdue {
+		db = db.Where("status NOT IN ? AND due_at < ?", closedStatuses, time.Now())
+	}
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.Priority != "" {
+		db = db.Where("priority = ?", query.Priority)
+	}
+
+	if query.ListID != 0 {
+		db = db.Where("list_id = ?", query.ListID)
+	}
+	if names := query.TagNames(); len(names) > 0 {
+		db = db.Where("todos_id IN (?)", taggedTodos(base, query.ViewerID, names, query.TagMode == dto.TagModeAll))
+	}
+
+	return db
+}
+
+// taggedTodos selects the ids of todos carrying any of the viewer's tags with
+// the names, or all of them when matchAll is set. Only the viewer's own tags
+// count, a todo shared with them doesn't match through a tag of its owner
+// that happens to have the same name.
+func taggedTodos(db *gorm.DB, viewerID int64, names []string, matchAll bool) *gorm.DB {
+	subquery := db.Table("todo_tags").
+		Select("todo_tags.todos_id").
+		Joins("JOIN tags ON tags.tag_id = todo_tags.tag_id").
+		Where("tags.user_id = ? AND tags.name IN ?", viewerID, names)
+	if matchAll {
+		subquery = subquery.Group("todo_tags.todos_id").Having("COUNT(DISTINCT tags.name) = ?", len(names))
+	}
+
+	return subquery
+}
+
+// todoOrder turns a sort name into an ORDER BY clause, the manual position
+// being the default. Todos without a due date come last when sorting by it.
+func todoOrder(sort string) string {
+	switch sort {
+	case entity.SortDueAt:
+		return "due_at IS NULL, due_at, todos_id"
+	case entity.SortPriority:
+		return "FIELD(priority, 'urgent', 'high', 'medium', 'low'), todos_id"
+	case entity.SortTitle:
+		return "title, todos_id"
+	case entity.SortCreated:
+		return "todos_id"
+	default:
+		return "position, todos_id"
+	}
 }
 
 func (repository *TodoRepository) GetID(todoID int64) (*entity.Todos, error) {
@@ -43,8 +188,16 @@ func (repository *TodoRepository) GetID(todoID int64) (*entity.Todos, error) {
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
 
-	return &todos, result.Error
+	found := []entity.Todos{todos}
+	if err := repository.loadTagNames(found); err != nil {
+		return nil, err
+	}
+
+	return &found[0], nil
 }
 
 func (repository *TodoRepository) Update(todoID int64, updates map[string]interface{}) (*entity.Todos, error) {
@@ -67,19 +220,22 @@ func (repository *TodoRepository) Delete(todoID int64) (int64, error) {
 	return result.RowsAffected, result.Error
 }
 
+// CreateUser creates the account together with its inbox list.
 func (repository *TodoRepository) CreateUser(user *entity.Users) error {
-	if err := repository.DB.Create(user).Error; err != nil {
-		return err
-	}
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
 
-	return nil
+		return tx.Create(entity.NewInboxList(user.UserID)).Error
+	})
 }
 
 func (repository *TodoRepository) FindUserByEmail(email string) (*entity.Users, error) {
 	var user entity.Users
 	if err := repository.DB.Where("email = ? ", email).First(&user).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, gorm.ErrRecordNotFound
+			return nil, nil
 		}
 		return nil, err
 	}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+// SetTOTPSecret stores a pending secret, 2FA stays off until EnableTwoFactor.
+func (repository *TodoRepository) SetTOTPSecret(userID int64, secret string) error {
+	return repository.DB.Model(&entity.Users{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+		"totp_secret":           secret,
+		"totp_last_step":        0,
+		"two_factor_enabled_at": nil,
+	}).Error
+}
+
+// EnableTwoFactor turns 2FA on and replaces the user's recovery codes.
+func (repository *TodoRepository) EnableTwoFactor(userID int64, recoveryCodeHashes []string) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Users{}).Where("user_id = ?", userID).Update("two_factor_enabled_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCodes{}).Error; err != nil {
+			return err
+		}
+
+		codes := make([]entity.RecoveryCodes, 0, len(recoveryCodeHashes))
+		for _, hash := range recoveryCodeHashes {
+			codes = append(codes, entity.RecoveryCodes{UserID: userID, CodeHash: hash})
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+func (repository *TodoRepository) DisableTwoFactor(userID int64) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Users{}).Where("user_id = ?", userID).Updates(map[string]interface{}{
+			"totp_secret":           nil,
+			"totp_last_step":        0,
+			"two_factor_enabled_at": nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&entity.RecoveryCodes{}).Error
+	})
+}
+
+// AdvanceTOTPStep records the time step of an accepted code. It returns false
+// when that step (or a later one) was already used, i.e. the code is replayed.
+func (repository *TodoRepository) AdvanceTOTPStep(userID int64, step int64) (bool, error) {
+	result := repository.DB.Model(&entity.Users{}).
+		Where("user_id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseRecoveryCode burns a recovery code, false means it is unknown or used.
+func (repository *TodoRepository) UseRecoveryCode(userID int64, codeHash string) (bool, error) {
+	result := repository.DB.Model(&entity.RecoveryCodes{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// CreateTwoFactorChallenge stores a challenge, dropping the user's expired
+// ones on the way.
+func (repository *TodoRepository) CreateTwoFactorChallenge(challenge *entity.TwoFactorChallenges) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND expires_at < ?", challenge.UserID, time.Now()).Delete(&entity.TwoFactorChallenges{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(challenge).Error
+	})
+}
+
+// ClaimTwoFactorAttempt counts an attempt to answer the challenge. It returns
+// false when the challenge is unknown, used, expired or had maxAttempts
+// attempts already.
+func (repository *TodoRepository) ClaimTwoFactorAttempt(jti string, maxAttempts int) (bool, error) {
+	result := repository.DB.Model(&entity.TwoFactorChallenges{}).
+		Where("jti = ? AND used_at IS NULL AND expires_at > ? AND attempts < ?", jti, time.Now(), maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// ConsumeTwoFactorChallenge uses the challenge up, false means it already was.
+func (repository *TodoRepository) ConsumeTwoFactorChallenge(jti string) (bool, error) {
+	result := repository.DB.Model(&entity.TwoFactorChallenges{}).
+		Where("jti = ? AND used_at IS NULL", jti).
+		Update("used_at", time.Now())
+
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,191 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateUndoOperation stores an operation, dropping the user's expired ones
+// on the way.
+func (repository *TodoRepository) CreateUndoOperation(operation *entity.UndoOperations) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND expires_at < ?", operation.UserID, time.Now()).Delete(&entity.UndoOperations{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(operation).Error
+	})
+}
+
+func (repository *TodoRepository) FindUndoOperation(userID int64, operationID int64) (*entity.UndoOperations, error) {
+	var operation entity.UndoOperations
+	result := repository.DB.Where("operation_id = ? AND user_id = ?", operationID, userID).First(&operation)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &operation, result.Error
+}
+
+// ClaimUndoOperation marks an operation undone, it reports false when it
+// already was.
+func (repository *TodoRepository) ClaimUndoOperation(operationID int64, now time.Time) (bool, error) {
+	result := repository.DB.Model(&entity.UndoOperations{}).
+		Where("operation_id = ? AND undone_at IS NULL", operationID).
+		Update("undone_at", now)
+
+	return result.RowsAffected == 1, result.Error
+}
+
+// TodoSnapshot returns a todo with all its subtasks, their tags, comments
+// and shares, as a delete would lose them. It is nil when there is no such
+// todo.
+func (repository *TodoRepository) TodoSnapshot(todoID int64) (*entity.UndoSnapshot, error) {
+	snapshot := &entity.UndoSnapshot{}
+	var todoIDs []int64
+
+	level := repository.DB.Where("todos_id = ?", todoID)
+	for {
+		var todos []entity.Todos
+		if err := level.Order("todos_id").Find(&todos).Error; err != nil {
+			return nil, err
+		}
+		if len(todos) == 0 {
+			break
+		}
+
+		parentIDs := make([]int64, len(todos))
+		for i := range todos {
+			parentIDs[i] = todos[i].Id
+		}
+		snapshot.Todos = append(snapshot.Todos, todos...)
+		todoIDs = append(todoIDs, parentIDs...)
+		level = repository.DB.Where("parent_id IN ?", parentIDs)
+	}
+
+	if len(todoIDs) == 0 {
+		return nil, nil
+	}
+
+	if err := repository.DB.Where("todos_id IN ?", todoIDs).Find(&snapshot.TodoTags).Error; err != nil {
+		return nil, err
+	}
+
+	if err := repository.DB.Unscoped().Where("todos_id IN ?", todoIDs).Order("comment_id").Find(&snapshot.Comments).Error; err != nil {
+		return nil, err
+	}
+
+	err := repository.DB.Where("todos_id IN ?", todoIDs).Order("share_id").Find(&snapshot.Shares).Error
+
+	return snapshot, err
+}
+
+// RestoreTodos writes the todos of a snapshot back as they were, with their
+// tags, comments and shares. Todos of a list deleted since go to the owner's
+// inbox, links to tags deleted since and shares with users deleted since are
+// left out.
+func (repository *TodoRepository) RestoreTodos(snapshot *entity.UndoSnapshot) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range snapshot.Todos {
+			todo := snapshot.Todos[i]
+			if todo.ListID != nil {
+				var lists int64
+				if err := tx.Model(&entity.Lists{}).Where("list_id = ?", *todo.ListID).Count(&lists).Error; err != nil {
+					return err
+				}
+
+				if lists == 0 {
+					var inbox entity.Lists
+					if err := tx.Where("user_id = ? AND is_inbox = ?", todo.UserID, true).First(&inbox).Error; err != nil {
+						return err
+					}
+					todo.ListID = &inbox.ListID
+				}
+			}
+
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&todo).Error; err != nil {
+				return err
+			}
+
+			// a zero value of a column with a default is left out of the insert,
+			// so the reminder state is written on its own
+			err := tx.Model(&entity.Todos{}).Where("todos_id = ?", todo.Id).Updates(map[string]interface{}{
+				"reminder_attempts": todo.ReminderAttempts,
+				"reminder_retry_at": todo.ReminderRetryAt,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(snapshot.TodoTags) > 0 {
+			tagIDs := make([]int64, len(snapshot.TodoTags))
+			for i, link := range snapshot.TodoTags {
+				tagIDs[i] = link.TagID
+			}
+
+			var existing []int64
+			if err := tx.Model(&entity.Tags{}).Where("tag_id IN ?", tagIDs).Pluck("tag_id", &existing).Error; err != nil {
+				return err
+			}
+
+			kept := make(map[int64]bool, len(existing))
+			for _, tagID := range existing {
+				kept[tagID] = true
+			}
+
+			var links []entity.TodoTags
+			for _, link := range snapshot.TodoTags {
+				if kept[link.TagID] {
+					links = append(links, link)
+				}
+			}
+
+			if len(links) > 0 {
+				if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(snapshot.Comments) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&snapshot.Comments).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(snapshot.Shares) > 0 {
+			userIDs := make([]int64, len(snapshot.Shares))
+			for i, share := range snapshot.Shares {
+				userIDs[i] = share.UserID
+			}
+
+			var existing []int64
+			if err := tx.Model(&entity.Users{}).Where("user_id IN ?", userIDs).Pluck("user_id", &existing).Error; err != nil {
+				return err
+			}
+
+			kept := make(map[int64]bool, len(existing))
+			for _, userID := range existing {
+				kept[userID] = true
+			}
+
+			var shares []entity.Shares
+			for _, share := range snapshot.Shares {
+				if kept[share.UserID] {
+					shares = append(shares, share)
+				}
+			}
+
+			if len(shares) > 0 {
+				return tx.Clauses(clause.OnConflict{DoNothing: true}).Omit(clause.Associations).Create(&shares).Error
+			}
+		}
+
+		return nil
+	})
+}
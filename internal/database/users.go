@@ -0,0 +1,48 @@
+package database
+
+import (
+	"errors"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) FindUserByID(userID int64) (*entity.Users, error) {
+	var user entity.Users
+	result := repository.DB.Where("user_id = ?", userID).First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &user, result.Error
+}
+
+func (repository *TodoRepository) UpdateUser(userID int64, updates map[string]interface{}) (*entity.Users, error) {
+	var user entity.Users
+	err := repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.Users{}).Where("user_id = ?", userID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).First(&user).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// DeleteUser removes the account together with every todo it owns.
+func (repository *TodoRepository) DeleteUser(userID int64) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entity.Todos{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userID).Delete(&entity.Users{}).Error
+	})
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"gorm.io/gorm"
+)
+
+func (repository *TodoRepository) CreateEmailVerificationToken(token *entity.EmailVerificationTokens) error {
+	return repository.DB.Create(token).Error
+}
+
+func (repository *TodoRepository) FindEmailVerificationToken(tokenHash string) (*entity.EmailVerificationTokens, error) {
+	var token entity.EmailVerificationTokens
+	result := repository.DB.Where("token_hash = ?", tokenHash).First(&token)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &token, result.Error
+}
+
+// VerifyEmail marks the token as used, confirms the address it was issued for
+// and discards the user's other outstanding tokens.
+func (repository *TodoRepository) VerifyEmail(token *entity.EmailVerificationTokens) error {
+	return repository.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&entity.EmailVerificationTokens{}).
+			Where("token_id = ? AND used_at IS NULL", token.TokenID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errTokenAlreadyUsed
+		}
+
+		err := tx.Model(&entity.Users{}).
+			Where("user_id = ?", token.UserID).
+			Updates(map[string]interface{}{
+				"email":             token.Email,
+				"email_verified_at": now,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ? AND token_id <> ?", token.UserID, token.TokenID).
+			Delete(&entity.EmailVerificationTokens{}).Error
+	})
+}
@@ -0,0 +1,190 @@
+// Package export writes todos as CSV, JSON or iCalendar one at a time, so
+// an export can be streamed without holding every todo in memory.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+	FormatICS  = "ics"
+)
+
+// Writer writes todos in one format. Nothing is written before the first
+// todo or Close, Close ends the document and flushes it.
+type Writer interface {
+	Write(todo *entity.Todos) error
+	Flush() error
+	Close() error
+}
+
+// New returns the writer of format, now stamps iCalendar entries.
+func New(format string, w io.Writer, now time.Time) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSV(w), nil
+	case FormatJSON:
+		return NewJSON(w), nil
+	case FormatICS:
+		return NewICS(w, now), nil
+	default:
+		return nil, fmt.Errorf("export: unknown format %q", format)
+	}
+}
+
+// ContentType is the media type of a document in format.
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatICS:
+		return "text/calendar; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// CSVHeader names the columns of a CSV export, tags are joined by commas in
+// one column.
+var CSVHeader = []string{
+	"id", "title", "description", "status", "priority", "list_id", "parent_id",
+	"due_at", "timezone", "remind_at", "completed_at", "recurrence", "tags",
+}
+
+// formulaPrefixes are the first characters that make spreadsheets read a
+// cell as a formula
+const formulaPrefixes = "=+-@\t\r"
+
+type csvWriter struct {
+	csv    *csv.Writer
+	header bool
+}
+
+func NewCSV(w io.Writer) Writer {
+	return &csvWriter{csv: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) Write(todo *entity.Todos) error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	row := []string{
+		strconv.FormatInt(todo.Id, 10),
+		todo.Title,
+		todo.Description,
+		todo.Status,
+		todo.Priority,
+		formatID(todo.ListID),
+		formatID(todo.ParentID),
+		formatTime(todo.DueAt),
+		todo.Timezone,
+		formatTime(todo.RemindAt),
+		formatTime(todo.CompletedAt),
+		todo.RecurrenceRule,
+		strings.Join(todo.Tags, ","),
+	}
+	for i := range row {
+		row[i] = escapeCell(row[i])
+	}
+
+	return w.csv.Write(row)
+}
+
+// escapeCell quotes a cell a spreadsheet would run as a formula, so a todo
+// titled "=HYPERLINK(...)" opens as text.
+func escapeCell(cell string) string {
+	if cell != "" && strings.ContainsRune(formulaPrefixes, rune(cell[0])) {
+		return "'" + cell
+	}
+
+	return cell
+}
+
+func (w *csvWriter) writeHeader() error {
+	if w.header {
+		return nil
+	}
+	w.header = true
+
+	return w.csv.Write(CSVHeader)
+}
+
+func (w *csvWriter) Flush() error {
+	w.csv.Flush()
+	return w.csv.Error()
+}
+
+func (w *csvWriter) Close() error {
+	if err := w.writeHeader(); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+// jsonWriter writes an array of todos shaped as the API returns them.
+type jsonWriter struct {
+	out   *bufio.Writer
+	count int
+}
+
+func NewJSON(w io.Writer) Writer {
+	return &jsonWriter{out: bufio.NewWriter(w)}
+}
+
+func (w *jsonWriter) Write(todo *entity.Todos) error {
+	separator := ",\n"
+	if w.count == 0 {
+		separator = "[\n"
+	}
+	w.count++
+
+	body, err := json.Marshal(todo)
+	if err != nil {
+		return err
+	}
+
+	w.out.WriteString(separator)
+	_, err = w.out.Write(body)
+	return err
+}
+
+func (w *jsonWriter) Flush() error {
+	return w.out.Flush()
+}
+
+func (w *jsonWriter) Close() error {
+	if w.count == 0 {
+		w.out.WriteString("[")
+	}
+	w.out.WriteString("\n]\n")
+
+	return w.out.Flush()
+}
+
+func formatID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+
+	return strconv.FormatInt(*id, 10)
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,132 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func exportTodos() []entity.Todos {
+	dueAt := time.Date(2026, 10, 20, 5, 0, 0, 0, time.FixedZone("WIB", 7*3600))
+	remindAt := dueAt.Add(-time.Hour)
+	parentID := int64(1)
+
+	return []entity.Todos{
+		{
+			Id: 1, Title: "Sholat, subuh", Description: "bangun jam 4;\nlalu wudhu", Status: entity.StatusInProgress,
+			Priority: entity.PriorityUrgent, DueAt: &dueAt, RemindAt: &remindAt, Timezone: "Asia/Jakarta",
+			RecurrenceRule: "FREQ=DAILY", Tags: []string{"ibadah", "pagi"},
+		},
+		{Id: 2, ParentID: &parentID, Title: "Wudhu", Status: entity.StatusDone, Priority: entity.PriorityLow},
+	}
+}
+
+func write(t *testing.T, format string, todos []entity.Todos) string {
+	var out bytes.Buffer
+	writer, err := New(format, &out, time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	for i := range todos {
+		require.NoError(t, writer.Write(&todos[i]))
+	}
+	require.NoError(t, writer.Close())
+
+	return out.String()
+}
+
+func TestCSV(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(write(t, FormatCSV, exportTodos()))).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, CSVHeader, records[0])
+	assert.Equal(t, []string{
+		"1", "Sholat, subuh", "bangun jam 4;\nlalu wudhu", "in_progress", "urgent", "", "",
+		"2026-10-19T22:00:00Z", "Asia/Jakarta", "2026-10-19T21:00:00Z", "", "FREQ=DAILY", "ibadah,pagi",
+	}, records[1])
+	assert.Equal(t, "1", records[2][6])
+}
+
+func TestCSVEscapesFormulas(t *testing.T) {
+	records, err := csv.NewReader(strings.NewReader(write(t, FormatCSV, []entity.Todos{
+		{Id: 1, Title: "=HYPERLINK(\"http://evil.example\")", Description: "-1+2", Tags: []string{"@home"}},
+		{Id: 2, Title: "a=b", Description: "+62 812", Timezone: "UTC"},
+	}))).ReadAll()
+	require.NoError(t, err)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, "'=HYPERLINK(\"http://evil.example\")", records[1][1])
+	assert.Equal(t, "'-1+2", records[1][2])
+	assert.Equal(t, "'@home", records[1][12])
+	assert.Equal(t, "a=b", records[2][1])
+	assert.Equal(t, "'+62 812", records[2][2])
+	assert.Equal(t, "UTC", records[2][8])
+}
+
+func TestJSON(t *testing.T) {
+	var todos []entity.Todos
+	require.NoError(t, json.Unmarshal([]byte(write(t, FormatJSON, exportTodos())), &todos))
+
+	require.Len(t, todos, 2)
+	assert.Equal(t, "Sholat, subuh", todos[0].Title)
+	assert.Equal(t, []string{"ibadah", "pagi"}, todos[0].Tags)
+}
+
+func TestEmptyExports(t *testing.T) {
+	assert.Equal(t, strings.Join(CSVHeader, ",")+"\n", write(t, FormatCSV, nil))
+	assert.Equal(t, "[\n]\n", write(t, FormatJSON, nil))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//todolist_gin_gorm//export//EN\r\nCALSCALE:GREGORIAN\r\nEND:VCALENDAR\r\n", write(t, FormatICS, nil))
+
+	var todos []entity.Todos
+	require.NoError(t, json.Unmarshal([]byte(write(t, FormatJSON, nil)), &todos))
+}
+
+func TestICS(t *testing.T) {
+	out := write(t, FormatICS, exportTodos())
+
+	assert.True(t, strings.HasPrefix(out, "BEGIN:VCALENDAR\r\n"))
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.Equal(t, 2, strings.Count(out, "BEGIN:VTODO\r\n"))
+
+	for _, line := range []string{
+		"UID:todo-1@todolist_gin_gorm",
+		"DTSTAMP:20261019T120000Z",
+		`SUMMARY:Sholat\, subuh`,
+		`DESCRIPTION:bangun jam 4\;\nlalu wudhu`,
+		"STATUS:IN-PROCESS",
+		"PRIORITY:1",
+		"DUE:20261019T220000Z",
+		"RRULE:FREQ=DAILY",
+		"CATEGORIES:ibadah,pagi",
+		"TRIGGER;VALUE=DATE-TIME:20261019T210000Z",
+		"STATUS:COMPLETED",
+		"PRIORITY:9",
+		"RELATED-TO:todo-1@todolist_gin_gorm",
+	} {
+		assert.Contains(t, out, line+"\r\n")
+	}
+}
+
+func TestICSFoldsLongLines(t *testing.T) {
+	title := strings.Repeat("ü", 60)
+	out := write(t, FormatICS, []entity.Todos{{Id: 1, Title: title}})
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), maxLineLength)
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "SUMMARY:"+title+"\r\n")
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := New("xml", &bytes.Buffer{}, time.Now())
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package export
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+	"unicode/utf8"
+)
+
+// icsTime is the UTC date-time form of RFC 5545, times are exported in UTC
+// so no VTIMEZONE has to be written.
+const icsTime = "20060102T150405Z"
+
+// maxLineLength is the number of octets after which iCalendar lines fold.
+const maxLineLength = 75
+
+// icsStatus maps our statuses to the ones VTODO knows, blocked todos still
+// need action.
+var icsStatus = map[string]string{
+	entity.StatusTodo:       "NEEDS-ACTION",
+	entity.StatusInProgress: "IN-PROCESS",
+	entity.StatusBlocked:    "NEEDS-ACTION",
+	entity.StatusDone:       "COMPLETED",
+	entity.StatusCancelled:  "CANCELLED",
+}
+
+// icsPriority maps priorities to the 1 (highest) to 9 (lowest) scale.
+var icsPriority = map[string]int{
+	entity.PriorityUrgent: 1,
+	entity.PriorityHigh:   3,
+	entity.PriorityMedium: 5,
+	entity.PriorityLow:    9,
+}
+
+// icsWriter writes a VCALENDAR with one VTODO per todo (RFC 5545).
+type icsWriter struct {
+	out    *bufio.Writer
+	now    time.Time
+	header bool
+}
+
+func NewICS(w io.Writer, now time.Time) Writer {
+	return &icsWriter{out: bufio.NewWriter(w), now: now.UTC()}
+}
+
+func (w *icsWriter) Write(todo *entity.Todos) error {
+	w.writeHeader()
+
+	w.line("BEGIN:VTODO")
+	w.line("UID:" + todoUID(todo.Id))
+	w.line("DTSTAMP:" + w.now.Format(icsTime))
+	w.line("SUMMARY:" + escapeText(todo.Title))
+	if todo.Description != "" {
+		w.line("DESCRIPTION:" + escapeText(todo.Description))
+	}
+	if status, ok := icsStatus[todo.Status]; ok {
+		w.line("STATUS:" + status)
+	}
+	if priority, ok := icsPriority[todo.Priority]; ok {
+		w.line("PRIORITY:" + strconv.Itoa(priority))
+	}
+	if todo.DueAt != nil {
+		w.line("DUE:" + todo.DueAt.UTC().Format(icsTime))
+	}
+	if todo.CompletedAt != nil {
+		w.line("COMPLETED:" + todo.CompletedAt.UTC().Format(icsTime))
+	}
+	if todo.RecurrenceRule != "" {
+		w.line("RRULE:" + strings.TrimPrefix(todo.RecurrenceRule, "RRULE:"))
+	}
+	if todo.ParentID != nil {
+		w.line("RELATED-TO:" + todoUID(*todo.ParentID))
+	}
+	if len(todo.Tags) > 0 {
+		categories := make([]string, len(todo.Tags))
+		for i, tag := range todo.Tags {
+			categories[i] = escapeText(tag)
+		}
+		w.line("CATEGORIES:" + strings.Join(categories, ","))
+	}
+	if todo.RemindAt != nil {
+		w.line("BEGIN:VALARM")
+		w.line("ACTION:DISPLAY")
+		w.line("TRIGGER;VALUE=DATE-TIME:" + todo.RemindAt.UTC().Format(icsTime))
+		w.line("DESCRIPTION:" + escapeText(todo.Title))
+		w.line("END:VALARM")
+	}
+	w.line("END:VTODO")
+
+	return nil
+}
+
+func (w *icsWriter) writeHeader() {
+	if w.header {
+		return
+	}
+	w.header = true
+
+	w.line("BEGIN:VCALENDAR")
+	w.line("VERSION:2.0")
+	w.line("PRODID:-//todolist_gin_gorm//export//EN")
+	w.line("CALSCALE:GREGORIAN")
+}
+
+func (w *icsWriter) Flush() error {
+	return w.out.Flush()
+}
+
+func (w *icsWriter) Close() error {
+	w.writeHeader()
+	w.line("END:VCALENDAR")
+
+	return w.out.Flush()
+}
+
+// line writes a content line, folding it so no line is longer than 75
+// octets without splitting a UTF-8 character.
+func (w *icsWriter) line(text string) {
+	limit := maxLineLength
+	for len(text) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		w.out.WriteString(text[:cut] + "\r\n ")
+		text = text[cut:]
+		// the leading space of a continuation line counts
+		limit = maxLineLength - 1
+	}
+	w.out.WriteString(text + "\r\n")
+}
+
+func todoUID(id int64) string {
+	return fmt.Sprintf("todo-%d@todolist_gin_gorm", id)
+}
+
+// escapeText escapes a TEXT value.
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
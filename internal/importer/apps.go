@@ -0,0 +1,181 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+// todoistPriority maps Todoist's 4 (p1, the highest) to 1 scale
+var todoistPriority = map[int]string{
+	4: entity.PriorityUrgent,
+	3: entity.PriorityHigh,
+	2: entity.PriorityMedium,
+	1: entity.PriorityLow,
+}
+
+type todoistTask struct {
+	ID          json.RawMessage `json:"id"`
+	Content     string          `json:"content"`
+	Description string          `json:"description"`
+	Priority    int             `json:"priority"`
+	Checked     bool            `json:"checked"`
+	IsCompleted bool            `json:"is_completed"`
+	Due         *struct {
+		Date     string `json:"date"`
+		Datetime string `json:"datetime"`
+		Timezone string `json:"timezone"`
+	} `json:"due"`
+}
+
+// parseTodoist reads tasks as the Todoist REST API returns them, either a
+// bare array or the "items" of a Sync API response. Recurring due dates are
+// written in natural language by Todoist and are imported as single ones.
+func parseTodoist(r io.Reader) ([]Record, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var tasks []todoistTask
+	if bytes.HasPrefix(bytes.TrimSpace(body), []byte("[")) {
+		err = json.Unmarshal(body, &tasks)
+	} else {
+		var sync struct {
+			Items []todoistTask `json:"items"`
+		}
+		err = json.Unmarshal(body, &sync)
+		tasks = sync.Items
+	}
+	if err != nil {
+		return nil, fmt.Errorf("the file is not a Todoist export: %w", err)
+	}
+
+	records := make([]Record, len(tasks))
+	for i, task := range tasks {
+		records[i] = Record{
+			Row: i + 1,
+			Todo: dto.CreateTodolistRequest{
+				Title:       strings.TrimSpace(task.Content),
+				Description: descriptionOrTitle(task.Description, task.Content),
+				Status:      entity.StatusTodo,
+				Priority:    todoistPriority[task.Priority],
+			},
+		}
+		if id := rawID(task.ID); id != "" {
+			records[i].ExternalID = "todoist:" + id
+		}
+		if task.Checked || task.IsCompleted {
+			records[i].Todo.Status = entity.StatusDone
+		}
+
+		if task.Due != nil {
+			records[i].Todo.Timezone = task.Due.Timezone
+			value := task.Due.Datetime
+			if value == "" {
+				value = task.Due.Date
+			}
+			records[i].Todo.DueAt, records[i].Err = parseTodoistDue(value, task.Due.Timezone)
+		}
+	}
+
+	return records, nil
+}
+
+// parseTodoistDue reads a due date with a time in UTC, a floating time or a
+// whole day, the last two in the task's timezone.
+func parseTodoistDue(value string, timezone string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if due, err := time.Parse(time.RFC3339, value); err == nil {
+		return &due, nil
+	}
+
+	location, err := time.LoadLocation(dto.TimezoneOrUTC(timezone))
+	if err != nil {
+		location = time.UTC
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02"} {
+		if due, err := time.ParseInLocation(layout, value, location); err == nil {
+			return &due, nil
+		}
+	}
+
+	return nil, errors.New("due date is not a date Todoist writes")
+}
+
+// parseTrello reads the cards of a Trello board export. Archived cards are
+// cancelled unless their due date was completed.
+func parseTrello(r io.Reader) ([]Record, error) {
+	var board struct {
+		Cards []struct {
+			ID          string     `json:"id"`
+			Name        string     `json:"name"`
+			Desc        string     `json:"desc"`
+			Closed      bool       `json:"closed"`
+			Due         *time.Time `json:"due"`
+			DueComplete bool       `json:"dueComplete"`
+		} `json:"cards"`
+	}
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, fmt.Errorf("the file is not a Trello board export: %w", err)
+	}
+
+	records := make([]Record, len(board.Cards))
+	for i, card := range board.Cards {
+		records[i] = Record{
+			Row: i + 1,
+			Todo: dto.CreateTodolistRequest{
+				Title:       strings.TrimSpace(card.Name),
+				Description: descriptionOrTitle(card.Desc, card.Name),
+				Status:      entity.StatusTodo,
+				DueAt:       card.Due,
+			},
+		}
+		if card.ID != "" {
+			records[i].ExternalID = "trello:" + card.ID
+		}
+
+		switch {
+		case card.DueComplete:
+			records[i].Todo.Status = entity.StatusDone
+		case card.Closed:
+			records[i].Todo.Status = entity.StatusCancelled
+		}
+	}
+
+	return records, nil
+}
+
+// descriptionOrTitle fills the description our todos require with the title
+// when the other app had none.
+func descriptionOrTitle(description string, title string) string {
+	if description = strings.TrimSpace(description); description != "" {
+		return description
+	}
+
+	return strings.TrimSpace(title)
+}
+
+// rawID reads an id sent either as a JSON string or a number.
+func rawID(raw json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id
+	}
+
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number.String()
+	}
+
+	return ""
+}
@@ -0,0 +1,180 @@
+// Package importer reads todos from import files: CSV and JSON as written by
+// package export, and the JSON exports of Todoist and Trello. Records are
+// only parsed here, validating them is left to the caller.
+package importer
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatJSON    = "json"
+	FormatTodoist = "todoist"
+	FormatTrello  = "trello"
+)
+
+// Record is one todo of an import file.
+type Record struct {
+	// Row is the line of a CSV file or the 1-based position of a JSON item
+	Row int
+	// ExternalID is "<source>:<id>", empty when the item has no id
+	ExternalID string
+	Todo       dto.CreateTodolistRequest
+	// Err is set when a value of the row couldn't be read
+	Err error
+}
+
+// Parse reads every record of r. An error is returned when the file itself
+// can't be read, problems with single rows are left in Record.Err.
+func Parse(format string, r io.Reader) ([]Record, error) {
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatJSON:
+		return parseJSON(r)
+	case FormatTodoist:
+		return parseTodoist(r)
+	case FormatTrello:
+		return parseTrello(r)
+	default:
+		return nil, fmt.Errorf("importer: unknown format %q", format)
+	}
+}
+
+// parseCSV reads a file with a header row naming its columns as package
+// export does, only title is required. Ids become "todolist:<id>".
+func parseCSV(r io.Reader) ([]Record, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("the file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	if _, ok := columns["title"]; !ok {
+		return nil, errors.New("the file has no title column")
+	}
+
+	var records []Record
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(fields) {
+				return unescapeCell(strings.TrimSpace(fields[i]))
+			}
+			return ""
+		}
+
+		record := Record{
+			Row: line,
+			Todo: dto.CreateTodolistRequest{
+				Title:       value("title"),
+				Description: value("description"),
+				Status:      value("status"),
+				Priority:    value("priority"),
+				Timezone:    value("timezone"),
+				Recurrence:  value("recurrence"),
+			},
+		}
+		if id := value("id"); id != "" {
+			record.ExternalID = "todolist:" + id
+		}
+		record.Todo.DueAt, record.Err = parseTime("due_at", value("due_at"), record.Err)
+		record.Todo.RemindAt, record.Err = parseTime("remind_at", value("remind_at"), record.Err)
+
+		records = append(records, record)
+	}
+}
+
+// unescapeCell drops the quote package export puts before cells a
+// spreadsheet would run as a formula.
+func unescapeCell(cell string) string {
+	if len(cell) > 1 && cell[0] == '\'' && strings.ContainsRune("=+-@\t\r", rune(cell[1])) {
+		return cell[1:]
+	}
+
+	return cell
+}
+
+// parseJSON reads an array of todos as the API and package export write them.
+func parseJSON(r io.Reader) ([]Record, error) {
+	var items []struct {
+		ID          int64      `json:"id"`
+		Title       string     `json:"title"`
+		Description string     `json:"description"`
+		Status      string     `json:"status"`
+		Priority    string     `json:"priority"`
+		DueAt       *time.Time `json:"due_at"`
+		Timezone    string     `json:"timezone"`
+		RemindAt    *time.Time `json:"remind_at"`
+		Recurrence  string     `json:"recurrence"`
+	}
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("the file is not a JSON array of todos: %w", err)
+	}
+
+	records := make([]Record, len(items))
+	for i, item := range items {
+		records[i] = Record{
+			Row: i + 1,
+			Todo: dto.CreateTodolistRequest{
+				Title:       item.Title,
+				Description: item.Description,
+				Status:      item.Status,
+				Priority:    item.Priority,
+				DueAt:       item.DueAt,
+				Timezone:    item.Timezone,
+				RemindAt:    item.RemindAt,
+				Recurrence:  item.Recurrence,
+			},
+		}
+		if item.ID != 0 {
+			records[i].ExternalID = "todolist:" + strconv.FormatInt(item.ID, 10)
+		}
+	}
+
+	return records, nil
+}
+
+// parseTime reads an RFC 3339 time, an empty value is no time. The first
+// error of a row is kept.
+func parseTime(column string, value string, rowErr error) (*time.Time, error) {
+	if value == "" {
+		return nil, rowErr
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if rowErr == nil {
+			rowErr = fmt.Errorf("%s must be an RFC 3339 time", column)
+		}
+		return nil, rowErr
+	}
+
+	return &parsed, rowErr
+}
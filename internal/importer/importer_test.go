@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/export"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCSVRoundTrip(t *testing.T) {
+	dueAt := time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC)
+	var out bytes.Buffer
+	writer := export.NewCSV(&out)
+	require.NoError(t, writer.Write(&entity.Todos{
+		Id: 12, Title: "Sholat, subuh", Description: "bangun\njam 4", Status: entity.StatusDone,
+		Priority: entity.PriorityHigh, DueAt: &dueAt, Timezone: "Asia/Jakarta", RecurrenceRule: "FREQ=DAILY",
+	}))
+	require.NoError(t, writer.Close())
+
+	records, err := Parse(FormatCSV, &out)
+	require.NoError(t, err)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, 2, records[0].Row)
+	assert.Equal(t, "todolist:12", records[0].ExternalID)
+	assert.Equal(t, "Sholat, subuh", records[0].Todo.Title)
+	assert.Equal(t, "bangun\njam 4", records[0].Todo.Description)
+	assert.Equal(t, entity.StatusDone, records[0].Todo.Status)
+	assert.Equal(t, "Asia/Jakarta", records[0].Todo.Timezone)
+	assert.True(t, dueAt.Equal(*records[0].Todo.DueAt))
+	assert.NoError(t, records[0].Err)
+}
+
+func TestCSVRoundTripKeepsFormulaText(t *testing.T) {
+	var out bytes.Buffer
+	writer := export.NewCSV(&out)
+	require.NoError(t, writer.Write(&entity.Todos{Id: 1, Title: "=SUM(A1:A2)", Description: "-1 kg"}))
+	require.NoError(t, writer.Close())
+
+	records, err := Parse(FormatCSV, &out)
+	require.NoError(t, err)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, "=SUM(A1:A2)", records[0].Todo.Title)
+	assert.Equal(t, "-1 kg", records[0].Todo.Description)
+}
+
+func TestCSVRows(t *testing.T) {
+	records, err := Parse(FormatCSV, strings.NewReader("\ufeffTitle,due_at\nSholat,tomorrow\n\"Puasa\nsenin\",\n"))
+	require.NoError(t, err)
+
+	require.Len(t, records, 2)
+	assert.EqualError(t, records[0].Err, "due_at must be an RFC 3339 time")
+	assert.Equal(t, 3, records[1].Row)
+	assert.Empty(t, records[1].ExternalID)
+
+	_, err = Parse(FormatCSV, strings.NewReader("name\nSholat\n"))
+	assert.EqualError(t, err, "the file has no title column")
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	var out bytes.Buffer
+	writer := export.NewJSON(&out)
+	require.NoError(t, writer.Write(&entity.Todos{Id: 3, Title: "Sholat", Description: "subuh", Priority: entity.PriorityLow}))
+	require.NoError(t, writer.Close())
+
+	records, err := Parse(FormatJSON, &out)
+	require.NoError(t, err)
+
+	require.Len(t, records, 1)
+	assert.Equal(t, "todolist:3", records[0].ExternalID)
+	assert.Equal(t, entity.PriorityLow, records[0].Todo.Priority)
+
+	_, err = Parse(FormatJSON, strings.NewReader(`{"title":"Sholat"}`))
+	assert.Error(t, err)
+}
+
+func TestTodoist(t *testing.T) {
+	records, err := Parse(FormatTodoist, strings.NewReader(`{"items":[
+		{"id":"2995104339","content":"Buy Milk","priority":4,"checked":true,"due":{"date":"2026-10-20T05:00:00Z"}},
+		{"id":2995104340,"content":"Call mom","description":"about sunday","priority":1,"due":{"date":"2026-10-21","timezone":"Asia/Jakarta"}},
+		{"id":"2995104341","content":"Pay rent","due":{"date":"next week"}}
+	]}`))
+	require.NoError(t, err)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, "todoist:2995104339", records[0].ExternalID)
+	assert.Equal(t, entity.StatusDone, records[0].Todo.Status)
+	assert.Equal(t, entity.PriorityUrgent, records[0].Todo.Priority)
+	assert.Equal(t, "Buy Milk", records[0].Todo.Description)
+	assert.Equal(t, time.Date(2026, 10, 20, 5, 0, 0, 0, time.UTC), records[0].Todo.DueAt.UTC())
+
+	assert.Equal(t, "todoist:2995104340", records[1].ExternalID)
+	assert.Equal(t, "about sunday", records[1].Todo.Description)
+	assert.Equal(t, entity.PriorityLow, records[1].Todo.Priority)
+	assert.Equal(t, time.Date(2026, 10, 20, 17, 0, 0, 0, time.UTC), records[1].Todo.DueAt.UTC())
+
+	assert.Error(t, records[2].Err)
+
+	records, err = Parse(FormatTodoist, strings.NewReader(`[{"id":"1","content":"Buy Milk","is_completed":false}]`))
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatusTodo, records[0].Todo.Status)
+}
+
+func TestTrello(t *testing.T) {
+	records, err := Parse(FormatTrello, strings.NewReader(`{"name":"Board","cards":[
+		{"id":"5f1","name":"Write report","desc":"q3 numbers","due":"2026-10-20T05:00:00.000Z","dueComplete":true},
+		{"id":"5f2","name":"Old idea","closed":true,"due":null}
+	]}`))
+	require.NoError(t, err)
+
+	require.Len(t, records, 2)
+	assert.Equal(t, "trello:5f1", records[0].ExternalID)
+	assert.Equal(t, entity.StatusDone, records[0].Todo.Status)
+	assert.Equal(t, "q3 numbers", records[0].Todo.Description)
+	require.NotNil(t, records[0].Todo.DueAt)
+	assert.Equal(t, entity.StatusCancelled, records[1].Todo.Status)
+	assert.Nil(t, records[1].Todo.DueAt)
+}
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogMailer is a stand-in for local development: it writes every message as
+// an .eml file into dir, or to the log when dir is empty.
+type LogMailer struct {
+	dir  string
+	from string
+}
+
+func NewLogMailer(dir string, from string) *LogMailer {
+	return &LogMailer{
+		dir:  dir,
+		from: from,
+	}
+}
+
+func (mailer *LogMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if mailer.dir == "" {
+		logrus.WithFields(logrus.Fields{
+			"to":      message.To,
+			"subject": message.Subject,
+		}).Info(message.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(mailer.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), sanitizeFileName(message.To))
+	return os.WriteFile(filepath.Join(mailer.dir, name), formatMessage(mailer.from, message), 0o644)
+}
+
+func sanitizeFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, name)
+}
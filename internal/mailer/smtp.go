@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+)
+
+type SMTPMailer struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host string, port int, username string, password string, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(host, strconv.Itoa(port)),
+		host: host,
+		auth: auth,
+		from: from,
+	}
+}
+
+func (mailer *SMTPMailer) Send(ctx context.Context, message Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(mailer.addr, mailer.auth, mailer.from, []string{message.To}, formatMessage(mailer.from, message)); err != nil {
+		return fmt.Errorf("send mail to %s: %w", message.To, err)
+	}
+
+	return nil
+}
+
+// formatMessage renders the headers and body of a plain text email.
+func formatMessage(from string, message Message) []byte {
+	header := strings.NewReplacer("\r", "", "\n", "")
+
+	var builder strings.Builder
+	builder.WriteString("From: " + header.Replace(from) + "\r\n")
+	builder.WriteString("To: " + header.Replace(message.To) + "\r\n")
+	builder.WriteString("Subject: " + header.Replace(message.Subject) + "\r\n")
+	builder.WriteString("MIME-Version: 1.0\r\n")
+	builder.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	builder.WriteString("\r\n")
+	builder.WriteString(message.Body)
+
+	return []byte(builder.String())
+}
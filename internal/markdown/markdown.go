@@ -0,0 +1,221 @@
+// Package markdown renders the small Markdown subset used in comments to
+// HTML that is safe to show in a page.
+//
+// Raw HTML is never passed through, every character of the source is
+// escaped. Supported are paragraphs, headings, lists, block quotes, fenced
+// code blocks, inline code, emphasis and links. Links only keep http, https
+// and mailto targets, anything else is rendered as plain text.
+package markdown
+
+import (
+	"html"
+	"net/url"
+	"strings"
+)
+
+// Render turns Markdown source into sanitized HTML.
+func Render(source string) string {
+	r := &renderer{}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+
+	for _, line := range lines {
+		if r.code != nil {
+			if strings.HasPrefix(strings.TrimSpace(line), "```") {
+				r.flushCode()
+			} else {
+				r.code = append(r.code, line)
+			}
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			r.flush()
+		case strings.HasPrefix(trimmed, "```"):
+			r.flush()
+			r.code = []string{}
+		case heading(trimmed) > 0:
+			r.flush()
+			level := heading(trimmed)
+			r.out.WriteString("<h" + string(rune('0'+level)) + ">")
+			r.out.WriteString(inline(strings.TrimSpace(trimmed[level:])))
+			r.out.WriteString("</h" + string(rune('0'+level)) + ">\n")
+		case strings.HasPrefix(trimmed, ">"):
+			if len(r.quote) == 0 {
+				r.flush()
+			}
+			r.quote = append(r.quote, strings.TrimSpace(strings.TrimPrefix(trimmed, ">")))
+		case listItem(trimmed, "ul") != "":
+			r.item("ul", listItem(trimmed, "ul"))
+		case listItem(trimmed, "ol") != "":
+			r.item("ol", listItem(trimmed, "ol"))
+		default:
+			if r.list != "" || len(r.quote) > 0 {
+				r.flush()
+			}
+			r.paragraph = append(r.paragraph, trimmed)
+		}
+	}
+
+	if r.code != nil {
+		r.flushCode()
+	}
+	r.flush()
+
+	return strings.TrimSuffix(r.out.String(), "\n")
+}
+
+type renderer struct {
+	out       strings.Builder
+	paragraph []string
+	quote     []string
+	list      string
+	items     []string
+	code      []string
+}
+
+func (r *renderer) item(list string, text string) {
+	if r.list != list {
+		r.flush()
+		r.list = list
+	}
+	r.items = append(r.items, text)
+}
+
+// flush closes the open paragraph, quote or list.
+func (r *renderer) flush() {
+	if len(r.paragraph) > 0 {
+		r.out.WriteString("<p>" + inline(strings.Join(r.paragraph, "\n")) + "</p>\n")
+		r.paragraph = nil
+	}
+
+	if len(r.quote) > 0 {
+		r.out.WriteString("<blockquote><p>" + inline(strings.Join(r.quote, "\n")) + "</p></blockquote>\n")
+		r.quote = nil
+	}
+
+	if r.list != "" {
+		r.out.WriteString("<" + r.list + ">\n")
+		for _, item := range r.items {
+			r.out.WriteString("<li>" + inline(item) + "</li>\n")
+		}
+		r.out.WriteString("</" + r.list + ">\n")
+		r.list, r.items = "", nil
+	}
+}
+
+func (r *renderer) flushCode() {
+	r.out.WriteString("<pre><code>" + html.EscapeString(strings.Join(r.code, "\n")) + "</code></pre>\n")
+	r.code = nil
+}
+
+// heading returns the level of a "# title" line, 0 when it isn't one.
+func heading(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level == len(line) || line[level] != ' ' {
+		return 0
+	}
+
+	return level
+}
+
+// listItem returns the text of a "- item" or "1. item" line.
+func listItem(line string, list string) string {
+	if list == "ul" {
+		if len(line) > 2 && (line[0] == '-' || line[0] == '*' || line[0] == '+') && line[1] == ' ' {
+			return strings.TrimSpace(line[2:])
+		}
+		return ""
+	}
+
+	digits := 0
+	for digits < len(line) && line[digits] >= '0' && line[digits] <= '9' {
+		digits++
+	}
+	if digits == 0 || digits+2 > len(line) || line[digits] != '.' || line[digits+1] != ' ' {
+		return ""
+	}
+
+	return strings.TrimSpace(line[digits+2:])
+}
+
+// inline renders code spans, links and emphasis, escaping everything else.
+func inline(text string) string {
+	var out strings.Builder
+
+	for i := 0; i < len(text); {
+		rest := text[i:]
+		switch {
+		case rest[0] == '`':
+			if end := strings.IndexByte(rest[1:], '`'); end > 0 {
+				out.WriteString("<code>" + html.EscapeString(rest[1:end+1]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case rest[0] == '[':
+			if label, target, n, ok := link(rest); ok {
+				if safeURL(target) {
+					out.WriteString(`<a href="` + html.EscapeString(target) + `" rel="nofollow noopener">` + inline(label) + "</a>")
+				} else {
+					out.WriteString(inline(label))
+				}
+				i += n
+				continue
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(rest[2:], rest[:2]); end > 0 {
+				out.WriteString("<strong>" + inline(rest[2:end+2]) + "</strong>")
+				i += end + 4
+				continue
+			}
+		case rest[0] == '*' || rest[0] == '_':
+			if end := strings.IndexByte(rest[1:], rest[0]); end > 0 && rest[1] != ' ' {
+				out.WriteString("<em>" + inline(rest[1:end+1]) + "</em>")
+				i += end + 2
+				continue
+			}
+		}
+
+		out.WriteString(html.EscapeString(rest[:1]))
+		i++
+	}
+
+	return out.String()
+}
+
+// link parses "[label](target)" at the start of text and returns how many
+// bytes it used.
+func link(text string) (string, string, int, bool) {
+	closeLabel := strings.Index(text, "](")
+	if closeLabel < 0 {
+		return "", "", 0, false
+	}
+
+	closeTarget := strings.IndexByte(text[closeLabel+2:], ')')
+	if closeTarget < 0 {
+		return "", "", 0, false
+	}
+
+	target := strings.TrimSpace(text[closeLabel+2 : closeLabel+2+closeTarget])
+	return text[1:closeLabel], target, closeLabel + 3 + closeTarget, true
+}
+
+func safeURL(target string) bool {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(parsed.Scheme) {
+	case "http", "https":
+		return parsed.Host != ""
+	case "mailto":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,44 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	testCase := []struct {
+		source   string
+		expected string
+	}{
+		{source: "hello *world*", expected: "<p>hello <em>world</em></p>"},
+		{source: "**done** and `go test`", expected: "<p><strong>done</strong> and <code>go test</code></p>"},
+		{source: "# Notes\nfirst\nsecond\n\nthird", expected: "<h1>Notes</h1>\n<p>first\nsecond</p>\n<p>third</p>"},
+		{source: "- milk\n- eggs\n1. wash\n2. dry", expected: "<ul>\n<li>milk</li>\n<li>eggs</li>\n</ul>\n<ol>\n<li>wash</li>\n<li>dry</li>\n</ol>"},
+		{source: "> quoted\n\nafter", expected: "<blockquote><p>quoted</p></blockquote>\n<p>after</p>"},
+		{source: "```\n<b>x</b>\n```", expected: "<pre><code>&lt;b&gt;x&lt;/b&gt;</code></pre>"},
+		{source: "see [docs](https://example.com/a?b=1&c=2)", expected: `<p>see <a href="https://example.com/a?b=1&amp;c=2" rel="nofollow noopener">docs</a></p>`},
+		{source: "2 * 3 * 4", expected: "<p>2 * 3 * 4</p>"},
+	}
+
+	for _, test := range testCase {
+		assert.Equal(t, test.expected, Render(test.source), "render %q", test.source)
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	testCase := []struct {
+		source   string
+		expected string
+	}{
+		{source: "<script>alert(1)</script>", expected: "<p>&lt;script&gt;alert(1)&lt;/script&gt;</p>"},
+		{source: "[click](javascript:alert(1))", expected: "<p>click)</p>"},
+		{source: "[x](https://a.com\" onclick=\"alert(1))", expected: "<p>x)</p>"},
+		{source: "<img src=x onerror=alert(1)>", expected: "<p>&lt;img src=x onerror=alert(1)&gt;</p>"},
+		{source: "`<b>`", expected: "<p><code>&lt;b&gt;</code></p>"},
+	}
+
+	for _, test := range testCase {
+		assert.Equal(t, test.expected, Render(test.source), "render %q", test.source)
+	}
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTableDrivenAuthMiddlewareAPIKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	past := time.Now().Add(-time.Hour)
+	recent := time.Now().Add(-10 * time.Second)
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com", Role: entity.RoleUser}
+
+	testCase := []struct {
+		name           string
+		method         string
+		key            *entity.APIKeys
+		expectedStatus int
+	}{
+		{
+			name:           "unknown key",
+			method:         http.MethodGet,
+			key:            nil,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "revoked key",
+			method:         http.MethodGet,
+			key:            &entity.APIKeys{KeyID: 1, UserID: 7, Scopes: "read", RevokedAt: &past},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "expired key",
+			method:         http.MethodGet,
+			key:            &entity.APIKeys{KeyID: 1, UserID: 7, Scopes: "read", ExpiresAt: &past},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "read-only key cannot write",
+			method:         http.MethodPost,
+			key:            &entity.APIKeys{KeyID: 1, UserID: 7, Scopes: "read"},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "read-only key can read",
+			method:         http.MethodGet,
+			key:            &entity.APIKeys{KeyID: 1, UserID: 7, Scopes: "read"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "write key can write",
+			method:         http.MethodPost,
+			key:            &entity.APIKeys{KeyID: 1, UserID: 7, Scopes: "read,write"},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "recently used key isn't touched again",
+			method:         http.MethodGet,
+			key:            &entity.APIKeys{KeyID: 1, UserID: 7, Scopes: "read", LastUsedAt: &recent},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "key last used long ago is touched",
+			method:         http.MethodGet,
+			key:            &entity.APIKeys{KeyID: 1, UserID: 7, Scopes: "read", LastUsedAt: &past},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindAPIKeyByHash", security.HashToken("tdl_key")).Return(test.key, nil)
+			if test.expectedStatus == http.StatusOK {
+				repo.On("FindUserByID", int64(7)).Return(user, nil)
+			}
+			if test.expectedStatus == http.StatusOK && test.key.LastUsedAt != &recent {
+				repo.On("TouchAPIKey", int64(1)).Return(nil)
+			}
+
+			router := gin.New()
+			router.Use(Authenticate(repo))
+			router.Handle(test.method, "/api/todos", func(ctx *gin.Context) {
+				assert.Equal(t, int64(7), ctx.GetInt64("user_id"))
+				assert.Equal(t, "api_key", ctx.GetString("auth_method"))
+				ctx.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(test.method, "/api/todos", nil)
+			request.Header.Set("X-API-Key", "tdl_key")
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestRequireJWTRejectsAPIKey(t *testing.T) {
+	router := gin.New()
+	router.GET("/me/api_keys", func(ctx *gin.Context) {
+		ctx.Set("auth_method", "api_key")
+	}, RequireJWT(), func(ctx *gin.Context) {
+		ctx.Status(http.StatusOK)
+	})
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/me/api_keys", nil))
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
@@ -2,18 +2,26 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/config"
 	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/repository"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
 // secret-key untuk sign-in token
 
 // request -> server
 
+// sessionTouchInterval is how stale last_seen_at may get before it is updated
+const sessionTouchInterval = time.Minute
+
 // AuthMiddlewareJWT is a middleware function to check user authentication
-func AuthMiddlewareJWT() gin.HandlerFunc {
+func AuthMiddlewareJWT(repository repository.Repository) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		// mengambil token dari Header Authorization
 		authHeader := ctx.GetHeader("Authorization")
@@ -27,14 +35,10 @@ func AuthMiddlewareJWT() gin.HandlerFunc {
 		}
 
 		// split token dari Header
-		tokenString := authHeader[len("Bearer "):]
+		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
 		// Parsing token dengan menggunakan struct Claims
-		claims := &jwt.StandardClaims{}
-		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-			return []byte("secret-key"), nil // Ganti dengan kunci rahasia yang sesuai
-		})
-
+		claims, err := config.ParseJWTToken(tokenString)
 		if err != nil {
 			if err == jwt.ErrSignatureInvalid {
 				ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
@@ -50,16 +54,66 @@ func AuthMiddlewareJWT() gin.HandlerFunc {
 			return
 		}
 
-		if !token.Valid {
+		// token yang dibuat sebelum password diganti sudah tidak berlaku
+		user, err := repository.FindUserByID(claims.UserID)
+		if err != nil {
+			logrus.Errorf("failed when get user by id: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+
+		if user == nil || user.TokenVersion != claims.TokenVersion {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Message: "unauthorized (non valid)",
+				Message: "token has been revoked",
 				Status:  http.StatusUnauthorized,
 			})
 			return
 		}
 
-		// jika token valid, mengambil email dari claim dan simpan ke dalam konteks
-		ctx.Set("email", claims.Subject)
+		if user.DisabledAt != nil {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+				Message: "account is disabled",
+				Status:  http.StatusForbidden,
+			})
+			return
+		}
+
+		// setiap token terikat ke satu sesi yang bisa dicabut sendiri
+		session, err := repository.FindSessionByJTI(claims.Id)
+		if err != nil {
+			logrus.Errorf("failed when get session: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+
+		if session == nil || session.UserID != user.UserID || !session.Active(time.Now()) {
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Message: "session has been revoked",
+				Status:  http.StatusUnauthorized,
+			})
+			return
+		}
+
+		// last_seen only needs minute precision, skip the write on every request
+		if time.Since(session.LastSeenAt) > sessionTouchInterval {
+			if err := repository.TouchSession(session.SessionID); err != nil {
+				logrus.Warnf("failed to update session last seen: %v", err)
+			}
+		}
+
+		// jika token valid, simpan user yang login ke dalam konteks
+		ctx.Set("email", user.Email)
+		ctx.Set("user_id", user.UserID)
+		ctx.Set("role", claims.Role)
+		ctx.Set("user", user)
+		ctx.Set("auth_method", "jwt")
+		ctx.Set("session_id", session.SessionID)
 
 		// jika token valid, akan dilanjutkan ke handler
 		ctx.Next()
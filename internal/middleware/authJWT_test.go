@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDrivenAuthMiddlewareJWTSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com", Role: entity.RoleUser}
+	token, err := config.CreateJWTToken(user, "jti-1")
+	require.NoError(t, err)
+
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	testCase := []struct {
+		name           string
+		session        *entity.Sessions
+		expectTouch    bool
+		expectedStatus int
+	}{
+		{
+			name:           "unknown session",
+			session:        nil,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "revoked session",
+			session:        &entity.Sessions{SessionID: 3, UserID: 7, ExpiresAt: future, RevokedAt: &past},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "session of another user",
+			session:        &entity.Sessions{SessionID: 3, UserID: 8, ExpiresAt: future, LastSeenAt: time.Now()},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "recently seen session",
+			session:        &entity.Sessions{SessionID: 3, UserID: 7, ExpiresAt: future, LastSeenAt: time.Now()},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "stale last seen is updated",
+			session:        &entity.Sessions{SessionID: 3, UserID: 7, ExpiresAt: future, LastSeenAt: past},
+			expectTouch:    true,
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("FindUserByID", int64(7)).Return(user, nil)
+			repo.On("FindSessionByJTI", "jti-1").Return(test.session, nil)
+			if test.expectTouch {
+				repo.On("TouchSession", int64(3)).Return(nil)
+			}
+
+			router := gin.New()
+			router.Use(AuthMiddlewareJWT(repo))
+			router.GET("/api/me", func(ctx *gin.Context) {
+				assert.Equal(t, int64(3), ctx.GetInt64("session_id"))
+				ctx.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			request.Header.Set("Authorization", "Bearer "+token)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "kept", header: "3f2a-proxy_1.0", expected: "3f2a-proxy_1.0"},
+		{name: "missing", header: ""},
+		{name: "unsafe", header: "id\nset-cookie"},
+		{name: "too long", header: strings.Repeat("a", 65)},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			var requestID string
+			router := gin.New()
+			router.GET("/", RequestID(), func(ctx *gin.Context) {
+				requestID = ctx.GetString("request_id")
+				ctx.Status(http.StatusOK)
+			})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			if test.header != "" {
+				request.Header.Set(RequestIDHeader, test.header)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+
+			if test.expected != "" {
+				assert.Equal(t, test.expected, requestID)
+			} else {
+				assert.Len(t, requestID, 32)
+			}
+			assert.Equal(t, requestID, recorder.Header().Get(RequestIDHeader))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"todolist_gin_gorm/internal/model/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole only lets through requests whose token carries one of roles.
+// It must run after AuthMiddlewareJWT.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := ctx.GetString("role")
+		for _, allowed := range roles {
+			if role == allowed {
+				ctx.Next()
+				return
+			}
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "forbidden",
+			Status:  http.StatusForbidden,
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		role           string
+		expectedStatus int
+	}{
+		{name: "admin", role: "admin", expectedStatus: http.StatusOK},
+		{name: "user", role: "user", expectedStatus: http.StatusForbidden},
+		{name: "anonymous", role: "", expectedStatus: http.StatusForbidden},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/admin", func(ctx *gin.Context) {
+				ctx.Set("role", test.role)
+			}, RequireRole("admin"), func(ctx *gin.Context) {
+				ctx.Status(http.StatusOK)
+			})
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/admin", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
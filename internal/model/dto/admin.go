@@ -0,0 +1,47 @@
+package dto
+
+type PageQuery struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// LimitOffset applies the defaults (first page, 20 rows) to the query.
+func (query *PageQuery) LimitOffset() (int, int) {
+	page, limit := query.Page, query.Limit
+	if page == 0 {
+		page = 1
+	}
+	if limit == 0 {
+		limit = 20
+	}
+
+	return limit, (page - 1) * limit
+}
+
+type AdminListUsersQuery struct {
+	PageQuery
+	Query string `form:"q"`
+}
+
+type UpdateRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+type AdminListUsersResponse struct {
+	Status  int            `json:"status"`
+	Message string         `json:"message"`
+	More    int            `json:"more"`
+	Total   int64          `json:"total"`
+	Data    []UserResponse `json:"data"`
+}
+
+type AdminUserResponse struct {
+	Status  int          `json:"status"`
+	Message string       `json:"message"`
+	Data    UserResponse `json:"data"`
+}
+
+type AdminActionResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "todolist_gin_gorm/internal/model/entity"
+
+type AttachmentResponse struct {
+	Status  int                `json:"status"`
+	Message string             `json:"message"`
+	Data    entity.Attachments `json:"data"`
+}
+
+type AttachmentResponseGetAll struct {
+	Status  int                  `json:"status"`
+	Message string               `json:"message"`
+	More    int                  `json:"more"`
+	Data    []entity.Attachments `json:"data"`
+}
+
+type AttachmentResponseDelete struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+// AuditQuery filters the audit events, newest first. Times are RFC 3339 with
+// an offset.
+type AuditQuery struct {
+	PageQuery
+	ActorID    int64     `form:"actor_id" binding:"omitempty,min=1"`
+	Action     string    `form:"action" binding:"omitempty,max=32"`
+	EntityType string    `form:"entity_type" binding:"omitempty,oneof=todo list user"`
+	EntityID   int64     `form:"entity_id" binding:"omitempty,min=1"`
+	Since      time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Until      time.Time `form:"until" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type AuditEventResponse struct {
+	Status  int                  `json:"status"`
+	Message string               `json:"message"`
+	More    int                  `json:"more"`
+	Data    []entity.AuditEvents `json:"data"`
+}
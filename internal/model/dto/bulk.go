@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"encoding/json"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+const (
+	// BulkAtomic rolls every operation back when one of them fails
+	BulkAtomic = "atomic"
+	// BulkPartial keeps the operations that succeeded
+	BulkPartial = "partial"
+)
+
+// BulkRequest runs many todo operations in one transaction, in "atomic" mode
+// unless another one is asked for.
+type BulkRequest struct {
+	Mode       string          `json:"mode" binding:"omitempty,oneof=atomic partial"`
+	Operations []BulkOperation `json:"operations" binding:"required,min=1,dive"`
+}
+
+// BulkOperation creates a todo from Todo, updates todo ID with Todo as
+// PUT /update_todolist does, or deletes todo ID. Todo is validated with the
+// operation, so a bad one only fails its own operation.
+type BulkOperation struct {
+	Op   string          `json:"op" binding:"required,oneof=create update delete"`
+	ID   int64           `json:"id"`
+	Todo json.RawMessage `json:"todo"`
+}
+
+// BulkResult is the outcome of the operation at Index, Status is the HTTP
+// status it would have had on its own endpoint.
+type BulkResult struct {
+	Index   int           `json:"index"`
+	Op      string        `json:"op"`
+	ID      int64         `json:"id,omitempty"`
+	Status  int           `json:"status"`
+	Message string        `json:"message"`
+	Data    *entity.Todos `json:"data,omitempty"`
+	// OperationID undoes an update or delete with POST /api/undo/:operationId
+	OperationID int64 `json:"operation_id,omitempty"`
+}
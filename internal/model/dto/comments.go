@@ -0,0 +1,26 @@
+package dto
+
+import "todolist_gin_gorm/internal/model/entity"
+
+// CommentRequest carries the Markdown body of a comment
+type CommentRequest struct {
+	Body string `json:"body" binding:"required,max=5000"`
+}
+
+type CommentResponse struct {
+	Status  int             `json:"status"`
+	Message string          `json:"message"`
+	Data    entity.Comments `json:"data"`
+}
+
+type CommentResponseGetAll struct {
+	Status  int               `json:"status"`
+	Message string            `json:"message"`
+	More    int               `json:"more"`
+	Data    []entity.Comments `json:"data"`
+}
+
+type CommentResponseDelete struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "todolist_gin_gorm/internal/model/entity"
+
+// ImportQuery describes an uploaded import file. With DryRun set the rows are
+// only checked, with ListID they are added to that list.
+type ImportQuery struct {
+	Format string `form:"format" binding:"required,oneof=csv json todoist trello"`
+	DryRun bool   `form:"dry_run"`
+	ListID int64  `form:"list_id"`
+}
+
+const (
+	// ImportValid rows would be created, reported by dry runs
+	ImportValid     = "valid"
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+)
+
+type ImportRow struct {
+	Row        int           `json:"row"`
+	ExternalID string        `json:"external_id,omitempty"`
+	Result     string        `json:"result"`
+	Errors     []string      `json:"errors,omitempty"`
+	Data       *entity.Todos `json:"data,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun     bool        `json:"dry_run"`
+	Created    int         `json:"created"`
+	Duplicates int         `json:"duplicates"`
+	Invalid    int         `json:"invalid"`
+	Rows       []ImportRow `json:"rows"`
+}
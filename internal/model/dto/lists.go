@@ -0,0 +1,58 @@
+package dto
+
+import "todolist_gin_gorm/internal/model/entity"
+
+type CreateListRequest struct {
+	Name   string `json:"name" binding:"required,max=99"`
+	SortBy string `json:"sort_by" binding:"omitempty,oneof=position created due_at priority title"`
+}
+
+type UpdateListRequest struct {
+	Name   *string `json:"name" binding:"omitempty,min=1,max=99"`
+	SortBy *string `json:"sort_by" binding:"omitempty,oneof=position created due_at priority title"`
+}
+
+func (request *UpdateListRequest) RequestUpdateList() map[string]interface{} {
+	update := make(map[string]interface{}, 0)
+	if request.Name != nil {
+		update["name"] = *request.Name
+	}
+
+	if request.SortBy != nil {
+		update["sort_by"] = *request.SortBy
+	}
+
+	return update
+}
+
+type ListQuery struct {
+	IncludeArchived bool `form:"include_archived"`
+}
+
+type MoveTodoRequest struct {
+	ListID int64 `json:"list_id" binding:"required"`
+}
+
+type ListResponse struct {
+	Status  int          `json:"status"`
+	Message string       `json:"message"`
+	Data    entity.Lists `json:"data"`
+}
+
+type ListResponseGetAll struct {
+	Status  int            `json:"status"`
+	Message string         `json:"message"`
+	More    int            `json:"more"`
+	Data    []entity.Lists `json:"data"`
+}
+
+type ListResponseDelete struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// RepositionTodoRequest places a todo right before or right after another one.
+type RepositionTodoRequest struct {
+	Before *int64 `json:"before"`
+	After  *int64 `json:"after"`
+}
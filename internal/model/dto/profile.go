@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+// UserResponse is the public view of entity.Users, it never carries the
+// password hash.
+type UserResponse struct {
+	UserID          int64      `json:"user_id"`
+	Username        string     `json:"username"`
+	Email           string     `json:"email"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	Role            string     `json:"role"`
+	DisabledAt      *time.Time `json:"disabled_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+func NewUserResponse(user *entity.Users) UserResponse {
+	return UserResponse{
+		UserID:          user.UserID,
+		Username:        user.Username,
+		Email:           user.Email,
+		EmailVerifiedAt: user.EmailVerifiedAt,
+		Role:            user.Role,
+		DisabledAt:      user.DisabledAt,
+		CreatedAt:       user.CreatedAt,
+		UpdatedAt:       user.UpdatedAt,
+	}
+}
+
+type ProfileResponse struct {
+	Status       int          `json:"status"`
+	Message      string       `json:"message"`
+	PendingEmail string       `json:"pending_email,omitempty"`
+	Data         UserResponse `json:"data"`
+}
+
+type DeleteAccountResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
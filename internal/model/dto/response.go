@@ -13,23 +13,53 @@ type TodolistResponseGetAll struct {
 	Message string         `json:"message"`
 	More    int            `json:"more"`
 	Data    []entity.Todos `json:"data"`
+	// OperationID undoes a change with POST /api/undo/:operationId
+	OperationID int64 `json:"operation_id,omitempty"`
 }
 
-type TodolistResponseGetID struct {
+type SearchResponse struct {
+	Status  int                `json:"status"`
+	Message string             `json:"message"`
+	More    int                `json:"more"`
+	Data    []entity.TodoMatch `json:"data"`
+}
+
+type BulkResponse struct {
 	Status  int          `json:"status"`
 	Message string       `json:"message"`
-	Data    entity.Todos `json:"data"`
+	Mode    string       `json:"mode"`
+	More    int          `json:"more"`
+	Data    []BulkResult `json:"data"`
+}
+
+type ImportResponse struct {
+	Status  int          `json:"status"`
+	Message string       `json:"message"`
+	Data    ImportReport `json:"data"`
+}
+
+type TodolistResponseGetID struct {
+	Status   int                     `json:"status"`
+	Message  string                  `json:"message"`
+	Data     entity.Todos            `json:"data"`
+	Progress *entity.SubtaskProgress `json:"progress,omitempty"`
+	// OperationID undoes a change with POST /api/undo/:operationId
+	OperationID int64 `json:"operation_id,omitempty"`
 }
 
 type TodolistResponseDelete struct {
 	Status  int    `json:"status"`
 	Message string `json:"message"`
+	// OperationID undoes the delete with POST /api/undo/:operationId
+	OperationID int64 `json:"operation_id,omitempty"`
 }
 
 type TodolistResponseUpdate struct {
 	Status  int         `json:"status"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
+	// OperationID undoes the update with POST /api/undo/:operationId
+	OperationID int64 `json:"operation_id,omitempty"`
 }
 
 type CreateUserResponse struct {
@@ -42,3 +72,81 @@ type UserLoginResponse struct {
 	Message string `json:"message"`
 	Token   string `json:"token"`
 }
+
+type EmailVerificationResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type PasswordResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type ChangePasswordResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+	Token   string `json:"token"`
+}
+
+type APIKeyResponseCreate struct {
+	Status  int            `json:"status"`
+	Message string         `json:"message"`
+	Key     string         `json:"key"`
+	Data    entity.APIKeys `json:"data"`
+}
+
+type APIKeyResponseGetAll struct {
+	Status  int              `json:"status"`
+	Message string           `json:"message"`
+	More    int              `json:"more"`
+	Data    []entity.APIKeys `json:"data"`
+}
+
+type APIKeyResponseDelete struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type TwoFactorChallengeResponse struct {
+	Status            int    `json:"status"`
+	Message           string `json:"message"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+type TwoFactorSetupResponse struct {
+	Status     int    `json:"status"`
+	Message    string `json:"message"`
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+type TwoFactorConfirmResponse struct {
+	Status        int      `json:"status"`
+	Message       string   `json:"message"`
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// SessionResponse marks the session the request was made with.
+type SessionResponse struct {
+	entity.Sessions
+	Current bool `json:"current"`
+}
+
+type SessionResponseGetAll struct {
+	Status  int               `json:"status"`
+	Message string            `json:"message"`
+	More    int               `json:"more"`
+	Data    []SessionResponse `json:"data"`
+}
+
+type SessionResponseDelete struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
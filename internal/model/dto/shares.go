@@ -0,0 +1,29 @@
+package dto
+
+import "todolist_gin_gorm/internal/model/entity"
+
+// ShareRequest shares a todo or list with another registered user. Sharing
+// again with the same user changes their role.
+type ShareRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=viewer editor"`
+}
+
+// ShareAcceptedResponse answers a share request alike whether or not the
+// email belongs to a user, the share shows up in the todo's or list's shares.
+type ShareAcceptedResponse struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+type ShareResponseGetAll struct {
+	Status  int             `json:"status"`
+	Message string          `json:"message"`
+	More    int             `json:"more"`
+	Data    []entity.Shares `json:"data"`
+}
+
+type ShareResponseDelete struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
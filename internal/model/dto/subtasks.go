@@ -0,0 +1,12 @@
+package dto
+
+type CreateSubtaskRequest struct {
+	Title       string `json:"title" binding:"required,min=2,max=99"`
+	Description string `json:"description" binding:"max=999"`
+	Priority    string `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+}
+
+// ReorderSubtasksRequest lists every subtask id of the parent in the new order.
+type ReorderSubtasksRequest struct {
+	Order []int64 `json:"order" binding:"required,min=1"`
+}
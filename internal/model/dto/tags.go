@@ -0,0 +1,26 @@
+package dto
+
+import "todolist_gin_gorm/internal/model/entity"
+
+// TagRequest names a tag, commas are refused because the todo filter uses them as separator.
+type TagRequest struct {
+	Name string `json:"name" binding:"required,max=50,excludesall=0x2C"`
+}
+
+type TagResponse struct {
+	Status  int         `json:"status"`
+	Message string      `json:"message"`
+	Data    entity.Tags `json:"data"`
+}
+
+type TagResponseGetAll struct {
+	Status  int           `json:"status"`
+	Message string        `json:"message"`
+	More    int           `json:"more"`
+	Data    []entity.Tags `json:"data"`
+}
+
+type TagResponseDelete struct {
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
@@ -1,17 +1,63 @@
 package dto
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/recurrence"
+)
+
+// ErrInvalidTransition is returned for a status change the workflow doesn't allow.
+var ErrInvalidTransition = errors.New("status can't change")
+
 type CreateTodolistRequest struct {
-	Title       string `json:"title" binding:"required,min=2"`
-	Description string `json:"description" binding:"required,min=4"`
+	Title       string     `json:"title" binding:"required,min=2"`
+	Description string     `json:"description" binding:"required,min=4"`
+	Status      string     `json:"status" binding:"omitempty,oneof=todo in_progress blocked done cancelled"`
+	Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	ListID      *int64     `json:"list_id"`
+	DueAt       *time.Time `json:"due_at"`
+	Timezone    string     `json:"timezone" binding:"omitempty,max=64"`
+	RemindAt    *time.Time `json:"remind_at"`
+	Recurrence  string     `json:"recurrence" binding:"omitempty,max=255"`
 }
 
+// UpdateTodolistRequest changes only the due date, reminder, timezone and
+// recurrence the request names. null clears the due date or reminder, an
+// empty recurrence ends the series.
 type UpdateTodolistRequest struct {
-	Title       string `json:"title" binding:"required,min=2"`
-	Description string `json:"description" binding:"required,min=4"`
-	Status      bool   `json:"status"`
+	Title       string       `json:"title" binding:"required,min=2"`
+	Description string       `json:"description" binding:"required,min=4"`
+	Status      *StatusInput `json:"status"`
+	Priority    string       `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	DueAt       NullableTime `json:"due_at"`
+	Timezone    *string      `json:"timezone" binding:"omitempty,max=64"`
+	RemindAt    NullableTime `json:"remind_at"`
+	Recurrence  *string      `json:"recurrence" binding:"omitempty,max=255"`
+}
+
+// NullableTime is a time of a partial update. Set tells a field that was
+// sent, possibly as null, from one that was left out.
+type NullableTime struct {
+	Set   bool
+	Value *time.Time
+}
+
+func (field *NullableTime) UnmarshalJSON(data []byte) error {
+	field.Set = true
+	return json.Unmarshal(data, &field.Value)
 }
 
-func (request *UpdateTodolistRequest) RequestUpdateTodolist() map[string]interface{} {
+func (field NullableTime) MarshalJSON() ([]byte, error) {
+	return json.Marshal(field.Value)
+}
+
+// RequestUpdateTodolist builds the update of current, moving its status only
+// along the allowed transitions.
+func (request *UpdateTodolistRequest) RequestUpdateTodolist(current *entity.Todos, now time.Time) (map[string]interface{}, error) {
 	update := make(map[string]interface{}, 0)
 	if request.Title != "" {
 		update["title"] = request.Title
@@ -21,7 +67,227 @@ func (request *UpdateTodolistRequest) RequestUpdateTodolist() map[string]interfa
 		update["description"] = request.Description
 	}
 
-	update["status"] = request.Status
+	if request.Priority != "" {
+		update["priority"] = request.Priority
+	}
+
+	if request.Status != nil {
+		from := StatusOrTodo(current.Status)
+		to := request.Status.Resolve(from)
+		if !entity.CanTransition(from, to) {
+			return nil, fmt.Errorf("%w: %s to %s", ErrInvalidTransition, from, to)
+		}
+
+		update["status"] = to
+		for key, value := range CompletionUpdate(from, to, now) {
+			update[key] = value
+		}
+	}
+
+	if request.DueAt.Set {
+		update["due_at"] = request.DueAt.Value
+	}
+	if request.Timezone != nil {
+		update["timezone"] = TimezoneOrUTC(*request.Timezone)
+	}
+	if request.RemindAt.Set {
+		update["remind_at"] = request.RemindAt.Value
+	}
+	if request.Recurrence != nil {
+		update["recurrence_rule"] = *request.Recurrence
+	}
+
+	return update, nil
+}
+
+// Updated returns current with the fields of the request applied.
+func (request *UpdateTodolistRequest) Updated(current *entity.Todos) *entity.Todos {
+	updated := *current
+	if request.Title != "" {
+		updated.Title = request.Title
+	}
+	if request.Description != "" {
+		updated.Description = request.Description
+	}
+	if request.Priority != "" {
+		updated.Priority = request.Priority
+	}
+
+	if request.DueAt.Set {
+		updated.DueAt = request.DueAt.Value
+	}
+	if request.Timezone != nil {
+		updated.Timezone = TimezoneOrUTC(*request.Timezone)
+	}
+	if request.RemindAt.Set {
+		updated.RemindAt = request.RemindAt.Value
+	}
+	if request.Recurrence != nil {
+		updated.RecurrenceRule = *request.Recurrence
+	}
+
+	return &updated
+}
+
+// StatusInput is the status of an update request. It takes a status name, or
+// true/false as sent by clients from before the workflow: true completes the
+// todo and false reopens a done one, leaving any other status alone.
+type StatusInput struct {
+	Name   string
+	Legacy bool
+	Done   bool
+}
+
+func (input *StatusInput) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &input.Done); err == nil {
+		input.Legacy = true
+		return nil
+	}
+
+	if err := json.Unmarshal(data, &input.Name); err != nil {
+		return errors.New("status must be a string or a boolean")
+	}
+
+	switch input.Name {
+	case entity.StatusTodo, entity.StatusInProgress, entity.StatusBlocked, entity.StatusDone, entity.StatusCancelled:
+		return nil
+	default:
+		return fmt.Errorf("unknown status %q", input.Name)
+	}
+}
+
+func (input StatusInput) MarshalJSON() ([]byte, error) {
+	if input.Legacy {
+		return json.Marshal(input.Done)
+	}
+
+	return json.Marshal(input.Name)
+}
+
+// Resolve returns the status to move to from current.
+func (input *StatusInput) Resolve(current string) string {
+	if !input.Legacy {
+		return input.Name
+	}
+
+	if input.Done {
+		return entity.StatusDone
+	}
+	if current == entity.StatusDone {
+		return entity.StatusTodo
+	}
+
+	return current
+}
+
+// CompletionUpdate stamps completed_at when a todo enters done and clears it
+// when it leaves.
+func CompletionUpdate(from string, to string, now time.Time) map[string]interface{} {
+	switch {
+	case to == entity.StatusDone && from != entity.StatusDone:
+		return map[string]interface{}{"completed_at": now}
+	case to != entity.StatusDone && from == entity.StatusDone:
+		return map[string]interface{}{"completed_at": nil}
+	default:
+		return nil
+	}
+}
+
+// StatusOrTodo treats a missing status as todo.
+func StatusOrTodo(status string) string {
+	if status == "" {
+		return entity.StatusTodo
+	}
+
+	return status
+}
+
+// TodoQuery filters the todo list. Times are RFC 3339 with an offset.
+type TodoQuery struct {
+	Status    string    `form:"status" binding:"omitempty,oneof=todo in_progress blocked done cancelled"`
+	Priority  string    `form:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	DueBefore time.Time `form:"due_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	DueAfter  time.Time `form:"due_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	Overdue   bool      `form:"overdue"`
+	ListID    int64     `form:"list_id"`
+	Sort      string    `form:"sort" binding:"omitempty,oneof=position created due_at priority title"`
+
+	// Tag is a comma separated list of the viewer's tag names, TagMode says
+	// whether a todo needs any (the default) or all of them
+	Tag     string `form:"tag"`
+	TagMode string `form:"tag_mode" binding:"omitempty,oneof=any all"`
+
+	// ViewerID is the user asking, only their own and shared todos are listed
+	ViewerID int64 `form:"-"`
+}
+
+// SearchQuery searches the titles and descriptions of the todos the viewer
+// can see, the filters of TodoQuery narrow the result down.
+type SearchQuery struct {
+	Q     string `form:"q" binding:"required,max=200"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	TodoQuery
+}
+
+// ExportQuery exports the todos the viewer can see, subtasks included, in
+// Format (csv, json or ics). The filters of TodoQuery apply, the sort doesn't.
+type ExportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv json ics"`
+	TodoQuery
+}
+
+const (
+	TagModeAny = "any"
+	TagModeAll = "all"
+)
+
+// TagNames splits the tag filter, dropping blanks and repeats.
+func (query *TodoQuery) TagNames() []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(query.Tag, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names
+}
+
+// ValidateRecurrence accepts an empty rule or one package recurrence
+// supports. A recurring todo needs a due date to repeat from.
+func ValidateRecurrence(rule string, dueAt *time.Time) error {
+	if rule == "" {
+		return nil
+	}
+
+	if _, err := recurrence.Parse(rule); err != nil {
+		return err
+	}
+	if dueAt == nil {
+		return errors.New("a recurring todo needs a due date")
+	}
+
+	return nil
+}
+
+// ValidateTimezone accepts an empty name (UTC) or an IANA zone like "Asia/Jakarta".
+func ValidateTimezone(name string) error {
+	// "Local" would mean the server's zone, which isn't something a client can rely on
+	if _, err := time.LoadLocation(name); err != nil || name == "Local" {
+		return errors.New("timezone must be an IANA time zone such as Asia/Jakarta")
+	}
+
+	return nil
+}
+
+func TimezoneOrUTC(name string) string {
+	if name == "" {
+		return "UTC"
+	}
 
-	return update
+	return name
 }
@@ -2,7 +2,7 @@ package dto
 
 import (
 	"errors"
-	"todolist_gin_gorm/internal/model/entity"
+	"time"
 )
 
 type RegisterRequest struct {
@@ -17,7 +17,7 @@ type LoginRequest struct {
 }
 
 // untuk validate register request
-func ValidateRegisterRequest(user *entity.Users) error {
+func ValidateRegisterRequest(user *RegisterRequest) error {
 	if user.Username == "" {
 		return errors.New("username is required")
 	}
@@ -32,10 +32,61 @@ func ValidateRegisterRequest(user *entity.Users) error {
 }
 
 // untuk validate login request
-func ValidateLoginRequest(user *entity.Users) error {
+func ValidateLoginRequest(user *LoginRequest) error {
 	if len(user.Email) < 2 || len(user.Password) < 2 {
 		return errors.New("email and password must be at least 2 characters long")
 	}
 
 	return nil
 }
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+type UpdateProfileRequest struct {
+	Username *string `json:"username" binding:"omitempty,min=2,max=55"`
+	Email    *string `json:"email" binding:"omitempty,email,max=55"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required,max=55"`
+	Scopes    []string   `json:"scopes" binding:"omitempty,dive,oneof=read write"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// DisableTwoFactorRequest takes either a TOTP code or a recovery code.
+type DisableTwoFactorRequest struct {
+	Password     string `json:"password" binding:"required"`
+	Code         string `json:"code" binding:"omitempty,len=6,numeric"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// LoginTwoFactorRequest takes either a TOTP code or a recovery code.
+type LoginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"omitempty,len=6,numeric"`
+	RecoveryCode   string `json:"recovery_code"`
+}
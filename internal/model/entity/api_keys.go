@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	ScopeRead  = "read"
+	ScopeWrite = "write"
+)
+
+// APIKeys is a personal key sent in the X-API-Key header. Only the hash is
+// stored, Prefix is kept so users can tell their keys apart.
+type APIKeys struct {
+	KeyID      int64      `gorm:"primaryKey" json:"key_id"`
+	UserID     int64      `json:"-"`
+	Name       string     `gorm:"type:varchar(55)" json:"name"`
+	Prefix     string     `gorm:"type:varchar(16)" json:"prefix"`
+	KeyHash    string     `gorm:"type:char(64);unique" json:"-"`
+	Scopes     string     `gorm:"type:varchar(55)" json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+func (key *APIKeys) HasScope(scope string) bool {
+	for _, s := range strings.Split(key.Scopes, ",") {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Usable reports whether the key is neither revoked nor expired.
+func (key *APIKeys) Usable(now time.Time) bool {
+	if key.RevokedAt != nil {
+		return false
+	}
+
+	return key.ExpiresAt == nil || now.Before(*key.ExpiresAt)
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Attachments are files uploaded to a todo. The content lives in the
+// configured storage under StorageKey.
+type Attachments struct {
+	AttachmentID int64     `gorm:"primaryKey" json:"attachment_id"`
+	TodoID       int64     `gorm:"column:todos_id" json:"todo_id"`
+	UserID       int64     `json:"user_id"`
+	FileName     string    `gorm:"type:varchar(255)" json:"file_name"`
+	ContentType  string    `gorm:"type:varchar(127)" json:"content_type"`
+	Size         int64     `json:"size"`
+	StorageKey   string    `gorm:"type:varchar(255)" json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+}
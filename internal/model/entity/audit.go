@@ -0,0 +1,57 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// entity types of audit events
+const (
+	AuditTodo       = "todo"
+	AuditList       = "list"
+	AuditUser       = "user"
+	AuditTag        = "tag"
+	AuditComment    = "comment"
+	AuditShare      = "share"
+	AuditAttachment = "attachment"
+	AuditAPIKey     = "api_key"
+	AuditSession    = "session"
+)
+
+// actions of audit events
+const (
+	AuditCreate   = "create"
+	AuditUpdate   = "update"
+	AuditDelete   = "delete"
+	AuditLogin    = "login"
+	AuditRegister = "register"
+	AuditUndo     = "undo"
+	AuditAttach   = "attach"
+	AuditDetach   = "detach"
+	AuditRevoke   = "revoke"
+
+	AuditChangePassword   = "change_password"
+	AuditResetPassword    = "reset_password"
+	AuditRequestReset     = "request_password_reset"
+	AuditVerifyEmail      = "verify_email"
+	AuditDisable          = "disable"
+	AuditEnable           = "enable"
+	AuditDisableTwoFactor = "disable_two_factor"
+	// admins reading other users' data is recorded too
+	AuditListUsers     = "list_users"
+	AuditViewUserTodos = "view_user_todos"
+)
+
+// AuditEvents records who changed what. Changes maps every changed field to
+// its value before and after, a create has no before and a delete no after.
+type AuditEvents struct {
+	EventID    int64           `gorm:"primaryKey" json:"event_id"`
+	ActorID    int64           `json:"actor_id"`
+	Action     string          `gorm:"type:varchar(32)" json:"action"`
+	EntityType string          `gorm:"type:varchar(32)" json:"entity_type"`
+	EntityID   int64           `json:"entity_id"`
+	Changes    json.RawMessage `gorm:"type:json" json:"changes"`
+	IPAddress  string          `gorm:"type:varchar(45)" json:"ip_address"`
+	RequestID  string          `gorm:"type:varchar(64)" json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
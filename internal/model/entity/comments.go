@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Comments are the discussion thread of a todo. The body is Markdown, it is
+// rendered to BodyHTML when the comment is returned.
+type Comments struct {
+	CommentID int64     `gorm:"primaryKey" json:"comment_id"`
+	TodoID    int64     `gorm:"column:todos_id" json:"todo_id"`
+	UserID    int64     `json:"user_id"`
+	Body      string    `gorm:"type:text" json:"body"`
+	BodyHTML  string    `gorm:"-" json:"body_html"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// EditedAt is set when the author changes the body
+	EditedAt  *time.Time     `json:"edited_at"`
+	DeletedAt gorm.DeletedAt `json:"-"`
+
+	// filled in when comments are listed
+	AuthorEmail string `gorm:"->" json:"author_email,omitempty"`
+}
+
+// Editable reports whether the comment can still be edited at now, a zero
+// window never closes.
+func (comment *Comments) Editable(now time.Time, window time.Duration) bool {
+	return window == 0 || now.Sub(comment.CreatedAt) <= window
+}
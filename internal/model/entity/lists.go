@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+const (
+	SortPosition = "position"
+	SortCreated  = "created"
+	SortDueAt    = "due_at"
+	SortPriority = "priority"
+	SortTitle    = "title"
+)
+
+// Lists groups todos into projects. Every user has one inbox list, it holds
+// todos created without a list and can't be archived or deleted. SortBy is
+// the order the list shows its todos in.
+type Lists struct {
+	ListID     int64      `gorm:"primaryKey" json:"list_id"`
+	UserID     int64      `json:"user_id"`
+	Name       string     `gorm:"type:varchar(99)" json:"name"`
+	IsInbox    bool       `json:"is_inbox"`
+	SortBy     string     `gorm:"type:varchar(16);default:position" json:"sort_by"`
+	ArchivedAt *time.Time `json:"archived_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+func NewInboxList(userID int64) *Lists {
+	return &Lists{
+		UserID:  userID,
+		Name:    "Inbox",
+		IsInbox: true,
+		SortBy:  SortPosition,
+	}
+}
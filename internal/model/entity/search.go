@@ -0,0 +1,11 @@
+package entity
+
+// TodoMatch is a todo found by a search, Score is its relevance.
+type TodoMatch struct {
+	Todos `gorm:"embedded"`
+	Score float64 `json:"score"`
+
+	// TitleHighlight and Snippet are HTML with the search terms in <mark>
+	TitleHighlight string `gorm:"-" json:"title_highlight"`
+	Snippet        string `gorm:"-" json:"snippet"`
+}
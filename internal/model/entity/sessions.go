@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Sessions records every access token handed out, so a user can see where
+// they are logged in and revoke a single device. TokenVersion is the user's
+// version when the token was signed, a session from an older version is dead.
+type Sessions struct {
+	SessionID    int64      `gorm:"primaryKey" json:"session_id"`
+	UserID       int64      `json:"-"`
+	JTI          string     `gorm:"column:jti;type:char(64);unique" json:"-"`
+	TokenVersion int        `json:"-"`
+	IPAddress    string     `gorm:"type:varchar(45)" json:"ip_address"`
+	UserAgent    string     `gorm:"type:varchar(255)" json:"user_agent"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastSeenAt   time.Time  `json:"last_seen_at"`
+	ExpiresAt    time.Time  `json:"expires_at"`
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+}
+
+// Active reports whether the session is neither revoked nor expired.
+func (session *Sessions) Active(now time.Time) bool {
+	return session.RevokedAt == nil && now.Before(session.ExpiresAt)
+}
@@ -0,0 +1,41 @@
+package entity
+
+import "time"
+
+// Share roles. Viewers can read, editors can also change the todos, only the
+// owner can delete, move, tag or share them.
+const (
+	ShareViewer = "viewer"
+	ShareEditor = "editor"
+	ShareOwner  = "owner"
+)
+
+var shareRoleLevels = map[string]int{
+	ShareViewer: 1,
+	ShareEditor: 2,
+	ShareOwner:  3,
+}
+
+// ShareRoleAllows reports whether role grants at least the access of required.
+func ShareRoleAllows(role string, required string) bool {
+	return shareRoleLevels[role] >= shareRoleLevels[required] && shareRoleLevels[role] > 0
+}
+
+// Shares gives a user access to someone else's todo, with its subtasks, or
+// to a whole list. Exactly one of TodoID and ListID is set.
+type Shares struct {
+	ShareID   int64     `gorm:"primaryKey" json:"share_id"`
+	OwnerID   int64     `json:"owner_id"`
+	UserID    int64     `json:"user_id"`
+	TodoID    *int64    `gorm:"column:todos_id" json:"todo_id"`
+	ListID    *int64    `json:"list_id"`
+	Role      string    `gorm:"type:varchar(8);default:viewer" json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// filled in when shares are listed
+	OwnerEmail string `gorm:"->" json:"owner_email,omitempty"`
+	UserEmail  string `gorm:"->" json:"user_email,omitempty"`
+	Todo       *Todos `gorm:"foreignKey:TodoID;references:Id" json:"todo,omitempty"`
+	List       *Lists `gorm:"foreignKey:ListID;references:ListID" json:"list,omitempty"`
+}
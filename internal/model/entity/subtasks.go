@@ -0,0 +1,11 @@
+package entity
+
+// MaxSubtaskDepth is how many levels a todo tree may have, the top level todo included.
+const MaxSubtaskDepth = 3
+
+// SubtaskProgress counts the direct subtasks of a todo. Cancelled subtasks
+// are left out of both numbers.
+type SubtaskProgress struct {
+	Done  int64 `json:"done"`
+	Total int64 `json:"total"`
+}
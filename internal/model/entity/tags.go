@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Tags are labels a user puts on their todos, names are unique per user.
+type Tags struct {
+	TagID     int64     `gorm:"primaryKey" json:"tag_id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `gorm:"type:varchar(50)" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TodoTags struct {
+	TodoID    int64     `gorm:"primaryKey;column:todos_id" json:"todo_id"`
+	TagID     int64     `gorm:"primaryKey" json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
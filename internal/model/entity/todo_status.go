@@ -0,0 +1,52 @@
+package entity
+
+const (
+	StatusTodo       = "todo"
+	StatusInProgress = "in_progress"
+	StatusBlocked    = "blocked"
+	StatusDone       = "done"
+	StatusCancelled  = "cancelled"
+)
+
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// statusTransitions lists where a todo may go from each status. Done and
+// cancelled todos can only be reopened.
+var statusTransitions = map[string][]string{
+	StatusTodo:       {StatusInProgress, StatusBlocked, StatusDone, StatusCancelled},
+	StatusInProgress: {StatusTodo, StatusBlocked, StatusDone, StatusCancelled},
+	StatusBlocked:    {StatusTodo, StatusInProgress, StatusCancelled},
+	StatusDone:       {StatusTodo},
+	StatusCancelled:  {StatusTodo},
+}
+
+// CanTransition reports whether a todo may move from one status to another.
+// Staying in the same status is always allowed.
+func CanTransition(from string, to string) bool {
+	if from == to {
+		return true
+	}
+
+	for _, status := range statusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsOpenStatus reports whether work on a todo with this status is still expected.
+func IsOpenStatus(status string) bool {
+	return status != StatusDone && status != StatusCancelled
+}
+
+// IsClosing reports whether moving from one status to another ends the work on a todo.
+func IsClosing(from string, to string) bool {
+	return IsOpenStatus(from) && !IsOpenStatus(to)
+}
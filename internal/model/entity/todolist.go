@@ -1,8 +1,79 @@
 package entity
 
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
 type Todos struct {
-	Id          int64  `gorm:"primaryKey;column:todos_id" json:"id"`
+	Id     int64  `gorm:"primaryKey;column:todos_id" json:"id"`
+	UserID int64  `gorm:"column:user_id;default:null" json:"user_id"`
+	ListID *int64 `gorm:"column:list_id" json:"list_id"`
+
+	// ParentID is set on subtasks, SubtaskOrder orders them under their parent
+	ParentID     *int64 `gorm:"column:parent_id" json:"parent_id"`
+	SubtaskOrder int    `json:"subtask_order"`
+
+	// Position is the todo's rank key in its list, see package rank
+	Position string `gorm:"type:varchar(255)" json:"position"`
+
 	Title       string `gorm:"type:varchar(99)" json:"title"`
 	Description string `gorm:"type:varchar(999)" json:"description"`
-	Status      bool   `gorm:"default:false" json:"status"`
+	Status      string `gorm:"type:varchar(16);default:todo" json:"status"`
+	Priority    string `gorm:"type:varchar(8);default:medium" json:"priority"`
+
+	// CompletedAt is set when the todo enters done and cleared when it leaves
+	CompletedAt *time.Time `json:"completed_at"`
+
+	// DueAt and RemindAt are stored as instants, Timezone is the IANA zone
+	// they are shown in
+	DueAt      *time.Time `json:"due_at"`
+	Timezone   string     `gorm:"type:varchar(64);default:UTC" json:"timezone"`
+	RemindAt   *time.Time `json:"remind_at"`
+	RemindedAt *time.Time `json:"reminded_at"`
+
+	// ReminderAttempts counts the failed deliveries of the reminder, it isn't
+	// tried again before ReminderRetryAt
+	ReminderAttempts int        `gorm:"default:0" json:"-"`
+	ReminderRetryAt  *time.Time `json:"-"`
+
+	// RecurrenceRule is an iCalendar RRULE, completing the todo creates the
+	// next occurrence. Occurrence counts the todos of the series so far.
+	RecurrenceRule string `gorm:"type:varchar(255)" json:"recurrence"`
+	Occurrence     int    `gorm:"default:1" json:"occurrence"`
+
+	// ExternalID is "<source>:<id>" for imported todos, importing the same
+	// item again is skipped
+	ExternalID *string `gorm:"type:varchar(128)" json:"external_id,omitempty"`
+
+	// Tags holds the names of the todo's tags, it is filled by the repository
+	Tags []string `gorm:"-" json:"tags"`
+
+	// CommentCount is filled by the repository when todos are listed
+	CommentCount int64 `gorm:"-" json:"comment_count"`
+}
+
+// Overdue reports whether the todo is still open after its due date.
+func (todo *Todos) Overdue(now time.Time) bool {
+	return IsOpenStatus(todo.Status) && todo.DueAt != nil && todo.DueAt.Before(now)
+}
+
+// AfterFind shows the dates in the todo's own timezone.
+func (todo *Todos) AfterFind(tx *gorm.DB) error {
+	location, err := time.LoadLocation(todo.Timezone)
+	if err != nil || todo.Timezone == "" {
+		return nil
+	}
+
+	if todo.DueAt != nil {
+		dueAt := todo.DueAt.In(location)
+		todo.DueAt = &dueAt
+	}
+	if todo.RemindAt != nil {
+		remindAt := todo.RemindAt.In(location)
+		todo.RemindAt = &remindAt
+	}
+
+	return nil
 }
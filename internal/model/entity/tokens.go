@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// EmailVerificationTokens holds the hash of a token mailed to Email; the
+// plain token only ever exists in the verification link.
+type EmailVerificationTokens struct {
+	TokenID   int64  `gorm:"primaryKey"`
+	UserID    int64  `gorm:"index"`
+	Email     string `gorm:"type:varchar(55)"`
+	TokenHash string `gorm:"type:char(64);unique"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (token *EmailVerificationTokens) Expired(now time.Time) bool {
+	return !now.Before(token.ExpiresAt)
+}
+
+// PasswordResetTokens is a single-use token mailed by /password/forgot.
+type PasswordResetTokens struct {
+	TokenID   int64  `gorm:"primaryKey"`
+	UserID    int64  `gorm:"index"`
+	TokenHash string `gorm:"type:char(64);unique"`
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+func (token *PasswordResetTokens) Expired(now time.Time) bool {
+	return !now.Before(token.ExpiresAt)
+}
+
+// RecoveryCodes are one-time codes that stand in for a TOTP code.
+type RecoveryCodes struct {
+	CodeID    int64  `gorm:"primaryKey"`
+	UserID    int64  `gorm:"index"`
+	CodeHash  string `gorm:"type:char(64)"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// TwoFactorChallenges backs a challenge token, its jti. The first successful
+// /login/2fa uses it up and too many wrong codes lock it.
+type TwoFactorChallenges struct {
+	ChallengeID int64  `gorm:"primaryKey"`
+	UserID      int64  `gorm:"index"`
+	JTI         string `gorm:"column:jti;type:char(64);unique"`
+	Attempts    int
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	CreatedAt   time.Time
+}
@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// UndoOperations keep what an update or delete of a todo overwrote, so the
+// user who did it can revert it until ExpiresAt. Snapshot holds an
+// UndoSnapshot, Result the todo as an update left it.
+type UndoOperations struct {
+	OperationID int64           `gorm:"primaryKey" json:"operation_id"`
+	UserID      int64           `json:"user_id"`
+	Action      string          `gorm:"type:varchar(16)" json:"action"`
+	TodoID      int64           `gorm:"column:todos_id" json:"todo_id"`
+	Snapshot    json.RawMessage `gorm:"type:json" json:"-"`
+	Result      json.RawMessage `gorm:"type:json" json:"-"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+	UndoneAt    *time.Time      `json:"undone_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// UndoSnapshot is the state an undo restores. It is stored as JSON, with the
+// reminder retry state Todos leaves out of its own.
+type UndoSnapshot struct {
+	// Todos are the todo and, for a delete, its subtasks, parents first
+	Todos    []Todos    `json:"todos"`
+	TodoTags []TodoTags `json:"todo_tags"`
+	Comments []Comments `json:"comments"`
+	Shares   []Shares   `json:"shares"`
+}
+
+// undoReminder is the reminder retry state of one of the snapshot's todos.
+type undoReminder struct {
+	TodoID   int64      `json:"todo_id"`
+	Attempts int        `json:"attempts"`
+	RetryAt  *time.Time `json:"retry_at"`
+}
+
+// undoSnapshotJSON is how an UndoSnapshot is stored.
+type undoSnapshotJSON struct {
+	snapshotFields
+	Reminders []undoReminder `json:"reminders"`
+}
+
+// snapshotFields has the fields of UndoSnapshot without its JSON methods.
+type snapshotFields UndoSnapshot
+
+func (snapshot UndoSnapshot) MarshalJSON() ([]byte, error) {
+	stored := undoSnapshotJSON{snapshotFields: snapshotFields(snapshot)}
+	for _, todo := range snapshot.Todos {
+		stored.Reminders = append(stored.Reminders, undoReminder{
+			TodoID:   todo.Id,
+			Attempts: todo.ReminderAttempts,
+			RetryAt:  todo.ReminderRetryAt,
+		})
+	}
+
+	return json.Marshal(stored)
+}
+
+func (snapshot *UndoSnapshot) UnmarshalJSON(data []byte) error {
+	var stored undoSnapshotJSON
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return err
+	}
+
+	reminders := make(map[int64]undoReminder, len(stored.Reminders))
+	for _, reminder := range stored.Reminders {
+		reminders[reminder.TodoID] = reminder
+	}
+
+	*snapshot = UndoSnapshot(stored.snapshotFields)
+	for i := range snapshot.Todos {
+		if reminder, ok := reminders[snapshot.Todos[i].Id]; ok {
+			snapshot.Todos[i].ReminderAttempts = reminder.Attempts
+			snapshot.Todos[i].ReminderRetryAt = reminder.RetryAt
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// UserIdentities links a user to an account at an external identity provider.
+type UserIdentities struct {
+	IdentityID int64  `gorm:"primaryKey"`
+	UserID     int64  `gorm:"index"`
+	Provider   string `gorm:"type:varchar(55)"`
+	Subject    string `gorm:"type:varchar(255)"`
+	Email      string `gorm:"type:varchar(55)"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
@@ -2,11 +2,29 @@ package entity
 
 import "time"
 
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type Users struct {
-	UserID    int64     `gorm:"primaryKey" json:"user_id"`
-	Username  string    `gorm:"type:varchar(55)" json:"username"`
-	Password  string    `json:"password"`
-	Email     string    `gorm:"unique" json:"email"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	UserID             int64      `gorm:"primaryKey" json:"user_id"`
+	Username           string     `gorm:"type:varchar(55)" json:"username"`
+	Password           string     `json:"-"`
+	TokenVersion       int        `gorm:"default:0" json:"-"`
+	Email              string     `gorm:"unique" json:"email"`
+	EmailVerifiedAt    *time.Time `json:"email_verified_at"`
+	Role               string     `gorm:"type:varchar(20);default:user" json:"role"`
+	DisabledAt         *time.Time `json:"disabled_at"`
+	TOTPSecret         *string    `gorm:"column:totp_secret;type:varchar(64)" json:"-"`
+	TOTPLastStep       int64      `gorm:"column:totp_last_step;default:0" json:"-"`
+	TwoFactorEnabledAt *time.Time `json:"two_factor_enabled_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// TwoFactorEnabled reports whether login needs a second factor. A secret
+// without TwoFactorEnabledAt is an enrollment that was never confirmed.
+func (user *Users) TwoFactorEnabled() bool {
+	return user.TwoFactorEnabledAt != nil && user.TOTPSecret != nil
 }
@@ -0,0 +1,35 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"todolist_gin_gorm/internal/mailer"
+)
+
+// EmailNotifier mails the reminder to the owner of the todo.
+type EmailNotifier struct {
+	mailer mailer.Mailer
+}
+
+func NewEmailNotifier(mail mailer.Mailer) *EmailNotifier {
+	return &EmailNotifier{mailer: mail}
+}
+
+func (notifier *EmailNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	if reminder.User == nil {
+		return fmt.Errorf("todo %d has no owner: %w", reminder.Todo.Id, ErrUndeliverable)
+	}
+
+	due := "it has no due date"
+	if reminder.Todo.DueAt != nil {
+		due = "it is due " + reminder.Todo.DueAt.Format(time.RFC1123)
+	}
+
+	return notifier.mailer.Send(ctx, mailer.Message{
+		To:      reminder.User.Email,
+		Subject: "Reminder: " + reminder.Todo.Title,
+		Body: fmt.Sprintf("Hello %s,\n\nthis is your reminder for \"%s\", %s.\n\n%s\n",
+			reminder.User.Username, reminder.Todo.Title, due, reminder.Todo.Description),
+	})
+}
@@ -0,0 +1,25 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LogNotifier only logs reminders, it stands in for a real channel during
+// development.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (notifier *LogNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	logrus.WithFields(logrus.Fields{
+		"todo_id": reminder.Todo.Id,
+		"user_id": reminder.Todo.UserID,
+		"due_at":  reminder.Todo.DueAt,
+	}).Infof("reminder: %s", reminder.Todo.Title)
+
+	return nil
+}
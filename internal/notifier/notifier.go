@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/mailer"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+// ErrUndeliverable is returned when a reminder can never be delivered, e.g.
+// a todo without an owner to email, so there is no point retrying it.
+var ErrUndeliverable = errors.New("reminder can't be delivered")
+
+// Reminder is a todo whose reminder time has come. User is nil for todos
+// created before todos had owners.
+type Reminder struct {
+	Todo entity.Todos
+	User *entity.Users
+}
+
+// Notifier delivers todo reminders.
+type Notifier interface {
+	Notify(ctx context.Context, reminder Reminder) error
+}
+
+// New returns the notifier selected by cfg.ReminderNotifier.
+func New(cfg *config.Config, mail mailer.Mailer) (Notifier, error) {
+	switch cfg.ReminderNotifier {
+	case "webhook":
+		if cfg.ReminderWebhookURL == "" {
+			return nil, errors.New("REMINDER_WEBHOOK_URL is required for the webhook notifier")
+		}
+		return NewWebhookNotifier(cfg.ReminderWebhookURL, cfg.ReminderWebhookSecret, nil), nil
+	case "email":
+		return NewEmailNotifier(mail), nil
+	case "log", "":
+		return NewLogNotifier(), nil
+	default:
+		return nil, fmt.Errorf("unknown reminder notifier %q", cfg.ReminderNotifier)
+	}
+}
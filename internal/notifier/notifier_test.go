@@ -0,0 +1,75 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todolist_gin_gorm/internal/mailer"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifierSignsPayload(t *testing.T) {
+	var payload WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		body, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, "sha256="+Sign("shh", body), request.Header.Get(SignatureHeader))
+		require.NoError(t, json.Unmarshal(body, &payload))
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "shh", server.Client())
+	err := notifier.Notify(context.Background(), Reminder{Todo: entity.Todos{Id: 1, Title: "sholat"}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "todo.reminder", payload.Event)
+	assert.Equal(t, int64(1), payload.Todo.Id)
+}
+
+func TestWebhookNotifierFailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "", server.Client())
+	err := notifier.Notify(context.Background(), Reminder{Todo: entity.Todos{Id: 1}})
+
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUndeliverable))
+}
+
+type recordingMailer struct {
+	messages []mailer.Message
+}
+
+func (recorder *recordingMailer) Send(ctx context.Context, message mailer.Message) error {
+	recorder.messages = append(recorder.messages, message)
+	return nil
+}
+
+func TestEmailNotifier(t *testing.T) {
+	mail := &recordingMailer{}
+	notifier := NewEmailNotifier(mail)
+
+	err := notifier.Notify(context.Background(), Reminder{Todo: entity.Todos{Id: 1, Title: "sholat"}})
+	assert.True(t, errors.Is(err, ErrUndeliverable))
+
+	err = notifier.Notify(context.Background(), Reminder{
+		Todo: entity.Todos{Id: 1, Title: "sholat"},
+		User: &entity.Users{Username: "alwi", Email: "alwi@mail.com"},
+	})
+	require.NoError(t, err)
+	require.Len(t, mail.messages, 1)
+	assert.Equal(t, "alwi@mail.com", mail.messages[0].To)
+	assert.Equal(t, "Reminder: sholat", mail.messages[0].Subject)
+}
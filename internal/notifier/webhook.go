@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of the body, keyed with the
+// webhook secret, so the receiver can check the request came from us.
+const SignatureHeader = "X-Todolist-Signature"
+
+// WebhookNotifier posts reminders as JSON to a URL.
+type WebhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+// WebhookPayload is the body of a reminder webhook.
+type WebhookPayload struct {
+	Event  string       `json:"event"`
+	SentAt time.Time    `json:"sent_at"`
+	Todo   entity.Todos `json:"todo"`
+}
+
+// NewWebhookNotifier uses a client with a 10 second timeout when client is nil.
+func NewWebhookNotifier(url string, secret string, client *http.Client) *WebhookNotifier {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &WebhookNotifier{url: url, secret: secret, client: client}
+}
+
+func (notifier *WebhookNotifier) Notify(ctx context.Context, reminder Reminder) error {
+	body, err := json.Marshal(WebhookPayload{
+		Event:  "todo.reminder",
+		SentAt: time.Now().UTC(),
+		Todo:   reminder.Todo,
+	})
+	if err != nil {
+		return err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, notifier.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if notifier.secret != "" {
+		request.Header.Set(SignatureHeader, "sha256="+Sign(notifier.secret, body))
+	}
+
+	response, err := notifier.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered %s", response.Status)
+	}
+
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of body keyed with secret.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,179 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// keySet caches the issuer's signing keys and refreshes them when a token
+// names a key id it hasn't seen yet.
+type keySet struct {
+	uri        string
+	httpClient *http.Client
+
+	mutex sync.Mutex
+	keys  map[string]*rsa.PublicKey
+}
+
+func newKeySet(uri string, httpClient *http.Client) *keySet {
+	return &keySet{
+		uri:        uri,
+		httpClient: httpClient,
+		keys:       map[string]*rsa.PublicKey{},
+	}
+}
+
+func (set *keySet) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	set.mutex.Lock()
+	defer set.mutex.Unlock()
+
+	if key, ok := set.keys[kid]; ok {
+		return key, nil
+	}
+
+	if err := set.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("oidc: unknown signing key %q", kid)
+	}
+
+	return key, nil
+}
+
+func (set *keySet) refresh(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, set.uri, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := set.httpClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("oidc jwks: %w", err)
+	}
+	defer response.Body.Close()
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&document); err != nil {
+		return fmt.Errorf("oidc jwks: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, webKey := range document.Keys {
+		if webKey.Kty != "RSA" {
+			continue
+		}
+
+		n, err := base64.RawURLEncoding.DecodeString(webKey.N)
+		if err != nil {
+			return fmt.Errorf("oidc jwks: key %q: %w", webKey.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(webKey.E)
+		if err != nil {
+			return fmt.Errorf("oidc jwks: key %q: %w", webKey.Kid, err)
+		}
+
+		keys[webKey.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+
+	set.keys = keys
+	return nil
+}
+
+type idTokenClaims struct {
+	Issuer        string      `json:"iss"`
+	Subject       string      `json:"sub"`
+	Audience      interface{} `json:"aud"`
+	ExpiresAt     int64       `json:"exp"`
+	IssuedAt      int64       `json:"iat"`
+	Nonce         string      `json:"nonce"`
+	Email         string      `json:"email"`
+	EmailVerified bool        `json:"email_verified"`
+	Name          string      `json:"name"`
+}
+
+// Valid is called by jwt-go after the signature has been checked.
+func (claims *idTokenClaims) Valid() error {
+	if claims.ExpiresAt == 0 || time.Now().Unix() >= claims.ExpiresAt {
+		return errors.New("id token is expired")
+	}
+
+	return nil
+}
+
+func (claims *idTokenClaims) hasAudience(clientID string) bool {
+	switch audience := claims.Audience.(type) {
+	case string:
+		return audience == clientID
+	case []interface{}:
+		for _, value := range audience {
+			if value == clientID {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (provider *OIDCProvider) verifyIDToken(ctx context.Context, rawToken string, nonce string) (*Identity, error) {
+	claims := &idTokenClaims{}
+	_, err := jwt.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method.Alg() != jwt.SigningMethodRS256.Alg() {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		return provider.keys.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("oidc: invalid id token: %w", err)
+	}
+
+	if claims.Issuer != provider.discovery.Issuer {
+		return nil, fmt.Errorf("oidc: id token issued by %q", claims.Issuer)
+	}
+
+	if !claims.hasAudience(provider.config.ClientID) {
+		return nil, errors.New("oidc: id token is not meant for this client")
+	}
+
+	if claims.Nonce != nonce {
+		return nil, errors.New("oidc: id token nonce mismatch")
+	}
+
+	if claims.Subject == "" {
+		return nil, errors.New("oidc: id token has no subject")
+	}
+
+	return &Identity{
+		Subject:       claims.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Name:          claims.Name,
+	}, nil
+}
@@ -0,0 +1,105 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/oidc"
+	"todolist_gin_gorm/internal/oidc/oidctest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// login follows the provider's redirect and returns the code it hands back.
+func login(t *testing.T, provider oidc.Provider, state *oidc.LoginState) url.Values {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+
+	response, err := client.Get(provider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()))
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusFound, response.StatusCode)
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	require.NoError(t, err)
+
+	return location.Query()
+}
+
+func TestAuthorizationCodeFlowWithPKCE(t *testing.T) {
+	server := oidctest.NewServer("todolist")
+	defer server.Close()
+	server.Identity = oidc.Identity{Subject: "sso|42", Email: "alwi@corp.com", EmailVerified: true, Name: "Alwi"}
+
+	provider, err := oidc.Discover(context.Background(), oidc.Config{
+		Name:        "sso",
+		Issuer:      server.URL,
+		ClientID:    "todolist",
+		RedirectURL: "http://localhost:1234/auth/oidc/callback",
+	}, nil)
+	require.NoError(t, err)
+
+	state, err := oidc.NewLoginState(time.Minute)
+	require.NoError(t, err)
+
+	callback := login(t, provider, state)
+	assert.Equal(t, state.State, callback.Get("state"))
+
+	identity, err := provider.Exchange(context.Background(), callback.Get("code"), state.CodeVerifier, state.Nonce)
+	require.NoError(t, err)
+	assert.Equal(t, "sso|42", identity.Subject)
+	assert.Equal(t, "alwi@corp.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+}
+
+func TestExchangeRejectsWrongVerifierAndNonce(t *testing.T) {
+	server := oidctest.NewServer("todolist")
+	defer server.Close()
+	server.Identity = oidc.Identity{Subject: "sso|42"}
+
+	provider, err := oidc.Discover(context.Background(), oidc.Config{
+		Issuer:      server.URL,
+		ClientID:    "todolist",
+		RedirectURL: "http://localhost:1234/auth/oidc/callback",
+	}, nil)
+	require.NoError(t, err)
+
+	state, err := oidc.NewLoginState(time.Minute)
+	require.NoError(t, err)
+
+	callback := login(t, provider, state)
+	_, err = provider.Exchange(context.Background(), callback.Get("code"), "wrong-verifier", state.Nonce)
+	assert.Error(t, err)
+
+	callback = login(t, provider, state)
+	_, err = provider.Exchange(context.Background(), callback.Get("code"), state.CodeVerifier, "wrong-nonce")
+	assert.Error(t, err)
+}
+
+func TestLoginStateRoundTrip(t *testing.T) {
+	secret := []byte("state-secret")
+
+	state, err := oidc.NewLoginState(time.Minute)
+	require.NoError(t, err)
+
+	encoded, err := state.Encode(secret)
+	require.NoError(t, err)
+
+	decoded, err := oidc.DecodeLoginState(secret, encoded)
+	require.NoError(t, err)
+	assert.Equal(t, state, decoded)
+
+	_, err = oidc.DecodeLoginState([]byte("other-secret"), encoded)
+	assert.ErrorIs(t, err, oidc.ErrInvalidState)
+
+	expired, err := oidc.NewLoginState(-time.Minute)
+	require.NoError(t, err)
+	encoded, err = expired.Encode(secret)
+	require.NoError(t, err)
+	_, err = oidc.DecodeLoginState(secret, encoded)
+	assert.ErrorIs(t, err, oidc.ErrInvalidState)
+}
@@ -0,0 +1,96 @@
+package oidc
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// LoginState is carried from the login redirect to the callback in a signed
+// cookie, so nothing has to be stored server side.
+type LoginState struct {
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+var ErrInvalidState = errors.New("oidc: invalid or expired login state")
+
+// NewLoginState generates fresh state, nonce and PKCE verifier values.
+func NewLoginState(ttl time.Duration) (*LoginState, error) {
+	values := make([]string, 3)
+	for i := range values {
+		value, err := randomString()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+
+	return &LoginState{
+		State:        values[0],
+		Nonce:        values[1],
+		CodeVerifier: values[2],
+		ExpiresAt:    time.Now().Add(ttl).Unix(),
+	}, nil
+}
+
+// CodeChallenge is the S256 PKCE challenge for the state's verifier.
+func (state *LoginState) CodeChallenge() string {
+	sum := sha256.Sum256([]byte(state.CodeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+func (state *LoginState) Encode(secret []byte) (string, error) {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + sign(secret, encoded), nil
+}
+
+func DecodeLoginState(secret []byte, value string) (*LoginState, error) {
+	encoded, signature, found := strings.Cut(value, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(sign(secret, encoded))) {
+		return nil, ErrInvalidState
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidState
+	}
+
+	var state LoginState
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return nil, ErrInvalidState
+	}
+
+	if time.Now().Unix() >= state.ExpiresAt {
+		return nil, ErrInvalidState
+	}
+
+	return &state, nil
+}
+
+func sign(secret []byte, value string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func randomString() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
@@ -0,0 +1,124 @@
+// Package rank generates lexicographic position keys. A key can always be
+// made between two others, so moving one item never renumbers its neighbours.
+//
+// Keys use the digits 0-9a-z and never end in '0': nothing sorts between
+// "a" and "a0", so such a key would leave no room in front of it.
+package rank
+
+import (
+	"errors"
+	"strings"
+)
+
+const digits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// Initial is the key of the first item of an empty collection. It sits in the
+// middle of the key space so items can be added on either side.
+const Initial = "i0000001"
+
+// ErrInvalidRange is returned when the keys are out of order or malformed.
+var ErrInvalidRange = errors.New("rank: invalid key range")
+
+// Between returns a key sorting strictly after before and before after. An
+// empty key leaves that side open.
+func Between(before string, after string) (string, error) {
+	if !valid(before) || !valid(after) || (before != "" && after != "" && before >= after) {
+		return "", ErrInvalidRange
+	}
+
+	switch {
+	case before == "" && after == "":
+		return Initial, nil
+	case after == "":
+		if key, ok := step(before, 1); ok {
+			return key, nil
+		}
+	case before == "":
+		if key, ok := step(after, -1); ok {
+			return key, nil
+		}
+	}
+
+	return midpoint(before, after), nil
+}
+
+// step adds delta to key read as a base 36 number of the same length, which
+// keeps keys short when items are appended or prepended one after another.
+func step(key string, delta int) (string, bool) {
+	value := []byte(key)
+	for {
+		i := len(value) - 1
+		for ; i >= 0; i-- {
+			digit := strings.IndexByte(digits, value[i]) + delta
+			if digit >= 0 && digit < len(digits) {
+				value[i] = digits[digit]
+				break
+			}
+			value[i] = digits[(digit+len(digits))%len(digits)]
+		}
+		if i < 0 {
+			return "", false
+		}
+
+		if value[len(value)-1] != digits[0] {
+			return string(value), true
+		}
+	}
+}
+
+// midpoint returns a key between a and b, b empty meaning no upper bound.
+func midpoint(a string, b string) string {
+	if b != "" {
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			return b[:n] + midpoint(suffix(a, n), b[n:])
+		}
+	}
+
+	digitA := 0
+	if a != "" {
+		digitA = strings.IndexByte(digits, a[0])
+	}
+	digitB := len(digits)
+	if b != "" {
+		digitB = strings.IndexByte(digits, b[0])
+	}
+
+	if digitB-digitA > 1 {
+		return string(digits[(digitA+digitB+1)/2])
+	}
+	if len(b) > 1 {
+		return b[:1]
+	}
+
+	return string(digits[digitA]) + midpoint(suffix(a, 1), "")
+}
+
+func digitAt(key string, i int) byte {
+	if i < len(key) {
+		return key[i]
+	}
+
+	return digits[0]
+}
+
+func suffix(key string, n int) string {
+	if n >= len(key) {
+		return ""
+	}
+
+	return key[n:]
+}
+
+func valid(key string) bool {
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return false
+		}
+	}
+
+	return !strings.HasSuffix(key, digits[:1])
+}
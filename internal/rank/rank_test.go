@@ -0,0 +1,69 @@
+package rank
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBetween(t *testing.T) {
+	testCase := []struct {
+		before   string
+		after    string
+		expected string
+	}{
+		{before: "", after: "", expected: Initial},
+		{before: "i0000001", after: "", expected: "i0000002"},
+		{before: "i000000z", after: "", expected: "i0000011"},
+		{before: "", after: "i0000001", expected: "hzzzzzzz"},
+		{before: "", after: "00000001", expected: "00000000i"},
+		{before: "a", after: "c", expected: "b"},
+		{before: "a", after: "b", expected: "ai"},
+		{before: "a1", after: "a2", expected: "a1i"},
+		{before: "zzzzzzzz", after: "", expected: "zzzzzzzzi"},
+	}
+
+	for _, test := range testCase {
+		key, err := Between(test.before, test.after)
+		require.NoError(t, err)
+		assert.Equal(t, test.expected, key, "between %q and %q", test.before, test.after)
+	}
+}
+
+func TestBetweenInvalidRange(t *testing.T) {
+	for _, keys := range [][2]string{{"b", "a"}, {"a", "a"}, {"a0", ""}, {"", "A"}} {
+		_, err := Between(keys[0], keys[1])
+		assert.ErrorIs(t, err, ErrInvalidRange, "between %q and %q", keys[0], keys[1])
+	}
+}
+
+// random inserts must keep every key unique and in the order they were placed
+func TestBetweenKeepsOrder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	keys := []string{}
+
+	for i := 0; i < 2000; i++ {
+		at := random.Intn(len(keys) + 1)
+
+		before, after := "", ""
+		if at > 0 {
+			before = keys[at-1]
+		}
+		if at < len(keys) {
+			after = keys[at]
+		}
+
+		key, err := Between(before, after)
+		require.NoError(t, err)
+
+		keys = append(keys[:at], append([]string{key}, keys[at:]...)...)
+	}
+
+	assert.True(t, sort.StringsAreSorted(keys))
+	for i := 1; i < len(keys); i++ {
+		require.NotEqual(t, keys[i-1], keys[i])
+	}
+}
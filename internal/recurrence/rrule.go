@@ -0,0 +1,287 @@
+// Package recurrence implements the part of the iCalendar RRULE (RFC 5545)
+// recurring todos need: FREQ, INTERVAL, COUNT, UNTIL, BYDAY and BYMONTHDAY.
+//
+// Occurrences are computed one after the other from the previous one, in
+// the previous occurrence's location, so the wall clock time is kept across
+// daylight saving changes.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxSearch bounds the search for a next occurrence, a rule like
+// FREQ=YEARLY on Feb 29 still finds one well within it.
+const maxSearch = 1000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	Until      time.Time
+	ByDay      []time.Weekday
+	ByMonthDay []int
+}
+
+// Parse reads a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH". The
+// "RRULE:" prefix is optional.
+func Parse(text string) (*Rule, error) {
+	text = strings.TrimPrefix(strings.TrimSpace(text), "RRULE:")
+	rule := &Rule{Interval: 1}
+
+	for _, part := range strings.Split(text, ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("%w: %q is not NAME=VALUE", ErrInvalidRule, part)
+		}
+
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(value))
+			switch rule.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				err = fmt.Errorf("unsupported FREQ %q", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = positive(value)
+		case "COUNT":
+			rule.Count, err = positive(value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(value)
+		default:
+			err = fmt.Errorf("unsupported part %s", name)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return nil, fmt.Errorf("%w: COUNT and UNTIL can't be used together", ErrInvalidRule)
+	case len(rule.ByDay) > 0 && rule.Freq != Daily && rule.Freq != Weekly:
+		return nil, fmt.Errorf("%w: BYDAY is only supported with DAILY and WEEKLY", ErrInvalidRule)
+	case len(rule.ByMonthDay) > 0 && rule.Freq != Monthly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY is only supported with MONTHLY", ErrInvalidRule)
+	}
+
+	return rule, nil
+}
+
+// Next returns the occurrence following previous, which is the occurrence-th
+// one of the series. It reports false when the series has ended.
+func (rule *Rule) Next(previous time.Time, occurrence int) (time.Time, bool) {
+	if rule.Count > 0 && occurrence >= rule.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	var found bool
+	switch rule.Freq {
+	case Daily:
+		next, found = rule.nextDaily(previous)
+	case Weekly:
+		next, found = rule.nextWeekly(previous)
+	case Monthly:
+		next, found = rule.nextMonthly(previous)
+	case Yearly:
+		next, found = rule.nextYearly(previous)
+	}
+
+	if !found || (!rule.Until.IsZero() && next.After(rule.Until)) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+func (rule *Rule) nextDaily(previous time.Time) (time.Time, bool) {
+	for i := 1; i <= maxSearch; i++ {
+		next := addDays(previous, i*rule.Interval)
+		if len(rule.ByDay) == 0 || containsWeekday(rule.ByDay, next.Weekday()) {
+			return next, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// nextWeekly takes the next listed day of the same week, or the first one of
+// the week INTERVAL weeks later. Weeks start on Monday.
+func (rule *Rule) nextWeekly(previous time.Time) (time.Time, bool) {
+	if len(rule.ByDay) == 0 {
+		return addDays(previous, 7*rule.Interval), true
+	}
+
+	today := mondayIndex(previous.Weekday())
+	for day := today + 1; day < 7; day++ {
+		if containsWeekday(rule.ByDay, mondayWeekday(day)) {
+			return addDays(previous, day-today), true
+		}
+	}
+
+	weekStart := addDays(previous, 7*rule.Interval-today)
+	for day := 0; day < 7; day++ {
+		if containsWeekday(rule.ByDay, mondayWeekday(day)) {
+			return addDays(weekStart, day), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// nextMonthly skips months that don't have the day, as RFC 5545 does for
+// the 31st or the 30th of February.
+func (rule *Rule) nextMonthly(previous time.Time) (time.Time, bool) {
+	monthDays := rule.ByMonthDay
+	if len(monthDays) == 0 {
+		monthDays = []int{previous.Day()}
+	}
+
+	for i := 0; i <= maxSearch; i++ {
+		months := i * rule.Interval
+		first := time.Date(previous.Year(), previous.Month()+time.Month(months), 1, 0, 0, 0, 0, previous.Location())
+		last := daysIn(first.Year(), first.Month())
+
+		days := make([]int, 0, len(monthDays))
+		for _, day := range monthDays {
+			if day < 0 {
+				day = last + 1 + day
+			}
+			if day >= 1 && day <= last {
+				days = append(days, day)
+			}
+		}
+		sort.Ints(days)
+
+		for _, day := range days {
+			if i == 0 && day <= previous.Day() {
+				continue
+			}
+
+			return time.Date(first.Year(), first.Month(), day, previous.Hour(), previous.Minute(), previous.Second(), previous.Nanosecond(), previous.Location()), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func (rule *Rule) nextYearly(previous time.Time) (time.Time, bool) {
+	for i := 1; i <= maxSearch; i++ {
+		year := previous.Year() + i*rule.Interval
+		if previous.Day() <= daysIn(year, previous.Month()) {
+			return time.Date(year, previous.Month(), previous.Day(), previous.Hour(), previous.Minute(), previous.Second(), previous.Nanosecond(), previous.Location()), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func addDays(t time.Time, days int) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day()+days, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func mondayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func mondayWeekday(index int) time.Weekday {
+	return time.Weekday((index + 1) % 7)
+}
+
+func containsWeekday(days []time.Weekday, day time.Weekday) bool {
+	for _, candidate := range days {
+		if candidate == day {
+			return true
+		}
+	}
+
+	return false
+}
+
+func positive(value string) (int, error) {
+	number, err := strconv.Atoi(value)
+	if err != nil || number < 1 {
+		return 0, fmt.Errorf("%q is not a positive number", value)
+	}
+
+	return number, nil
+}
+
+// parseUntil accepts a UTC date-time or a date, which includes that whole day.
+func parseUntil(value string) (time.Time, error) {
+	if until, err := time.Parse("20060102T150405Z", value); err == nil {
+		return until, nil
+	}
+
+	until, err := time.Parse("20060102", value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("UNTIL %q is not a date or UTC date-time", value)
+	}
+
+	return until.Add(24*time.Hour - time.Second), nil
+}
+
+func parseByDay(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		day, ok := weekdays[strings.ToUpper(name)]
+		if !ok {
+			return nil, fmt.Errorf("unsupported BYDAY %q", name)
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, text := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(text)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("BYMONTHDAY %q is out of range", text)
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
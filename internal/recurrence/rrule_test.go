@@ -0,0 +1,118 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNext(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	testCase := []struct {
+		name     string
+		rule     string
+		previous time.Time
+		expected time.Time
+	}{
+		{
+			name:     "every other day",
+			rule:     "FREQ=DAILY;INTERVAL=2",
+			previous: time.Date(2026, 10, 30, 7, 0, 0, 0, jakarta),
+			expected: time.Date(2026, 11, 1, 7, 0, 0, 0, jakarta),
+		},
+		{
+			name:     "weekdays skip the weekend",
+			rule:     "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR",
+			previous: time.Date(2026, 10, 23, 7, 0, 0, 0, jakarta), // friday
+			expected: time.Date(2026, 10, 26, 7, 0, 0, 0, jakarta),
+		},
+		{
+			name:     "next listed day in the week",
+			rule:     "FREQ=WEEKLY;BYDAY=MO,TH",
+			previous: time.Date(2026, 10, 19, 9, 0, 0, 0, jakarta), // monday
+			expected: time.Date(2026, 10, 22, 9, 0, 0, 0, jakarta),
+		},
+		{
+			name:     "fortnightly wraps to the first listed day",
+			rule:     "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH",
+			previous: time.Date(2026, 10, 22, 9, 0, 0, 0, jakarta), // thursday
+			expected: time.Date(2026, 11, 2, 9, 0, 0, 0, jakarta),
+		},
+		{
+			name:     "wall clock kept over daylight saving",
+			rule:     "FREQ=WEEKLY",
+			previous: time.Date(2026, 10, 31, 9, 0, 0, 0, newYork),
+			expected: time.Date(2026, 11, 7, 9, 0, 0, 0, newYork),
+		},
+		{
+			name:     "months without the 31st are skipped",
+			rule:     "FREQ=MONTHLY",
+			previous: time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 3, 31, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "last day of the month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			previous: time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC),
+			expected: time.Date(2026, 2, 28, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "leap day",
+			rule:     "FREQ=YEARLY",
+			previous: time.Date(2028, 2, 29, 8, 0, 0, 0, time.UTC),
+			expected: time.Date(2032, 2, 29, 8, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			rule, err := Parse(test.rule)
+			require.NoError(t, err)
+
+			next, ok := rule.Next(test.previous, 1)
+			require.True(t, ok)
+			assert.Equal(t, test.expected, next)
+		})
+	}
+}
+
+func TestNextEndsSeries(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=3")
+	require.NoError(t, err)
+
+	start := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	_, ok := rule.Next(start, 2)
+	assert.True(t, ok)
+	_, ok = rule.Next(start, 3)
+	assert.False(t, ok)
+
+	rule, err = Parse("FREQ=WEEKLY;UNTIL=20261026")
+	require.NoError(t, err)
+
+	_, ok = rule.Next(start, 1)
+	assert.True(t, ok)
+	_, ok = rule.Next(start.AddDate(0, 0, 7), 2)
+	assert.False(t, ok)
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=2;UNTIL=20261231",
+		"FREQ=MONTHLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=DAILY;BYSETPOS=1",
+	} {
+		_, err := Parse(text)
+		assert.ErrorIs(t, err, ErrInvalidRule, text)
+	}
+}
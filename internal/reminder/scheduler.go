@@ -0,0 +1,136 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/notifier"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// batchSize is how many reminders are fired per run.
+	batchSize = 100
+
+	// a failed delivery is retried after retryDelay, doubling with every
+	// attempt up to maxRetryDelay, and given up after maxAttempts
+	retryDelay    = time.Minute
+	maxRetryDelay = time.Hour
+	maxAttempts   = 8
+)
+
+// Scheduler fires todo reminders from inside the server. The sent state is
+// kept in the database, so a restart doesn't send anything again.
+type Scheduler struct {
+	repository repository.Repository
+	notifier   notifier.Notifier
+	interval   time.Duration
+}
+
+func NewScheduler(repository repository.Repository, notifier notifier.Notifier, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		repository: repository,
+		notifier:   notifier,
+		interval:   interval,
+	}
+}
+
+// Run checks for due reminders every interval until ctx is done.
+func (scheduler *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := scheduler.RunOnce(ctx, time.Now()); err != nil {
+			logrus.Errorf("failed to run reminders: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce fires the reminders due at now, at most batchSize of them. Failed
+// ones are due again after a backoff, see release.
+func (scheduler *Scheduler) RunOnce(ctx context.Context, now time.Time) error {
+	todos, err := scheduler.repository.ListDueReminders(now, batchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, todo := range todos {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := scheduler.fire(ctx, notifier.Reminder{Todo: todo}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fire claims the reminder first and only then delivers it. A failed delivery
+// gives the claim back so a later run retries, unless it can never succeed.
+func (scheduler *Scheduler) fire(ctx context.Context, reminder notifier.Reminder) error {
+	todo := reminder.Todo
+	claimed, err := scheduler.repository.ClaimReminder(todo.Id, *todo.RemindAt)
+	if err != nil {
+		return err
+	}
+
+	// another server got it first, or the reminder was changed meanwhile
+	if !claimed {
+		return nil
+	}
+
+	if todo.UserID != 0 {
+		reminder.User, err = scheduler.repository.FindUserByID(todo.UserID)
+		if err != nil {
+			scheduler.release(todo)
+			return err
+		}
+	}
+
+	if err := scheduler.notifier.Notify(ctx, reminder); err != nil {
+		logrus.Errorf("failed to deliver reminder of todo %d: %v", todo.Id, err)
+		if !errors.Is(err, notifier.ErrUndeliverable) {
+			scheduler.release(todo)
+		}
+	}
+
+	return nil
+}
+
+// release gives the claim back for a retry after the backoff of the attempt,
+// the reminder stays claimed once it failed maxAttempts times.
+func (scheduler *Scheduler) release(todo entity.Todos) {
+	attempt := todo.ReminderAttempts + 1
+	if attempt >= maxAttempts {
+		logrus.Errorf("giving up the reminder of todo %d after %d attempts", todo.Id, attempt)
+		return
+	}
+
+	if err := scheduler.repository.ReleaseReminder(todo.Id, time.Now().Add(backoff(attempt))); err != nil {
+		logrus.Errorf("failed to release reminder of todo %d: %v", todo.Id, err)
+	}
+}
+
+func backoff(attempt int) time.Duration {
+	delay := retryDelay
+	for i := 1; i < attempt && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+
+	return delay
+}
@@ -0,0 +1,124 @@
+package reminder
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/notifier"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	reminders []notifier.Reminder
+	err       error
+}
+
+func (recorder *recordingNotifier) Notify(ctx context.Context, reminder notifier.Reminder) error {
+	recorder.reminders = append(recorder.reminders, reminder)
+	return recorder.err
+}
+
+func TestTableDrivenRunOnce(t *testing.T) {
+	now := time.Now()
+	remindAt := now.Add(-time.Minute)
+	owner := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+	todo := entity.Todos{Id: 1, UserID: 7, Title: "sholat", RemindAt: &remindAt}
+	retried := todo
+	retried.ReminderAttempts = 2
+	exhausted := todo
+	exhausted.ReminderAttempts = maxAttempts - 1
+
+	testCase := []struct {
+		name             string
+		todo             entity.Todos
+		claimed          bool
+		notifyErr        error
+		expectedNotified int
+		expectedRetry    time.Duration
+	}{
+		{
+			name:             "claimed reminder is delivered",
+			todo:             todo,
+			claimed:          true,
+			expectedNotified: 1,
+		},
+		{
+			name:             "reminder claimed elsewhere is skipped",
+			todo:             todo,
+			claimed:          false,
+			expectedNotified: 0,
+		},
+		{
+			name:             "failed delivery is released for a retry",
+			todo:             todo,
+			claimed:          true,
+			notifyErr:        errors.New("connection refused"),
+			expectedNotified: 1,
+			expectedRetry:    time.Minute,
+		},
+		{
+			name:             "retried delivery backs off further",
+			todo:             retried,
+			claimed:          true,
+			notifyErr:        errors.New("connection refused"),
+			expectedNotified: 1,
+			expectedRetry:    4 * time.Minute,
+		},
+		{
+			name:             "delivery is given up after the last attempt",
+			todo:             exhausted,
+			claimed:          true,
+			notifyErr:        errors.New("connection refused"),
+			expectedNotified: 1,
+		},
+		{
+			name:             "undeliverable reminder is not retried",
+			todo:             todo,
+			claimed:          true,
+			notifyErr:        notifier.ErrUndeliverable,
+			expectedNotified: 1,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			repo := mocks.NewRepository(t)
+			repo.On("ListDueReminders", now, batchSize).Return([]entity.Todos{test.todo}, nil)
+			repo.On("ClaimReminder", int64(1), remindAt).Return(test.claimed, nil)
+			if test.claimed {
+				repo.On("FindUserByID", int64(7)).Return(owner, nil)
+			}
+			if test.expectedRetry > 0 {
+				repo.On("ReleaseReminder", int64(1), mock.MatchedBy(func(retryAt time.Time) bool {
+					delay := time.Until(retryAt)
+					return delay > test.expectedRetry-time.Second && delay <= test.expectedRetry
+				})).Return(nil)
+			}
+
+			recorder := &recordingNotifier{err: test.notifyErr}
+			scheduler := NewScheduler(repo, recorder, time.Minute)
+
+			require.NoError(t, scheduler.RunOnce(context.Background(), now))
+
+			require.Len(t, recorder.reminders, test.expectedNotified)
+			if test.expectedNotified > 0 {
+				assert.Equal(t, owner, recorder.reminders[0].User)
+				assert.Equal(t, "sholat", recorder.reminders[0].Todo.Title)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, backoff(1))
+	assert.Equal(t, 2*time.Minute, backoff(2))
+	assert.Equal(t, 32*time.Minute, backoff(6))
+	assert.Equal(t, maxRetryDelay, backoff(7))
+	assert.Equal(t, maxRetryDelay, backoff(30))
+}
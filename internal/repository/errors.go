@@ -0,0 +1,13 @@
+package repository
+
+import "errors"
+
+// ErrTokenAlreadyUsed is returned when a single-use token is redeemed twice.
+var ErrTokenAlreadyUsed = errors.New("token already used")
+
+// ErrDuplicateTag is returned when a user already has a tag with the name.
+var ErrDuplicateTag = errors.New("tag already exists")
+
+// ErrDuplicateTodo is returned when a user already has a todo with the
+// external id.
+var ErrDuplicateTodo = errors.New("todo already imported")
@@ -1,13 +1,108 @@
 package repository
 
-import "todolist_gin_gorm/internal/model/entity"
+import (
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+)
 
 type Repository interface {
-	GetAll() ([]entity.Todos, error)
+	// Transaction runs fn with a repository bound to one transaction, which
+	// is committed when fn returns nil. Nested calls use savepoints.
+	Transaction(fn func(repo Repository) error) error
+	GetAll(query *dto.TodoQuery) ([]entity.Todos, error)
+	SearchTodos(query *dto.SearchQuery) ([]entity.TodoMatch, error)
+	ExportTodos(query *dto.TodoQuery, fn func(todos []entity.Todos) error) error
+	ImportedExternalIDs(userID int64, externalIDs []string) ([]string, error)
 	GetID(todoID int64) (*entity.Todos, error)
-	Create(title string, description string) (*entity.Todos, error)
+	Create(todo *entity.Todos) error
 	Update(todoID int64, updates map[string]interface{}) (*entity.Todos, error)
 	Delete(todoID int64) (int64, error)
+	CreateNextOccurrence(next *entity.Todos, previousID int64) error
+	LastPosition(listID *int64) (string, error)
+	AdjacentPosition(todo *entity.Todos, after bool) (string, error)
+	ListDueReminders(now time.Time, limit int) ([]entity.Todos, error)
+	ClaimReminder(todoID int64, remindAt time.Time) (bool, error)
+	ReleaseReminder(todoID int64, retryAt time.Time) error
+	GetSubtasks(parentID int64) ([]entity.Todos, error)
+	SubtaskProgress(parentID int64) (entity.SubtaskProgress, error)
+	NextSubtaskOrder(parentID int64) (int, error)
+	ReorderSubtasks(parentID int64, order []int64) error
+	CloseSubtasks(parentID int64, status string, now time.Time) error
+	MoveSubtasks(parentID int64, listID *int64) error
+	CreateList(list *entity.Lists) error
+	GetLists(userID int64, includeArchived bool) ([]entity.Lists, error)
+	FindList(userID int64, listID int64) (*entity.Lists, error)
+	UpdateList(listID int64, updates map[string]interface{}) (*entity.Lists, error)
+	DeleteList(list *entity.Lists) error
+	CreateTag(tag *entity.Tags) error
+	GetTags(userID int64) ([]entity.Tags, error)
+	FindTag(userID int64, tagID int64) (*entity.Tags, error)
+	RenameTag(tag *entity.Tags, name string) error
+	DeleteTag(tagID int64) error
+	AttachTag(todoID int64, tagID int64) error
+	DetachTag(todoID int64, tagID int64) (int64, error)
+	SaveShare(share *entity.Shares) error
+	FindShare(shareID int64) (*entity.Shares, error)
+	ListShares(todoID *int64, listID *int64) ([]entity.Shares, error)
+	ListSharedWithUser(userID int64) ([]entity.Shares, error)
+	DeleteShare(shareID int64) (int64, error)
+	FindTodoForUser(userID int64, todoID int64) (*entity.Todos, string, error)
+	FindTodoShareRole(userID int64, todo *entity.Todos) (string, error)
+	FindSharedList(userID int64, listID int64) (*entity.Lists, string, error)
+	CreateComment(comment *entity.Comments) error
+	GetComments(todoID int64) ([]entity.Comments, error)
+	FindComment(commentID int64) (*entity.Comments, error)
+	UpdateComment(commentID int64, body string, editedAt time.Time) error
+	DeleteComment(commentID int64) (int64, error)
+	CreateAttachment(attachment *entity.Attachments) error
+	GetAttachments(todoID int64) ([]entity.Attachments, error)
+	FindAttachment(attachmentID int64) (*entity.Attachments, error)
+	DeleteAttachment(attachmentID int64) (int64, error)
+	AttachmentKeys(todoID int64) ([]string, error)
+	UserAttachmentKeys(userID int64) ([]string, error)
 	CreateUser(user *entity.Users) error
 	FindUserByEmail(username string) (*entity.Users, error)
+	FindUserByID(userID int64) (*entity.Users, error)
+	UpdateUser(userID int64, updates map[string]interface{}) (*entity.Users, error)
+	DeleteUser(userID int64) error
+	ListUsers(search string, limit int, offset int) ([]entity.Users, int64, error)
+	SetUserDisabled(userID int64, disabled bool) error
+	SetUserRole(userID int64, role string) error
+	GetAllByUser(userID int64) ([]entity.Todos, error)
+	CreateAuditEvent(event *entity.AuditEvents) error
+	ListAuditEvents(query *dto.AuditQuery) ([]entity.AuditEvents, error)
+	CreateUndoOperation(operation *entity.UndoOperations) error
+	FindUndoOperation(userID int64, operationID int64) (*entity.UndoOperations, error)
+	ClaimUndoOperation(operationID int64, now time.Time) (bool, error)
+	TodoSnapshot(todoID int64) (*entity.UndoSnapshot, error)
+	RestoreTodos(snapshot *entity.UndoSnapshot) error
+	CreateAPIKey(key *entity.APIKeys) error
+	ListAPIKeys(userID int64) ([]entity.APIKeys, error)
+	FindAPIKeyByHash(keyHash string) (*entity.APIKeys, error)
+	RevokeAPIKey(userID int64, keyID int64) (int64, error)
+	TouchAPIKey(keyID int64) error
+	FindUserIdentity(provider string, subject string) (*entity.UserIdentities, error)
+	CreateUserIdentity(identity *entity.UserIdentities) error
+	CreateUserWithIdentity(user *entity.Users, identity *entity.UserIdentities) error
+	SetTOTPSecret(userID int64, secret string) error
+	EnableTwoFactor(userID int64, recoveryCodeHashes []string) error
+	DisableTwoFactor(userID int64) error
+	AdvanceTOTPStep(userID int64, step int64) (bool, error)
+	UseRecoveryCode(userID int64, codeHash string) (bool, error)
+	CreateTwoFactorChallenge(challenge *entity.TwoFactorChallenges) error
+	ClaimTwoFactorAttempt(jti string, maxAttempts int) (bool, error)
+	ConsumeTwoFactorChallenge(jti string) (bool, error)
+	CreateSession(session *entity.Sessions) error
+	FindSessionByJTI(jti string) (*entity.Sessions, error)
+	ListSessions(userID int64) ([]entity.Sessions, error)
+	RevokeSession(userID int64, sessionID int64) (int64, error)
+	TouchSession(sessionID int64) error
+	CreateEmailVerificationToken(token *entity.EmailVerificationTokens) error
+	FindEmailVerificationToken(tokenHash string) (*entity.EmailVerificationTokens, error)
+	VerifyEmail(token *entity.EmailVerificationTokens) error
+	CreatePasswordResetToken(token *entity.PasswordResetTokens) error
+	FindPasswordResetToken(tokenHash string) (*entity.PasswordResetTokens, error)
+	ResetPassword(token *entity.PasswordResetTokens, passwordHash string) error
+	UpdatePassword(userID int64, passwordHash string) (*entity.Users, error)
 }
@@ -0,0 +1,156 @@
+// Package search turns a user's search text into terms and highlights those
+// terms in the todos that matched.
+package search
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// MaxTerms is how many words of a search are used, the rest is ignored.
+const MaxTerms = 10
+
+// Terms splits the search text into lower case words. Characters with a
+// meaning in MySQL boolean full-text queries are treated as separators.
+func Terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(`+-<>()~*"@`, r)
+	})
+
+	var terms []string
+	seen := make(map[string]bool)
+	for _, word := range words {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		terms = append(terms, word)
+		if len(terms) == MaxTerms {
+			break
+		}
+	}
+
+	return terms
+}
+
+// BooleanQuery builds a MySQL boolean mode query requiring every term, as a
+// word or a word prefix.
+func BooleanQuery(terms []string) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = "+" + term + "*"
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// Highlight escapes text for HTML and wraps every occurrence of the terms in
+// <mark>.
+func Highlight(text string, terms []string) string {
+	runes := []rune(text)
+	return highlight(runes, lowerRunes(runes), terms)
+}
+
+// Snippet returns about width characters of text around the first match,
+// highlighted like Highlight. Cut off ends are marked with an ellipsis.
+func Snippet(text string, terms []string, width int) string {
+	runes := []rune(text)
+	if len(runes) <= width {
+		return Highlight(text, terms)
+	}
+
+	lower := lowerRunes(runes)
+	first := len(runes)
+	for _, term := range terms {
+		if at := index(lower, []rune(term), 0); at >= 0 && at < first {
+			first = at
+		}
+	}
+	if first == len(runes) {
+		first = 0
+	}
+
+	// show some context in front of the match
+	start := first - width/4
+	if start < 0 {
+		start = 0
+	}
+	end := start + width
+	if end > len(runes) {
+		end = len(runes)
+		start = end - width
+	}
+
+	snippet := highlight(runes[start:end], lower[start:end], terms)
+	if start > 0 {
+		snippet = "…" + snippet
+	}
+	if end < len(runes) {
+		snippet += "…"
+	}
+
+	return snippet
+}
+
+func highlight(runes []rune, lower []rune, terms []string) string {
+	var out strings.Builder
+	plain := 0
+
+	for i := 0; i < len(runes); {
+		length := 0
+		for _, term := range terms {
+			termRunes := []rune(term)
+			if len(termRunes) > length && hasPrefix(lower[i:], termRunes) {
+				length = len(termRunes)
+			}
+		}
+
+		if length == 0 {
+			i++
+			continue
+		}
+
+		out.WriteString(html.EscapeString(string(runes[plain:i])))
+		out.WriteString("<mark>" + html.EscapeString(string(runes[i:i+length])) + "</mark>")
+		i += length
+		plain = i
+	}
+	out.WriteString(html.EscapeString(string(runes[plain:])))
+
+	return out.String()
+}
+
+// lowerRunes lower cases rune by rune, so indexes stay the same as in the
+// original text.
+func lowerRunes(runes []rune) []rune {
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	return lower
+}
+
+func index(runes []rune, term []rune, from int) int {
+	for i := from; i+len(term) <= len(runes); i++ {
+		if hasPrefix(runes[i:], term) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func hasPrefix(runes []rune, prefix []rune) bool {
+	if len(prefix) == 0 || len(prefix) > len(runes) {
+		return false
+	}
+	for i, r := range prefix {
+		if runes[i] != r {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,37 @@
+package search
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"sholat", "tahajud"}, Terms("  Sholat +tahajud sholat "))
+	assert.Equal(t, []string{"a", "b"}, Terms(`"a" -b* (a)`))
+	assert.Empty(t, Terms(" +-* "))
+	assert.Len(t, Terms(strings.Repeat("x y z a b c d e f g h i ", 2)), MaxTerms)
+}
+
+func TestBooleanQuery(t *testing.T) {
+	assert.Equal(t, "+sholat* +malam*", BooleanQuery([]string{"sholat", "malam"}))
+}
+
+func TestHighlight(t *testing.T) {
+	assert.Equal(t, "<mark>Sholat</mark> tahajud <mark>sholat</mark>", Highlight("Sholat tahajud sholat", []string{"sholat"}))
+	assert.Equal(t, "&lt;b&gt; <mark>x</mark>", Highlight("<b> x", []string{"x"}))
+	assert.Equal(t, "<mark>piket</mark>ing", Highlight("piketing", []string{"pik", "piket"}))
+}
+
+func TestSnippet(t *testing.T) {
+	text := strings.Repeat("a ", 50) + "sholat tahajud" + strings.Repeat(" b", 50)
+
+	snippet := Snippet(text, []string{"tahajud"}, 40)
+	assert.True(t, strings.HasPrefix(snippet, "…"))
+	assert.True(t, strings.HasSuffix(snippet, "…"))
+	assert.Contains(t, snippet, "<mark>tahajud</mark>")
+
+	assert.Equal(t, "short <mark>text</mark>", Snippet("short text", []string{"text"}, 40))
+	assert.Equal(t, strings.Repeat("a ", 20)+"…", Snippet(text, []string{"missing"}, 40))
+}
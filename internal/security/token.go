@@ -0,0 +1,42 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// GenerateToken returns a random url-safe token together with the hash that
+// should be persisted in its place.
+func GenerateToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	token := hex.EncodeToString(buf)
+	return token, HashToken(token), nil
+}
+
+// HashToken hashes a token the same way GenerateToken does, so a token
+// received from a client can be looked up by its hash.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyPrefixLength is how many leading characters of an API key are stored
+// in clear text.
+const APIKeyPrefixLength = 12
+
+// GenerateAPIKey returns a new personal API key, its visible prefix and the
+// hash to persist.
+func GenerateAPIKey() (string, string, string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", "", err
+	}
+
+	key := "tdl_" + hex.EncodeToString(buf)
+	return key, key[:APIKeyPrefixLength], HashToken(key), nil
+}
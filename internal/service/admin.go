@@ -0,0 +1,215 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminListUsersHandler lists users, optionally filtered by ?q= on username or email
+func (handler *HandlerImpl) AdminListUsersHandler(ctx *gin.Context) {
+	query := new(dto.AdminListUsersQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	limit, offset := query.LimitOffset()
+	users, total, err := handler.todolistRepository.ListUsers(query.Query, limit, offset)
+	if err != nil {
+		logrus.Errorf("failed when list users: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditListUsers, entity.AuditUser, 0, nil, gin.H{"q": query.Query, "limit": limit, "offset": offset})
+
+	data := make([]dto.UserResponse, 0, len(users))
+	for i := range users {
+		data = append(data, dto.NewUserResponse(&users[i]))
+	}
+
+	ctx.JSON(http.StatusOK, dto.AdminListUsersResponse{
+		Message: "list users successfully",
+		Status:  http.StatusOK,
+		More:    len(data),
+		Total:   total,
+		Data:    data,
+	})
+}
+
+// AdminDisableUserHandler disables an account and revokes its tokens
+func (handler *HandlerImpl) AdminDisableUserHandler(ctx *gin.Context) {
+	handler.setUserDisabled(ctx, true)
+}
+
+// AdminEnableUserHandler re-enables a disabled account
+func (handler *HandlerImpl) AdminEnableUserHandler(ctx *gin.Context) {
+	handler.setUserDisabled(ctx, false)
+}
+
+func (handler *HandlerImpl) setUserDisabled(ctx *gin.Context, disabled bool) {
+	target, ok := handler.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	if target.UserID == ctx.GetInt64("user_id") {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "admins cannot disable their own account",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.SetUserDisabled(target.UserID, disabled); err != nil {
+		logrus.Errorf("failed when disable user: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	action, message := entity.AuditEnable, "enable user successfully"
+	if disabled {
+		action, message = entity.AuditDisable, "disable user successfully"
+	}
+	handler.audit(ctx, action, entity.AuditUser, target.UserID, nil, nil)
+
+	logrus.Info(http.StatusOK, message)
+	ctx.JSON(http.StatusOK, dto.AdminActionResponse{
+		Message: message,
+		Status:  http.StatusOK,
+	})
+}
+
+// AdminResetPasswordHandler mails the user a password reset link
+func (handler *HandlerImpl) AdminResetPasswordHandler(ctx *gin.Context) {
+	target, ok := handler.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := handler.sendPasswordResetEmail(ctx, target); err != nil {
+		logrus.Errorf("failed to send password reset email: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditRequestReset, entity.AuditUser, target.UserID, nil, nil)
+
+	ctx.JSON(http.StatusOK, dto.AdminActionResponse{
+		Message: "password reset email sent",
+		Status:  http.StatusOK,
+	})
+}
+
+// AdminUpdateRoleHandler changes the role of a user, tokens issued with the
+// old role are revoked
+func (handler *HandlerImpl) AdminUpdateRoleHandler(ctx *gin.Context) {
+	target, ok := handler.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	request := new(dto.UpdateRoleRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.SetUserRole(target.UserID, request.Role); err != nil {
+		logrus.Errorf("failed when update user role: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditUser, target.UserID, gin.H{"role": target.Role}, gin.H{"role": request.Role})
+
+	target.Role = request.Role
+	ctx.JSON(http.StatusOK, dto.AdminUserResponse{
+		Message: "update role successfully",
+		Status:  http.StatusOK,
+		Data:    dto.NewUserResponse(target),
+	})
+}
+
+// AdminGetUserTodosHandler returns every todo owned by a user
+func (handler *HandlerImpl) AdminGetUserTodosHandler(ctx *gin.Context) {
+	target, ok := handler.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	todos, err := handler.todolistRepository.GetAllByUser(target.UserID)
+	if err != nil {
+		logrus.Errorf("failed when get todolist by user: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditViewUserTodos, entity.AuditUser, target.UserID, nil, nil)
+
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetAll{
+		Message: "get all todolist successfully",
+		Status:  http.StatusOK,
+		More:    len(todos),
+		Data:    todos,
+	})
+}
+
+// adminTargetUser loads the user from the :userId parameter and writes the
+// error response itself when it cannot.
+func (handler *HandlerImpl) adminTargetUser(ctx *gin.Context) (*entity.Users, bool) {
+	userID, err := strconv.ParseInt(ctx.Param("userId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	user, err := handler.todolistRepository.FindUserByID(userID)
+	if err != nil {
+		logrus.Errorf("failed when get user by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+
+	if user == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "user not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+
+	return user, true
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var adminUser = &entity.Users{UserID: 1, Email: "admin@mail.com", Role: entity.RoleAdmin}
+
+func TestAdminListUsers(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("ListUsers", "alwi", 10, 10).Return([]entity.Users{
+		{UserID: 7, Username: "alwi", Email: "alwi@mail.com", Password: "hash"},
+	}, int64(11), nil)
+	mockRepo.On("CreateAuditEvent", mock.MatchedBy(func(event *entity.AuditEvents) bool {
+		return event.ActorID == 1 && event.Action == entity.AuditListUsers && event.EntityType == entity.AuditUser
+	})).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.GET("/admin/users", withUser(adminUser), handler.AdminListUsersHandler)
+
+	req, err := http.NewRequest(http.MethodGet, "/admin/users?q=alwi&page=2&limit=10", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var result dto.AdminListUsersResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, int64(11), result.Total)
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "alwi@mail.com", result.Data[0].Email)
+	assert.NotContains(t, recorder.Body.String(), "hash")
+}
+
+func TestTableDrivenAdminDisableUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		userID         string
+		mock           func(mock *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:   "success",
+			userID: "7",
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByID", int64(7)).Return(&entity.Users{UserID: 7}, nil)
+				m.On("SetUserDisabled", int64(7), true).Return(nil)
+				m.On("CreateAuditEvent", mock.MatchedBy(func(event *entity.AuditEvents) bool {
+					return event.Action == entity.AuditDisable && event.EntityType == entity.AuditUser && event.EntityID == 7
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:   "own account",
+			userID: "1",
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByID", int64(1)).Return(adminUser, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "not found",
+			userID: "99",
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByID", int64(99)).Return(nil, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/admin/users/:userId/disable", withUser(adminUser), handler.AdminDisableUserHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/admin/users/"+test.userID+"/disable", nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestAdminUpdateRole(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserByID", int64(7)).Return(&entity.Users{UserID: 7, Role: entity.RoleUser}, nil)
+	mockRepo.On("SetUserRole", int64(7), entity.RoleAdmin).Return(nil)
+	mockRepo.On("CreateAuditEvent", mock.MatchedBy(func(event *entity.AuditEvents) bool {
+		return event.Action == entity.AuditUpdate && event.EntityType == entity.AuditUser && event.EntityID == 7 &&
+			string(event.Changes) == `{"role":{"before":"user","after":"admin"}}`
+	})).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.PUT("/admin/users/:userId/role", withUser(adminUser), handler.AdminUpdateRoleHandler)
+
+	req, err := http.NewRequest(http.MethodPut, "/admin/users/7/role", strings.NewReader(`{"role": "admin"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var result dto.AdminUserResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, entity.RoleAdmin, result.Data.Role)
+}
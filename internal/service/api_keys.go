@@ -0,0 +1,147 @@
+package service
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// CreateAPIKeyHandler issues a personal API key. The key itself is only
+// returned in this response.
+func (handler *HandlerImpl) CreateAPIKeyHandler(ctx *gin.Context) {
+	request := new(dto.CreateAPIKeyRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "expires_at must be in the future",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	plainKey, prefix, keyHash, err := security.GenerateAPIKey()
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	key := &entity.APIKeys{
+		UserID:    ctx.GetInt64("user_id"),
+		Name:      request.Name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    normalizeScopes(request.Scopes),
+		ExpiresAt: request.ExpiresAt,
+	}
+	if err := handler.todolistRepository.CreateAPIKey(key); err != nil {
+		logrus.Errorf("failed when create api key: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditCreate, entity.AuditAPIKey, key.KeyID, nil, key)
+
+	logrus.Info(http.StatusCreated, "create api key successfully")
+	ctx.JSON(http.StatusCreated, dto.APIKeyResponseCreate{
+		Message: "create api key successfully, store the key now, it won't be shown again",
+		Status:  http.StatusCreated,
+		Key:     plainKey,
+		Data:    *key,
+	})
+}
+
+// ListAPIKeysHandler lists the keys of the logged in user, including revoked ones
+func (handler *HandlerImpl) ListAPIKeysHandler(ctx *gin.Context) {
+	keys, err := handler.todolistRepository.ListAPIKeys(ctx.GetInt64("user_id"))
+	if err != nil {
+		logrus.Errorf("failed when list api keys: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.APIKeyResponseGetAll{
+		Message: "get all api keys successfully",
+		Status:  http.StatusOK,
+		More:    len(keys),
+		Data:    keys,
+	})
+}
+
+// RevokeAPIKeyHandler revokes one of the logged in user's keys
+func (handler *HandlerImpl) RevokeAPIKeyHandler(ctx *gin.Context) {
+	keyID, err := strconv.ParseInt(ctx.Param("keyId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	revoked, err := handler.todolistRepository.RevokeAPIKey(ctx.GetInt64("user_id"), keyID)
+	if err != nil {
+		logrus.Errorf("failed when revoke api key: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if revoked == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "api key not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditRevoke, entity.AuditAPIKey, keyID, nil, nil)
+
+	logrus.Info(http.StatusOK, "revoke api key successfully")
+	ctx.JSON(http.StatusOK, dto.APIKeyResponseDelete{
+		Message: "revoke api key successfully",
+		Status:  http.StatusOK,
+	})
+}
+
+// normalizeScopes defaults to read-only and always grants read alongside write.
+func normalizeScopes(scopes []string) string {
+	set := map[string]bool{entity.ScopeRead: true}
+	for _, scope := range scopes {
+		set[scope] = true
+	}
+
+	normalized := make([]string, 0, len(set))
+	for scope := range set {
+		normalized = append(normalized, scope)
+	}
+	sort.Strings(normalized)
+
+	return strings.Join(normalized, ",")
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAPIKeyReturnsKeyOnce(t *testing.T) {
+	var stored *entity.APIKeys
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("CreateAPIKey", mock.AnythingOfType("*entity.APIKeys")).Run(func(args mock.Arguments) {
+		stored = args.Get(0).(*entity.APIKeys)
+	}).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/me/api_keys", withUser(&entity.Users{UserID: 7}), handler.CreateAPIKeyHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/me/api_keys", strings.NewReader(`{"name": "ci", "scopes": ["write"]}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var result dto.APIKeyResponseCreate
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+	require.NotNil(t, stored)
+	assert.Equal(t, int64(7), stored.UserID)
+	assert.Equal(t, "read,write", stored.Scopes)
+	assert.Equal(t, security.HashToken(result.Key), stored.KeyHash)
+	assert.True(t, strings.HasPrefix(result.Key, result.Data.Prefix))
+	assert.NotContains(t, recorder.Body.String(), stored.KeyHash)
+}
+
+func TestCreateAPIKeyInvalidScope(t *testing.T) {
+	handler := NewHandlerImpl(mocks.NewRepository(t))
+
+	router := gin.New()
+	router.POST("/me/api_keys", withUser(&entity.Users{UserID: 7}), handler.CreateAPIKeyHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/me/api_keys", strings.NewReader(`{"name": "ci", "scopes": ["admin"]}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestRevokeAPIKeyNotFound(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("RevokeAPIKey", int64(7), int64(3)).Return(int64(0), nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.DELETE("/me/api_keys/:keyId", withUser(&entity.Users{UserID: 7}), handler.RevokeAPIKeyHandler)
+
+	req, err := http.NewRequest(http.MethodDelete, "/me/api_keys/3", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+}
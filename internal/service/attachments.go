@@ -0,0 +1,297 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+	"todolist_gin_gorm/internal/storage"
+	"unicode/utf8"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// multipartOverhead is allowed on top of ATTACHMENT_MAX_SIZE for the
+// multipart framing around the file
+const multipartOverhead = 1 << 20
+
+// UploadAttachmentHandler stores the "file" part of a multipart form as an
+// attachment of the todo. The type is sniffed from the content, the one the
+// client claims is ignored.
+func (handler *HandlerImpl) UploadAttachmentHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareEditor)
+	if !ok {
+		return
+	}
+
+	maxSize := handler.cfg.AttachmentMaxSize
+	if maxSize > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+multipartOverhead)
+	}
+
+	header, err := ctx.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || (err == nil && maxSize > 0 && header.Size > maxSize) {
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+			Message: fmt.Sprintf("file is larger than %d bytes", maxSize),
+			Status:  http.StatusRequestEntityTooLarge,
+		})
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "a file is required",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		logrus.Errorf("failed when open upload: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	defer file.Close()
+
+	fileType, err := mimetype.DetectReader(file)
+	if err == nil {
+		_, err = file.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		logrus.Errorf("failed when detect file type: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if !handler.allowedAttachmentType(fileType) {
+		ctx.AbortWithStatusJSON(http.StatusUnsupportedMediaType, dto.ErrorResponse{
+			Message: fmt.Sprintf("files of type %s are not allowed", fileType.String()),
+			Status:  http.StatusUnsupportedMediaType,
+		})
+		return
+	}
+
+	token, _, err := security.GenerateToken()
+	if err != nil {
+		logrus.Errorf("failed when create storage key: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	attachment := &entity.Attachments{
+		TodoID:      todo.Id,
+		UserID:      ctx.GetInt64("user_id"),
+		FileName:    attachmentName(header.Filename),
+		ContentType: fileType.String(),
+		Size:        header.Size,
+		StorageKey:  fmt.Sprintf("todos/%d/%s", todo.Id, token),
+	}
+
+	if err := handler.storage.Put(ctx, attachment.StorageKey, file, attachment.Size, attachment.ContentType); err != nil {
+		logrus.Errorf("failed when store attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.CreateAttachment(attachment); err != nil {
+		logrus.Errorf("failed when create attachment: %v", err)
+		handler.removeStoredFiles(ctx, []string{attachment.StorageKey})
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditCreate, entity.AuditAttachment, attachment.AttachmentID, nil, attachment)
+
+	logrus.Info(http.StatusCreated, "upload attachment successfully")
+	ctx.JSON(http.StatusCreated, dto.AttachmentResponse{
+		Message: "upload attachment successfully",
+		Status:  http.StatusCreated,
+		Data:    *attachment,
+	})
+}
+
+func (handler *HandlerImpl) GetAttachmentsHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	attachments, err := handler.todolistRepository.GetAttachments(todo.Id)
+	if err != nil {
+		logrus.Errorf("failed when get attachments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.AttachmentResponseGetAll{
+		Message: "get all attachments successfully",
+		Status:  http.StatusOK,
+		More:    len(attachments),
+		Data:    attachments,
+	})
+}
+
+// DownloadAttachmentHandler streams the file, Range requests are answered
+// with the requested part only
+func (handler *HandlerImpl) DownloadAttachmentHandler(ctx *gin.Context) {
+	attachment, ok := handler.todoAttachment(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	object, err := handler.storage.Open(ctx, attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "attachment not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when open attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	defer object.Close()
+
+	ctx.Header("Content-Type", attachment.ContentType)
+	ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	http.ServeContent(ctx.Writer, ctx.Request, attachment.FileName, attachment.CreatedAt, object)
+}
+
+func (handler *HandlerImpl) DeleteAttachmentHandler(ctx *gin.Context) {
+	attachment, ok := handler.todoAttachment(ctx, entity.ShareEditor)
+	if !ok {
+		return
+	}
+
+	if _, err := handler.todolistRepository.DeleteAttachment(attachment.AttachmentID); err != nil {
+		logrus.Errorf("failed when delete attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.removeStoredFiles(ctx, []string{attachment.StorageKey})
+	handler.audit(ctx, entity.AuditDelete, entity.AuditAttachment, attachment.AttachmentID, attachment, nil)
+
+	logrus.Info(http.StatusOK, "delete attachment successfully")
+	ctx.JSON(http.StatusOK, dto.AttachmentResponseDelete{
+		Message: "delete attachment successfully",
+		Status:  http.StatusOK,
+	})
+}
+
+// todoAttachment loads the attachment named by :attachmentId of the todo
+// named by :todolistId, the user needs the required role on the todo.
+func (handler *HandlerImpl) todoAttachment(ctx *gin.Context, required string) (*entity.Attachments, bool) {
+	todo, ok := handler.todoFor(ctx, required)
+	if !ok {
+		return nil, false
+	}
+
+	attachmentID, err := strconv.ParseInt(ctx.Param("attachmentId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	attachment, err := handler.todolistRepository.FindAttachment(attachmentID)
+	if err != nil {
+		logrus.Errorf("failed when get attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+
+	if attachment == nil || attachment.TodoID != todo.Id {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "attachment not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+
+	return attachment, true
+}
+
+// removeStoredFiles deletes files whose rows are gone. Failures are only
+// logged, the todo or attachment is already deleted.
+func (handler *HandlerImpl) removeStoredFiles(ctx *gin.Context, keys []string) {
+	for _, key := range keys {
+		if err := handler.storage.Delete(ctx, key); err != nil {
+			logrus.Errorf("failed when delete stored file %s: %v", key, err)
+		}
+	}
+}
+
+// allowedAttachmentType checks the sniffed type against ATTACHMENT_TYPES,
+// an empty list allows every type
+func (handler *HandlerImpl) allowedAttachmentType(fileType *mimetype.MIME) bool {
+	if len(handler.cfg.AttachmentTypes) == 0 {
+		return true
+	}
+
+	for _, allowed := range handler.cfg.AttachmentTypes {
+		if fileType.Is(strings.TrimSpace(allowed)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func attachmentName(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "attachment"
+	}
+	if len(name) > 255 {
+		// cut before the rune that crosses the limit, not through it
+		cut := 255
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		return name[:cut]
+	}
+
+	return name
+}
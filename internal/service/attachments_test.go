@@ -0,0 +1,222 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/storage"
+	"todolist_gin_gorm/mocks"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00")
+
+func multipartFile(t *testing.T, name string, content []byte) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", name)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	return body, writer.FormDataContentType()
+}
+
+func TestTableDrivenUploadAttachment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		fileName       string
+		content        []byte
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+		expectedType   string
+	}{
+		{
+			name:     "png",
+			fileName: "photo.txt",
+			content:  pngHeader,
+			mock: func(m *mocks.Repository) {
+				m.On("CreateAttachment", mock.MatchedBy(func(attachment *entity.Attachments) bool {
+					return attachment.TodoID == 1 && attachment.UserID == 7 && attachment.FileName == "photo.txt" &&
+						attachment.Size == int64(len(pngHeader)) && attachment.StorageKey != ""
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedType:   "image/png",
+		},
+		{
+			name:           "type not allowed",
+			fileName:       "archive.png",
+			content:        []byte("PK\x03\x04\x14\x00\x00\x00\x08\x00"),
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			name:           "too large",
+			fileName:       "big.txt",
+			content:        bytes.Repeat([]byte("a"), 65),
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+			test.mock(mockRepo)
+
+			cfg := &config.Config{AttachmentMaxSize: 64, AttachmentTypes: []string{"image/png", "text/plain"}}
+			handler := NewHandlerImpl(mockRepo, WithConfig(cfg), WithStorage(storage.NewLocal(t.TempDir())))
+
+			router := gin.New()
+			router.POST("/todos/:todolistId/attachments", withUser(&entity.Users{UserID: 7}), handler.UploadAttachmentHandler)
+
+			body, contentType := multipartFile(t, test.fileName, test.content)
+			req, err := http.NewRequest(http.MethodPost, "/todos/1/attachments", body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", contentType)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedType != "" {
+				var result dto.AttachmentResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+				assert.Equal(t, test.expectedType, result.Data.ContentType)
+			}
+		})
+	}
+}
+
+func TestDownloadAttachmentRange(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := storage.NewLocal(t.TempDir())
+	content := "0123456789"
+	require.NoError(t, store.Put(context.Background(), "todos/1/abc", bytes.NewReader([]byte(content)), int64(len(content)), "text/plain"))
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 8}, entity.ShareViewer, nil)
+	mockRepo.On("FindAttachment", int64(3)).Return(&entity.Attachments{
+		AttachmentID: 3, TodoID: 1, FileName: "notes.txt", ContentType: "text/plain; charset=utf-8", Size: 10, StorageKey: "todos/1/abc",
+	}, nil)
+	handler := NewHandlerImpl(mockRepo, WithStorage(store))
+
+	router := gin.New()
+	router.GET("/todos/:todolistId/attachments/:attachmentId", withUser(&entity.Users{UserID: 7}), handler.DownloadAttachmentHandler)
+
+	req := httptest.NewRequest(http.MethodGet, "/todos/1/attachments/3", nil)
+	req.Header.Set("Range", "bytes=2-5")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusPartialContent, recorder.Code)
+	assert.Equal(t, "2345", recorder.Body.String())
+	assert.Equal(t, "bytes 2-5/10", recorder.Header().Get("Content-Range"))
+	assert.Equal(t, `attachment; filename=notes.txt`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, "text/plain; charset=utf-8", recorder.Header().Get("Content-Type"))
+}
+
+func TestDeleteTodoRemovesAttachments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := storage.NewLocal(t.TempDir())
+	for _, key := range []string{"todos/1/a", "todos/2/b"} {
+		require.NoError(t, store.Put(context.Background(), key, bytes.NewReader([]byte("x")), 1, ""))
+	}
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+	mockRepo.On("AttachmentKeys", int64(1)).Return([]string{"todos/1/a", "todos/2/b"}, nil)
+	mockRepo.On("Delete", int64(1)).Return(int64(1), nil)
+	handler := NewHandlerImpl(mockRepo, WithStorage(store))
+
+	router := gin.New()
+	router.DELETE("/delete_todolist/:todolistId", withUser(&entity.Users{UserID: 7}), handler.DeleteHandlerTodolist)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/delete_todolist/1", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	for _, key := range []string{"todos/1/a", "todos/2/b"} {
+		_, err := store.Open(context.Background(), key)
+		assert.ErrorIs(t, err, storage.ErrNotFound, key)
+	}
+}
+
+func TestDeleteAccountRemovesAttachments(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	store := storage.NewLocal(t.TempDir())
+	for _, key := range []string{"todos/1/a", "todos/3/c"} {
+		require.NoError(t, store.Put(context.Background(), key, bytes.NewReader([]byte("x")), 1, ""))
+	}
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("UserAttachmentKeys", int64(7)).Return([]string{"todos/1/a", "todos/3/c"}, nil)
+	mockRepo.On("DeleteUser", int64(7)).Return(nil)
+	handler := NewHandlerImpl(mockRepo, WithStorage(store))
+
+	router := gin.New()
+	router.DELETE("/me", withUser(&entity.Users{UserID: 7, Password: string(hash)}), handler.DeleteAccountHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/me", strings.NewReader(`{"password": "secret"}`)))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	for _, key := range []string{"todos/1/a", "todos/3/c"} {
+		_, err := store.Open(context.Background(), key)
+		assert.ErrorIs(t, err, storage.ErrNotFound, key)
+	}
+}
+
+func TestDeleteAttachmentNeedsEditor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 8}, entity.ShareViewer, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.DELETE("/todos/:todolistId/attachments/:attachmentId", withUser(&entity.Users{UserID: 7}), handler.DeleteAttachmentHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/todos/1/attachments/3", nil))
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestAttachmentNameCutsOnRuneBoundary(t *testing.T) {
+	// 127 two byte runes put the 255 byte limit in the middle of the 128th
+	name := attachmentName(strings.Repeat("é", 200))
+
+	assert.True(t, utf8.ValidString(name))
+	assert.Equal(t, strings.Repeat("é", 127), name)
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"net/http"
+	"todolist_gin_gorm/internal/audit"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// todoAuditIgnore are todo fields loaded for responses, not stored with it
+var todoAuditIgnore = []string{"tags", "comment_count"}
+
+// commentAuditIgnore are comment fields rendered for responses
+var commentAuditIgnore = []string{"body_html", "author_email"}
+
+// TodoHistoryHandler lists the audit events of a todo, newest first, to
+// anyone who can view it
+func (handler *HandlerImpl) TodoHistoryHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	query := new(dto.AuditQuery)
+	if err := ctx.ShouldBindQuery(&query.PageQuery); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	query.EntityType = entity.AuditTodo
+	query.EntityID = todo.Id
+
+	handler.listAuditEvents(ctx, query, "get todo history successfully")
+}
+
+// AdminListAuditEventsHandler lists the audit events of every user, filtered
+// by actor, action, entity and time
+func (handler *HandlerImpl) AdminListAuditEventsHandler(ctx *gin.Context) {
+	query := new(dto.AuditQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	handler.listAuditEvents(ctx, query, "list audit events successfully")
+}
+
+func (handler *HandlerImpl) listAuditEvents(ctx *gin.Context, query *dto.AuditQuery, message string) {
+	events, err := handler.todolistRepository.ListAuditEvents(query)
+	if err != nil {
+		logrus.Errorf("failed when list audit events: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.AuditEventResponse{
+		Message: message,
+		Status:  http.StatusOK,
+		More:    len(events),
+		Data:    events,
+	})
+}
+
+// audit records a change made by the user of the request. before is nil for
+// a create and after for a delete. A failed write is logged, the change
+// itself already happened.
+func (handler *HandlerImpl) audit(ctx *gin.Context, action string, entityType string, entityID int64, before interface{}, after interface{}) {
+	handler.auditAs(ctx, ctx.GetInt64("user_id"), action, entityType, entityID, before, after)
+}
+
+// auditAs is audit for requests made before the user is known, as a login.
+func (handler *HandlerImpl) auditAs(ctx *gin.Context, actorID int64, action string, entityType string, entityID int64, before interface{}, after interface{}) {
+	var ignore []string
+	switch entityType {
+	case entity.AuditTodo:
+		ignore = todoAuditIgnore
+	case entity.AuditComment:
+		ignore = commentAuditIgnore
+	}
+
+	changes, err := audit.Diff(before, after, ignore...)
+	if err != nil {
+		logrus.Errorf("failed to diff audit event: %v", err)
+	}
+	// an update that changed nothing is not worth an event
+	if changes == nil && action == entity.AuditUpdate {
+		return
+	}
+
+	event := &entity.AuditEvents{
+		ActorID:    actorID,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Changes:    changes,
+		IPAddress:  ctx.ClientIP(),
+		RequestID:  ctx.GetString("request_id"),
+	}
+	if err := handler.todolistRepository.CreateAuditEvent(event); err != nil {
+		logrus.Errorf("failed to write audit event: %v", err)
+	}
+}
+
+// updatedTodo returns a copy of todo with the column updates applied, as it
+// was stored.
+func updatedTodo(todo *entity.Todos, updates map[string]interface{}) *entity.Todos {
+	after := *todo
+	if err := audit.Apply(&after, updates); err != nil {
+		logrus.Errorf("failed to apply todo updates: %v", err)
+	}
+
+	return &after
+}
@@ -0,0 +1,272 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/middleware"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectAudit accepts the audit events of handlers that aren't under test
+// for them
+func expectAudit(mockRepo *mocks.Repository) {
+	mockRepo.On("CreateAuditEvent", mock.Anything).Return(nil).Maybe()
+}
+
+func TestUpdateTodolistRecordsChanges(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	existing := &entity.Todos{Id: 1, UserID: 7, Title: "sholat", Description: "sholat subuh", Status: entity.StatusTodo, Priority: entity.PriorityMedium, Timezone: "UTC", Tags: []string{"ibadah"}}
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(existing, entity.ShareOwner, nil)
+	mockRepo.On("Update", int64(1), mock.Anything).Return(existing, nil)
+	mockRepo.On("CreateAuditEvent", mock.MatchedBy(func(event *entity.AuditEvents) bool {
+		var changes map[string]struct {
+			Before interface{} `json:"before"`
+			After  interface{} `json:"after"`
+		}
+		if err := json.Unmarshal(event.Changes, &changes); err != nil {
+			return false
+		}
+
+		return event.ActorID == 7 && event.Action == entity.AuditUpdate && event.EntityType == entity.AuditTodo &&
+			event.EntityID == 1 && event.RequestID == "req-1" && event.IPAddress == "203.0.113.9" &&
+			len(changes) == 1 && changes["title"].Before == "sholat" && changes["title"].After == "sholat tahajud"
+	})).Return(nil).Once()
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.PUT("/update_todolist/:todolistId", middleware.RequestID(), withUser(&entity.Users{UserID: 7}), handler.UpdateHandlerTodolist)
+
+	body := `{"title": "sholat tahajud", "description": "sholat subuh", "status": "todo", "priority": "medium"}`
+	req, err := http.NewRequest(http.MethodPut, "/update_todolist/1", strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("X-Request-ID", "req-1")
+	req.RemoteAddr = "203.0.113.9:41234"
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestDeleteTodolistRecordsTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	existing := &entity.Todos{Id: 1, UserID: 7, Title: "sholat", Description: "sholat subuh"}
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(existing, entity.ShareOwner, nil)
+	mockRepo.On("AttachmentKeys", int64(1)).Return(nil, nil)
+	mockRepo.On("Delete", int64(1)).Return(int64(1), nil)
+	mockRepo.On("CreateAuditEvent", mock.MatchedBy(func(event *entity.AuditEvents) bool {
+		return event.Action == entity.AuditDelete && event.EntityID == 1 &&
+			strings.Contains(string(event.Changes), `"title":{"before":"sholat","after":null}`)
+	})).Return(nil).Once()
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.DELETE("/delete_todolist/:todolistId", withUser(&entity.Users{UserID: 7}), handler.DeleteHandlerTodolist)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/delete_todolist/1", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestTableDrivenTodoHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		path         string
+		mock         func(mockRepo *mocks.Repository)
+		expectedCode int
+		expectedMore int
+	}{
+		{
+			name: "owner",
+			path: "/todos/1/history?limit=5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				mockRepo.On("ListAuditEvents", mock.MatchedBy(func(query *dto.AuditQuery) bool {
+					return query.EntityType == entity.AuditTodo && query.EntityID == 1 && query.Limit == 5 && query.ActorID == 0
+				})).Return([]entity.AuditEvents{
+					{EventID: 2, ActorID: 7, Action: entity.AuditUpdate, EntityType: entity.AuditTodo, EntityID: 1, CreatedAt: createdAt},
+					{EventID: 1, ActorID: 7, Action: entity.AuditCreate, EntityType: entity.AuditTodo, EntityID: 1, CreatedAt: createdAt},
+				}, nil)
+			},
+			expectedCode: http.StatusOK,
+			expectedMore: 2,
+		},
+		{
+			name: "someone else's todo",
+			path: "/todos/1/history",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(nil, "", nil)
+			},
+			expectedCode: http.StatusNotFound,
+		},
+		{
+			name: "invalid page",
+			path: "/todos/1/history?page=0&limit=500",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+			},
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			tc.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.GET("/todos/:todolistId/history", withUser(&entity.Users{UserID: 7}), handler.TodoHistoryHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+			if tc.expectedCode == http.StatusOK {
+				var result dto.AuditEventResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+				assert.Equal(t, tc.expectedMore, result.More)
+				assert.Equal(t, entity.AuditUpdate, result.Data[0].Action)
+			}
+		})
+	}
+}
+
+func TestTableDrivenAdminListAuditEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name         string
+		path         string
+		lookup       bool
+		expectedCode int
+	}{
+		{name: "filters", path: "/admin/audit_events?actor_id=7&action=login&entity_type=user&since=2026-10-01T00:00:00%2B07:00", lookup: true, expectedCode: http.StatusOK},
+		{name: "unknown entity type", path: "/admin/audit_events?entity_type=comment", expectedCode: http.StatusBadRequest},
+		{name: "invalid time", path: "/admin/audit_events?until=yesterday", expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			if tc.lookup {
+				mockRepo.On("ListAuditEvents", mock.MatchedBy(func(query *dto.AuditQuery) bool {
+					return query.ActorID == 7 && query.Action == entity.AuditLogin && query.EntityType == entity.AuditUser &&
+						query.Since.Equal(time.Date(2026, 9, 30, 17, 0, 0, 0, time.UTC)) && query.Until.IsZero()
+				})).Return([]entity.AuditEvents{{EventID: 1, ActorID: 7, Action: entity.AuditLogin}}, nil)
+			}
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.GET("/admin/audit_events", withUser(adminUser), handler.AdminListAuditEventsHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenAccountActionsRecordEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		method         string
+		path           string
+		route          func(router *gin.Engine, handler *HandlerImpl)
+		mock           func(m *mocks.Repository)
+		expectedAction string
+		expectedEntity string
+		expectedID     int64
+	}{
+		{
+			name:   "revoke session",
+			method: http.MethodDelete,
+			path:   "/me/sessions/9",
+			route: func(router *gin.Engine, handler *HandlerImpl) {
+				router.DELETE("/me/sessions/:sessionId", withUser(&entity.Users{UserID: 7}), handler.RevokeSessionHandler)
+			},
+			mock: func(m *mocks.Repository) {
+				m.On("RevokeSession", int64(7), int64(9)).Return(int64(1), nil)
+			},
+			expectedAction: entity.AuditRevoke,
+			expectedEntity: entity.AuditSession,
+			expectedID:     9,
+		},
+		{
+			name:   "revoke api key",
+			method: http.MethodDelete,
+			path:   "/me/api_keys/3",
+			route: func(router *gin.Engine, handler *HandlerImpl) {
+				router.DELETE("/me/api_keys/:keyId", withUser(&entity.Users{UserID: 7}), handler.RevokeAPIKeyHandler)
+			},
+			mock: func(m *mocks.Repository) {
+				m.On("RevokeAPIKey", int64(7), int64(3)).Return(int64(1), nil)
+			},
+			expectedAction: entity.AuditRevoke,
+			expectedEntity: entity.AuditAPIKey,
+			expectedID:     3,
+		},
+		{
+			name:   "delete tag",
+			method: http.MethodDelete,
+			path:   "/tags/4",
+			route: func(router *gin.Engine, handler *HandlerImpl) {
+				router.DELETE("/tags/:tagId", withUser(&entity.Users{UserID: 7}), handler.DeleteTagHandler)
+			},
+			mock: func(m *mocks.Repository) {
+				m.On("FindTag", int64(7), int64(4)).Return(&entity.Tags{TagID: 4, UserID: 7, Name: "ibadah"}, nil)
+				m.On("DeleteTag", int64(4)).Return(nil)
+			},
+			expectedAction: entity.AuditDelete,
+			expectedEntity: entity.AuditTag,
+			expectedID:     4,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			test.mock(mockRepo)
+			mockRepo.On("CreateAuditEvent", mock.MatchedBy(func(event *entity.AuditEvents) bool {
+				return event.ActorID == 7 && event.Action == test.expectedAction &&
+					event.EntityType == test.expectedEntity && event.EntityID == test.expectedID
+			})).Return(nil).Once()
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			test.route(router, handler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(test.method, test.path, nil))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginUnknownEmailLooksLikeWrongPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	login := func(user *entity.Users) *httptest.ResponseRecorder {
+		mockRepo := mocks.NewRepository(t)
+		mockRepo.On("FindUserByEmail", "alwi@mail.com").Return(user, nil)
+
+		handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{BcryptCost: bcrypt.MinCost}))
+
+		router := gin.New()
+		router.POST("/login", handler.LoginHandler)
+
+		req, err := http.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email": "alwi@mail.com", "password": "wrong-password"}`))
+		require.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	unknown := login(nil)
+	wrongPassword := login(&entity.Users{UserID: 7, Email: "alwi@mail.com", Password: string(hash)})
+
+	assert.Equal(t, http.StatusUnauthorized, unknown.Code)
+	assert.Equal(t, wrongPassword.Code, unknown.Code)
+	assert.Equal(t, wrongPassword.Body.String(), unknown.Body.String())
+}
+
+func TestDummyPasswordHashUsesConfiguredCost(t *testing.T) {
+	handler := NewHandlerImpl(mocks.NewRepository(t), WithConfig(&config.Config{BcryptCost: bcrypt.MinCost + 1}))
+
+	cost, err := bcrypt.Cost(handler.dummyPasswordHash())
+	require.NoError(t, err)
+	assert.Equal(t, bcrypt.MinCost+1, cost)
+}
+
+func TestTableDrivenRegister(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	verifiedAt := time.Now()
+	existing := &entity.Users{UserID: 7, Username: "alwi", Email: "alwi@mail.com", EmailVerifiedAt: &verifiedAt}
+
+	testCase := []struct {
+		name            string
+		enumerationSafe bool
+		mock            func(m *mocks.Repository)
+		expectedStatus  int
+		expectedMail    string
+	}{
+		{
+			name: "taken email",
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByEmail", "alwi@mail.com").Return(existing, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "new email",
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByEmail", "alwi@mail.com").Return(nil, nil)
+				m.On("CreateUser", mock.AnythingOfType("*entity.Users")).Return(nil)
+				m.On("CreateEmailVerificationToken", mock.AnythingOfType("*entity.EmailVerificationTokens")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMail:   "Verify your email address",
+		},
+		{
+			name:            "enumeration safe, taken email",
+			enumerationSafe: true,
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByEmail", "alwi@mail.com").Return(existing, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedMail:   "You already have an account",
+		},
+		{
+			name:            "enumeration safe, new email",
+			enumerationSafe: true,
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByEmail", "alwi@mail.com").Return(nil, nil)
+				m.On("CreateUser", mock.AnythingOfType("*entity.Users")).Return(nil)
+				m.On("CreateEmailVerificationToken", mock.AnythingOfType("*entity.EmailVerificationTokens")).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+			expectedMail:   "Verify your email address",
+		},
+	}
+
+	var acceptedBodies []string
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+
+			mail := &recordingMailer{}
+			handler := NewHandlerImpl(mockRepo, WithMailer(mail), WithConfig(&config.Config{
+				BcryptCost:                  bcrypt.MinCost,
+				EnumerationSafeRegistration: test.enumerationSafe,
+			}))
+
+			router := gin.New()
+			router.POST("/register", handler.RegisterHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/register", strings.NewReader(`{"username": "alwi", "email": "alwi@mail.com", "password": "secret-password"}`))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedMail == "" {
+				assert.Empty(t, mail.messages)
+			} else {
+				require.Len(t, mail.messages, 1)
+				assert.Equal(t, test.expectedMail, mail.messages[0].Subject)
+			}
+
+			if recorder.Code == http.StatusAccepted {
+				var result dto.CreateUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+				acceptedBodies = append(acceptedBodies, recorder.Body.String())
+			}
+		})
+	}
+
+	require.Len(t, acceptedBodies, 2)
+	assert.Equal(t, acceptedBodies[0], acceptedBodies[1])
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/sirupsen/logrus"
+)
+
+// errBulkRollback aborts the transaction of an atomic bulk request
+var errBulkRollback = errors.New("bulk operation failed")
+
+// operationError fails a todo operation with the status to answer, a bulk
+// operation with the one its own endpoint would have answered
+type operationError struct {
+	status  int
+	message string
+}
+
+func (err *operationError) Error() string {
+	return err.message
+}
+
+// bulkOperationResult is what a successful operation reports and records in
+// the audit log once the transaction is committed
+type bulkOperationResult struct {
+	status int
+	data   *entity.Todos
+	action string
+	todoID int64
+	before *entity.Todos
+	after  *entity.Todos
+	// files are the stored attachments to remove
+	files []string
+	// snapshot is what an undo of the operation restores
+	snapshot *entity.UndoSnapshot
+}
+
+// BulkTodosHandler creates, updates and deletes many todos in one
+// transaction. Every operation runs in a savepoint of its own: in atomic mode
+// the first failure rolls everything back, in partial mode it only undoes
+// that operation. The result of each operation is returned in order.
+func (handler *HandlerImpl) BulkTodosHandler(ctx *gin.Context) {
+	request := new(dto.BulkRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		logrus.Error(err.Error())
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if limit := handler.cfg.BulkMaxOperations; limit > 0 && len(request.Operations) > limit {
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+			Message: fmt.Sprintf("at most %d operations are allowed", limit),
+			Status:  http.StatusRequestEntityTooLarge,
+		})
+		return
+	}
+
+	if request.Mode == "" {
+		request.Mode = dto.BulkAtomic
+	}
+
+	userID := ctx.GetInt64("user_id")
+	results := make([]dto.BulkResult, len(request.Operations))
+	done := make([]*bulkOperationResult, len(request.Operations))
+	failed := -1
+
+	err := handler.todolistRepository.Transaction(func(tx repository.Repository) error {
+		for i, operation := range request.Operations {
+			results[i] = dto.BulkResult{Index: i, Op: operation.Op, ID: operation.ID}
+
+			var result *bulkOperationResult
+			err := tx.Transaction(func(item repository.Repository) error {
+				var err error
+				result, err = handler.runBulkOperation(item, userID, operation)
+				return err
+			})
+			if err != nil {
+				results[i].Status, results[i].Message = operationFailure(err)
+				if request.Mode == dto.BulkAtomic {
+					failed = i
+					return errBulkRollback
+				}
+				continue
+			}
+
+			results[i].Status = result.status
+			results[i].Message = operation.Op + " todolist successfully"
+			results[i].Data = result.data
+			done[i] = result
+		}
+
+		return nil
+	})
+
+	if errors.Is(err, errBulkRollback) {
+		for i := range results {
+			switch {
+			case i < failed:
+				results[i] = dto.BulkResult{Index: i, Op: results[i].Op, ID: results[i].ID, Status: http.StatusFailedDependency, Message: "rolled back"}
+			case i > failed:
+				operation := request.Operations[i]
+				results[i] = dto.BulkResult{Index: i, Op: operation.Op, ID: operation.ID, Status: http.StatusFailedDependency, Message: "not run"}
+			}
+		}
+
+		ctx.AbortWithStatusJSON(results[failed].Status, dto.BulkResponse{
+			Message: "bulk operations rolled back",
+			Status:  results[failed].Status,
+			Mode:    request.Mode,
+			More:    len(results),
+			Data:    results,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when run bulk operations: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	for i, result := range done {
+		// failed operations and updates that changed nothing leave no trace
+		if result == nil || result.action == "" {
+			continue
+		}
+
+		handler.removeStoredFiles(ctx, result.files)
+		handler.audit(ctx, result.action, entity.AuditTodo, result.todoID, result.before, result.after)
+		results[i].OperationID = handler.recordUndo(ctx, result.action, result.todoID, result.snapshot)
+	}
+
+	logrus.Info(http.StatusOK, "bulk operations done")
+	ctx.JSON(http.StatusOK, dto.BulkResponse{
+		Message: "bulk operations done",
+		Status:  http.StatusOK,
+		Mode:    request.Mode,
+		More:    len(results),
+		Data:    results,
+	})
+}
+
+// runBulkOperation runs one operation against repo.
+func (handler *HandlerImpl) runBulkOperation(repo repository.Repository, userID int64, operation dto.BulkOperation) (*bulkOperationResult, error) {
+	switch operation.Op {
+	case "create":
+		return bulkCreate(repo, userID, operation)
+	case "update":
+		return bulkUpdate(repo, userID, operation)
+	default:
+		return handler.bulkDelete(repo, userID, operation)
+	}
+}
+
+func bulkCreate(repo repository.Repository, userID int64, operation dto.BulkOperation) (*bulkOperationResult, error) {
+	request := new(dto.CreateTodolistRequest)
+	if err := bindBulkTodo(operation, request); err != nil {
+		return nil, err
+	}
+
+	todo, err := createTodo(repo, userID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bulkOperationResult{status: http.StatusCreated, data: todo, action: entity.AuditCreate, todoID: todo.Id, after: todo}, nil
+}
+
+func bulkUpdate(repo repository.Repository, userID int64, operation dto.BulkOperation) (*bulkOperationResult, error) {
+	current, err := bulkTodo(repo, userID, operation.ID, entity.ShareEditor)
+	if err != nil {
+		return nil, err
+	}
+
+	request := new(dto.UpdateTodolistRequest)
+	if err := bindBulkTodo(operation, request); err != nil {
+		return nil, err
+	}
+
+	updated, err := updateTodo(repo, current, request)
+	if err != nil {
+		return nil, err
+	}
+	if updated == nil {
+		return &bulkOperationResult{status: http.StatusOK, data: current}, nil
+	}
+
+	return &bulkOperationResult{
+		status:   http.StatusOK,
+		data:     updated,
+		action:   entity.AuditUpdate,
+		todoID:   current.Id,
+		before:   current,
+		after:    updated,
+		snapshot: &entity.UndoSnapshot{Todos: []entity.Todos{*current}},
+	}, nil
+}
+
+func (handler *HandlerImpl) bulkDelete(repo repository.Repository, userID int64, operation dto.BulkOperation) (*bulkOperationResult, error) {
+	todo, err := bulkTodo(repo, userID, operation.ID, entity.ShareOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	// the attachments of the todo and its subtasks are deleted with it
+	files, err := repo.AttachmentKeys(todo.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// taken before the delete, so it can be undone
+	snapshot, err := handler.todoSnapshot(repo, todo.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repo.Delete(todo.Id); err != nil {
+		return nil, err
+	}
+
+	return &bulkOperationResult{status: http.StatusOK, action: entity.AuditDelete, todoID: todo.Id, before: todo, files: files, snapshot: snapshot}, nil
+}
+
+// bulkTodo loads the todo an operation targets, the user needs the required
+// role on it.
+func bulkTodo(repo repository.Repository, userID int64, todoID int64, required string) (*entity.Todos, error) {
+	if todoID <= 0 {
+		return nil, &operationError{status: http.StatusBadRequest, message: "id is required"}
+	}
+
+	todo, role, err := repo.FindTodoForUser(userID, todoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if todo == nil {
+		return nil, &operationError{status: http.StatusNotFound, message: "todolist by id not found"}
+	}
+	if !entity.ShareRoleAllows(role, required) {
+		return nil, &operationError{status: http.StatusForbidden, message: "your role on this todo does not allow it"}
+	}
+
+	return todo, nil
+}
+
+// bindBulkTodo decodes and validates the todo of an operation.
+func bindBulkTodo(operation dto.BulkOperation, request interface{}) error {
+	if len(operation.Todo) == 0 {
+		return &operationError{status: http.StatusBadRequest, message: operation.Op + " needs a todo"}
+	}
+
+	if err := binding.JSON.BindBody(operation.Todo, request); err != nil {
+		logrus.Error(err.Error())
+		return &operationError{status: http.StatusBadRequest, message: "invalid input validation"}
+	}
+
+	return nil
+}
+
+// operationFailure is the status and message reported for a failed
+// operation, unexpected errors are logged and hidden.
+func operationFailure(err error) (int, string) {
+	var failure *operationError
+	if errors.As(err, &failure) {
+		return failure.status, failure.message
+	}
+
+	logrus.Errorf("failed when run todo operation: %v", err)
+	return http.StatusInternalServerError, "internal server error"
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// runTransactions makes the mock run transactions against itself
+func runTransactions(mockRepo *mocks.Repository) {
+	mockRepo.On("Transaction", mock.Anything).Return(func(fn func(repository.Repository) error) error {
+		return fn(mockRepo)
+	})
+}
+
+func serveBulk(t *testing.T, handler *HandlerImpl, body string) (*httptest.ResponseRecorder, dto.BulkResponse) {
+	router := gin.New()
+	router.POST("/todos/bulk", withUser(&entity.Users{UserID: 7}), handler.BulkTodosHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/todos/bulk", strings.NewReader(body)))
+
+	var result dto.BulkResponse
+	if recorder.Code != http.StatusBadRequest && recorder.Code != http.StatusRequestEntityTooLarge {
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	}
+
+	return recorder, result
+}
+
+func TestBulkTodosPartial(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	runTransactions(mockRepo)
+	mockRepo.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+		return todo.Title == "sholat" && todo.UserID == 7 && todo.Status == entity.StatusTodo
+	})).Return(nil)
+	mockRepo.On("FindTodoForUser", int64(7), int64(404)).Return(nil, "", nil)
+	mockRepo.On("FindTodoForUser", int64(7), int64(5)).Return(&entity.Todos{Id: 5, UserID: 7, Title: "puasa", Description: "senin kamis", Status: entity.StatusInProgress}, entity.ShareOwner, nil)
+	mockRepo.On("Update", int64(5), mock.MatchedBy(func(updates map[string]interface{}) bool {
+		return updates["status"] == entity.StatusDone && updates["completed_at"] != nil
+	})).Return(&entity.Todos{Id: 5, UserID: 7, Status: entity.StatusDone}, nil)
+	mockRepo.On("CloseSubtasks", int64(5), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("FindTodoForUser", int64(7), int64(6)).Return(&entity.Todos{Id: 6, UserID: 7}, entity.ShareOwner, nil)
+	mockRepo.On("AttachmentKeys", int64(6)).Return([]string{}, nil)
+	mockRepo.On("Delete", int64(6)).Return(int64(6), nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	recorder, result := serveBulk(t, handler, `{"mode":"partial","operations":[
+		{"op":"create","todo":{"title":"sholat","description":"subuh berjamaah"}},
+		{"op":"delete","id":404},
+		{"op":"update","id":5,"todo":{"title":"puasa","description":"senin kamis","status":"done"}},
+		{"op":"create","todo":{"title":"x"}},
+		{"op":"delete","id":6}
+	]}`)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, dto.BulkPartial, result.Mode)
+	require.Len(t, result.Data, 5)
+
+	statuses := make([]int, 0, len(result.Data))
+	for _, operation := range result.Data {
+		statuses = append(statuses, operation.Status)
+	}
+	assert.Equal(t, []int{http.StatusCreated, http.StatusNotFound, http.StatusOK, http.StatusBadRequest, http.StatusOK}, statuses)
+	assert.Equal(t, "sholat", result.Data[0].Data.Title)
+	assert.Equal(t, entity.StatusDone, result.Data[2].Data.Status)
+	assert.Equal(t, "puasa", result.Data[2].Data.Title)
+	assert.Equal(t, "senin kamis", result.Data[2].Data.Description)
+}
+
+func TestBulkTodosRecordUndo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	current := &entity.Todos{Id: 5, UserID: 7, Title: "puasa", Description: "senin kamis", Status: entity.StatusTodo}
+	updated := &entity.Todos{Id: 5, UserID: 7, Title: "puasa sunnah", Description: "senin kamis", Status: entity.StatusTodo}
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	runTransactions(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(5)).Return(current, entity.ShareOwner, nil)
+	mockRepo.On("Update", int64(5), mock.Anything).Return(&entity.Todos{}, nil)
+	mockRepo.On("GetID", int64(5)).Return(updated, nil)
+	mockRepo.On("FindTodoForUser", int64(7), int64(6)).Return(&entity.Todos{Id: 6, UserID: 7}, entity.ShareOwner, nil)
+	mockRepo.On("AttachmentKeys", int64(6)).Return([]string{}, nil)
+	mockRepo.On("TodoSnapshot", int64(6)).Return(&entity.UndoSnapshot{Todos: []entity.Todos{{Id: 6, UserID: 7}}}, nil)
+	mockRepo.On("Delete", int64(6)).Return(int64(1), nil)
+	mockRepo.On("CreateUndoOperation", mock.MatchedBy(func(operation *entity.UndoOperations) bool {
+		return operation.Action == entity.AuditUpdate && operation.TodoID == 5 &&
+			strings.Contains(string(operation.Snapshot), `"title":"puasa"`)
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.UndoOperations).OperationID = 11
+	}).Return(nil)
+	mockRepo.On("CreateUndoOperation", mock.MatchedBy(func(operation *entity.UndoOperations) bool {
+		return operation.Action == entity.AuditDelete && operation.TodoID == 6
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.UndoOperations).OperationID = 12
+	}).Return(nil)
+	handler := NewHandlerImpl(mockRepo, WithConfig(undoConfig))
+
+	recorder, result := serveBulk(t, handler, `{"operations":[
+		{"op":"update","id":5,"todo":{"title":"puasa sunnah","description":"senin kamis"}},
+		{"op":"delete","id":6}
+	]}`)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Len(t, result.Data, 2)
+	assert.Equal(t, int64(11), result.Data[0].OperationID)
+	assert.Equal(t, int64(12), result.Data[1].OperationID)
+}
+
+func TestBulkTodosAtomicRollsBack(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	runTransactions(mockRepo)
+	mockRepo.On("Create", mock.AnythingOfType("*entity.Todos")).Return(nil)
+	mockRepo.On("FindTodoForUser", int64(7), int64(9)).Return(&entity.Todos{Id: 9, UserID: 8}, entity.ShareViewer, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	recorder, result := serveBulk(t, handler, `{"operations":[
+		{"op":"create","todo":{"title":"sholat","description":"subuh berjamaah"}},
+		{"op":"delete","id":9},
+		{"op":"delete","id":10}
+	]}`)
+
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, dto.BulkAtomic, result.Mode)
+	require.Len(t, result.Data, 3)
+	assert.Equal(t, http.StatusFailedDependency, result.Data[0].Status)
+	assert.Nil(t, result.Data[0].Data)
+	assert.Equal(t, http.StatusForbidden, result.Data[1].Status)
+	assert.Equal(t, http.StatusFailedDependency, result.Data[2].Status)
+	mockRepo.AssertNotCalled(t, "FindTodoForUser", int64(7), int64(10))
+}
+
+func TestTableDrivenBulkTodosInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name         string
+		body         string
+		expectedCode int
+	}{
+		{name: "no operations", body: `{"operations":[]}`, expectedCode: http.StatusBadRequest},
+		{name: "unknown op", body: `{"operations":[{"op":"archive","id":1}]}`, expectedCode: http.StatusBadRequest},
+		{name: "unknown mode", body: `{"mode":"best","operations":[{"op":"delete","id":1}]}`, expectedCode: http.StatusBadRequest},
+		{name: "too many", body: `{"operations":[{"op":"delete","id":1},{"op":"delete","id":2},{"op":"delete","id":3}]}`, expectedCode: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewHandlerImpl(mocks.NewRepository(t), WithConfig(&config.Config{BulkMaxOperations: 2}))
+
+			recorder, _ := serveBulk(t, handler, tc.body)
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/markdown"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// GetCommentsHandler returns the comment thread of a todo, oldest first
+func (handler *HandlerImpl) GetCommentsHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	comments, err := handler.todolistRepository.GetComments(todo.Id)
+	if err != nil {
+		logrus.Errorf("failed when get comments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	for i := range comments {
+		comments[i].BodyHTML = markdown.Render(comments[i].Body)
+	}
+
+	ctx.JSON(http.StatusOK, dto.CommentResponseGetAll{
+		Message: "get all comments successfully",
+		Status:  http.StatusOK,
+		More:    len(comments),
+		Data:    comments,
+	})
+}
+
+// CreateCommentHandler posts a comment as the logged in user, everyone the
+// todo is shared with can take part in the thread
+func (handler *HandlerImpl) CreateCommentHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	body, ok := bindCommentBody(ctx)
+	if !ok {
+		return
+	}
+
+	comment := &entity.Comments{
+		TodoID: todo.Id,
+		UserID: ctx.GetInt64("user_id"),
+		Body:   body,
+	}
+	if err := handler.todolistRepository.CreateComment(comment); err != nil {
+		logrus.Errorf("failed when create comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditCreate, entity.AuditComment, comment.CommentID, nil, comment)
+
+	comment.BodyHTML = markdown.Render(comment.Body)
+	comment.AuthorEmail = ctx.GetString("email")
+	logrus.Info(http.StatusCreated, "create comment successfully")
+	ctx.JSON(http.StatusCreated, dto.CommentResponse{
+		Message: "create comment successfully",
+		Status:  http.StatusCreated,
+		Data:    *comment,
+	})
+}
+
+// UpdateCommentHandler lets the author change a comment during the
+// configured edit window
+func (handler *HandlerImpl) UpdateCommentHandler(ctx *gin.Context) {
+	_, comment, ok := handler.todoComment(ctx)
+	if !ok {
+		return
+	}
+
+	if comment.UserID != ctx.GetInt64("user_id") {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "only the author can edit a comment",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	now := time.Now()
+	if !comment.Editable(now, handler.cfg.CommentEditWindow) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "the comment can no longer be edited",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	body, ok := bindCommentBody(ctx)
+	if !ok {
+		return
+	}
+
+	if err := handler.todolistRepository.UpdateComment(comment.CommentID, body, now); err != nil {
+		logrus.Errorf("failed when update comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	before := *comment
+	comment.Body = body
+	comment.EditedAt = &now
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditComment, comment.CommentID, before, comment)
+
+	comment.BodyHTML = markdown.Render(body)
+	logrus.Info(http.StatusOK, "update comment successfully")
+	ctx.JSON(http.StatusOK, dto.CommentResponse{
+		Message: "update comment successfully",
+		Status:  http.StatusOK,
+		Data:    *comment,
+	})
+}
+
+// DeleteCommentHandler removes a comment from the thread. The author and the
+// owner of the todo can delete it.
+func (handler *HandlerImpl) DeleteCommentHandler(ctx *gin.Context) {
+	todo, comment, ok := handler.todoComment(ctx)
+	if !ok {
+		return
+	}
+
+	userID := ctx.GetInt64("user_id")
+	if comment.UserID != userID && todo.UserID != userID {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "only the author or the owner of the todo can delete a comment",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	if _, err := handler.todolistRepository.DeleteComment(comment.CommentID); err != nil {
+		logrus.Errorf("failed when delete comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditDelete, entity.AuditComment, comment.CommentID, comment, nil)
+
+	logrus.Info(http.StatusOK, "delete comment successfully")
+	ctx.JSON(http.StatusOK, dto.CommentResponseDelete{
+		Message: "delete comment successfully",
+		Status:  http.StatusOK,
+	})
+}
+
+// todoComment loads the todo named by :todolistId and its comment named by
+// :commentId, the user must be able to see the todo.
+func (handler *HandlerImpl) todoComment(ctx *gin.Context) (*entity.Todos, *entity.Comments, bool) {
+	todo, ok := handler.todoFor(ctx, entity.ShareViewer)
+	if !ok {
+		return nil, nil, false
+	}
+
+	commentID, err := strconv.ParseInt(ctx.Param("commentId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, nil, false
+	}
+
+	comment, err := handler.todolistRepository.FindComment(commentID)
+	if err != nil {
+		logrus.Errorf("failed when get comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, nil, false
+	}
+
+	if comment == nil || comment.TodoID != todo.Id {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "comment not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, nil, false
+	}
+
+	return todo, comment, true
+}
+
+// bindCommentBody reads the comment body, a blank one is refused
+func bindCommentBody(ctx *gin.Context) (string, bool) {
+	request := new(dto.CommentRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil || strings.TrimSpace(request.Body) == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return "", false
+	}
+
+	return strings.TrimSpace(request.Body), true
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCommentRendersMarkdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 8}, entity.ShareViewer, nil)
+	mockRepo.On("CreateComment", mock.MatchedBy(func(comment *entity.Comments) bool {
+		return comment.TodoID == 1 && comment.UserID == 7 && comment.Body == "**done** <script>x</script>"
+	})).Return(nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/todos/:todolistId/comments", withUser(&entity.Users{UserID: 7, Email: "alwi@mail.com"}), handler.CreateCommentHandler)
+
+	body := `{"body": " **done** <script>x</script> "}`
+	req, err := http.NewRequest(http.MethodPost, "/todos/1/comments", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+
+	var result dto.CommentResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	assert.Equal(t, "<p><strong>done</strong> &lt;script&gt;x&lt;/script&gt;</p>", result.Data.BodyHTML)
+	assert.Equal(t, "alwi@mail.com", result.Data.AuthorEmail)
+}
+
+func TestTableDrivenUpdateComment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		userID         int64
+		comment        *entity.Comments
+		expectedStatus int
+	}{
+		{
+			name:           "author within the window",
+			userID:         7,
+			comment:        &entity.Comments{CommentID: 3, TodoID: 1, UserID: 7, CreatedAt: time.Now().Add(-time.Minute)},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "window has passed",
+			userID:         7,
+			comment:        &entity.Comments{CommentID: 3, TodoID: 1, UserID: 7, CreatedAt: time.Now().Add(-time.Hour)},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "not the author",
+			userID:         7,
+			comment:        &entity.Comments{CommentID: 3, TodoID: 1, UserID: 8, CreatedAt: time.Now()},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "comment of another todo",
+			userID:         7,
+			comment:        &entity.Comments{CommentID: 3, TodoID: 2, UserID: 7, CreatedAt: time.Now()},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+			mockRepo.On("FindComment", int64(3)).Return(test.comment, nil)
+			if test.expectedStatus == http.StatusOK {
+				mockRepo.On("UpdateComment", int64(3), "edited", mock.AnythingOfType("time.Time")).Return(nil)
+			}
+			handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{CommentEditWindow: 15 * time.Minute}))
+
+			router := gin.New()
+			router.PATCH("/todos/:todolistId/comments/:commentId", withUser(&entity.Users{UserID: test.userID}), handler.UpdateCommentHandler)
+
+			req, err := http.NewRequest(http.MethodPatch, "/todos/1/comments/3", strings.NewReader(`{"body": "edited"}`))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenDeleteComment(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		userID         int64
+		expectedStatus int
+	}{
+		{
+			name:           "author",
+			userID:         8,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "owner of the todo",
+			userID:         7,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "another viewer",
+			userID:         9,
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			todo := &entity.Todos{Id: 1, UserID: 7}
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			role := entity.ShareOwner
+			if test.userID != 7 {
+				role = entity.ShareViewer
+			}
+			mockRepo.On("FindTodoForUser", test.userID, int64(1)).Return(todo, role, nil)
+			mockRepo.On("FindComment", int64(3)).Return(&entity.Comments{CommentID: 3, TodoID: 1, UserID: 8}, nil)
+			if test.expectedStatus == http.StatusOK {
+				mockRepo.On("DeleteComment", int64(3)).Return(int64(1), nil)
+			}
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.DELETE("/todos/:todolistId/comments/:commentId", withUser(&entity.Users{UserID: test.userID}), handler.DeleteCommentHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/todos/1/comments/3", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// currentUser returns the user stored in the context by AuthMiddlewareJWT.
+func currentUser(ctx *gin.Context) (*entity.Users, bool) {
+	value, exists := ctx.Get("user")
+	if !exists {
+		return nil, false
+	}
+
+	user, ok := value.(*entity.Users)
+	return user, ok && user != nil
+}
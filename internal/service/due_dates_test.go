@@ -0,0 +1,244 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDrivenCreateTodolistDueDates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dueAt := time.Date(2026, 11, 1, 9, 0, 0, 0, time.FixedZone("WIB", 7*60*60))
+
+	testCase := []struct {
+		name           string
+		bodyRequest    string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:        "due date with timezone",
+			bodyRequest: `{"title": "sholat", "description": "sholat tahajud", "due_at": "2026-11-01T09:00:00+07:00", "timezone": "Asia/Jakarta", "remind_at": "2026-11-01T08:30:00+07:00"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+					return todo.DueAt != nil && todo.DueAt.Equal(dueAt) &&
+						todo.RemindAt != nil && todo.RemindAt.Equal(dueAt.Add(-30*time.Minute)) &&
+						todo.Timezone == "Asia/Jakarta"
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown timezone",
+			bodyRequest:    `{"title": "sholat", "description": "sholat tahajud", "due_at": "2026-11-01T09:00:00+07:00", "timezone": "Mars/Olympus"}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "due date without offset",
+			bodyRequest:    `{"title": "sholat", "description": "sholat tahajud", "due_at": "2026-11-01 09:00"}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/create_todolist", handler.CreateHandlerTodolist)
+
+			req, err := http.NewRequest(http.MethodPost, "/create_todolist", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenGetAllTodolistDueFilters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		query          string
+		expectedQuery  func(query *dto.TodoQuery) bool
+		expectedStatus int
+	}{
+		{
+			name:  "due range",
+			query: "?due_after=2026-11-01T00:00:00Z&due_before=2026-11-08T00:00:00%2B07:00",
+			expectedQuery: func(query *dto.TodoQuery) bool {
+				return query.DueAfter.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)) &&
+					query.DueBefore.Equal(time.Date(2026, 11, 7, 17, 0, 0, 0, time.UTC)) &&
+					!query.Overdue
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:  "overdue",
+			query: "?overdue=true",
+			expectedQuery: func(query *dto.TodoQuery) bool {
+				return query.Overdue && query.DueBefore.IsZero() && query.DueAfter.IsZero()
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "invalid date",
+			query:          "?due_before=tomorrow",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			if test.expectedQuery != nil {
+				mockRepo.On("GetAll", mock.MatchedBy(test.expectedQuery)).Return([]entity.Todos{}, nil)
+			}
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.GET("/find_all_todolist", handler.GetAllHandlerTodolist)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/find_all_todolist"+test.query, nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestUpdateTodolistMovedReminderFiresAgain(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	remindedAt := time.Now().Add(-time.Hour)
+	remindAt := time.Date(2026, 11, 1, 1, 30, 0, 0, time.UTC)
+	existing := &entity.Todos{Id: 1, Title: "sholat", RemindAt: &remindAt, RemindedAt: &remindedAt}
+
+	testCase := []struct {
+		name          string
+		remindAt      string
+		expectedReset bool
+	}{
+		{name: "same reminder", remindAt: "2026-11-01T08:30:00+07:00", expectedReset: false},
+		{name: "moved reminder", remindAt: "2026-11-02T08:30:00+07:00", expectedReset: true},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(0), int64(1)).Return(existing, entity.ShareOwner, nil)
+			mockRepo.On("Update", int64(1), mock.MatchedBy(func(updates map[string]interface{}) bool {
+				_, reset := updates["reminded_at"]
+				return reset == test.expectedReset
+			})).Return(existing, nil)
+
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.PUT("/update_todolist/:todolistId", handler.UpdateHandlerTodolist)
+
+			body := `{"title": "sholat", "description": "sholat tahajud", "remind_at": "` + test.remindAt + `"}`
+			req, err := http.NewRequest(http.MethodPut, "/update_todolist/1", strings.NewReader(body))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenUpdateTodolistKeepsUnsentDueDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dueAt := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+	remindAt := dueAt.Add(-time.Hour)
+
+	testCase := []struct {
+		name            string
+		bodyRequest     string
+		expectedUpdates map[string]interface{}
+	}{
+		{
+			name:            "fields left out",
+			bodyRequest:     `{"title": "sholat", "description": "sholat tahajud", "status": true}`,
+			expectedUpdates: map[string]interface{}{},
+		},
+		{
+			name:        "fields cleared",
+			bodyRequest: `{"title": "sholat", "description": "sholat tahajud", "due_at": null, "remind_at": null, "timezone": ""}`,
+			expectedUpdates: map[string]interface{}{
+				"due_at":      (*time.Time)(nil),
+				"remind_at":   (*time.Time)(nil),
+				"reminded_at": nil,
+				"timezone":    "UTC",
+			},
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			existing := &entity.Todos{Id: 1, Title: "sholat", Status: entity.StatusTodo, DueAt: &dueAt, RemindAt: &remindAt, Timezone: "Asia/Jakarta"}
+
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(0), int64(1)).Return(existing, entity.ShareOwner, nil)
+			mockRepo.On("Update", int64(1), mock.MatchedBy(func(updates map[string]interface{}) bool {
+				for _, column := range []string{"due_at", "remind_at", "reminded_at", "timezone"} {
+					value, ok := updates[column]
+					expected, expectedOK := test.expectedUpdates[column]
+					if ok != expectedOK || !assert.ObjectsAreEqual(expected, value) {
+						return false
+					}
+				}
+				return true
+			})).Return(existing, nil)
+			mockRepo.On("CloseSubtasks", int64(1), entity.StatusDone, mock.Anything).Return(nil).Maybe()
+
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.PUT("/update_todolist/:todolistId", handler.UpdateHandlerTodolist)
+
+			req, err := http.NewRequest(http.MethodPut, "/update_todolist/1", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		})
+	}
+}
+
+func TestTodoAfterFindUsesTimezone(t *testing.T) {
+	dueAt := time.Date(2026, 11, 1, 2, 0, 0, 0, time.UTC)
+	todo := &entity.Todos{DueAt: &dueAt, Timezone: "Asia/Jakarta"}
+
+	require.NoError(t, todo.AfterFind(nil))
+
+	assert.Equal(t, "2026-11-01T09:00:00+07:00", todo.DueAt.Format(time.RFC3339))
+	assert.True(t, todo.Overdue(dueAt.Add(time.Minute)))
+}
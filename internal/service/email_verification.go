@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+	"todolist_gin_gorm/internal/mailer"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+	"todolist_gin_gorm/internal/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// VerifyEmailHandler redeems the token from a verification link
+func (handler *HandlerImpl) VerifyEmailHandler(ctx *gin.Context) {
+	tokenString := ctx.Query("token")
+	if tokenString == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "verification token is required",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	token, err := handler.todolistRepository.FindEmailVerificationToken(security.HashToken(tokenString))
+	if err != nil {
+		logrus.Errorf("failed when get verification token: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if token == nil || token.UsedAt != nil || token.Expired(time.Now()) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid or expired verification token",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	err = handler.todolistRepository.VerifyEmail(token)
+	if errors.Is(err, repository.ErrTokenAlreadyUsed) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid or expired verification token",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when verify email: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.auditAs(ctx, token.UserID, entity.AuditVerifyEmail, entity.AuditUser, token.UserID, nil, gin.H{"email": token.Email})
+
+	logrus.Info(http.StatusOK, "email verified successfully")
+	ctx.JSON(http.StatusOK, dto.EmailVerificationResponse{
+		Message: "email verified successfully",
+		Status:  http.StatusOK,
+	})
+}
+
+// ResendVerificationHandler mails a fresh verification link. The response is
+// the same whether or not the address belongs to an unverified account.
+func (handler *HandlerImpl) ResendVerificationHandler(ctx *gin.Context) {
+	request := new(dto.ResendVerificationRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	user, err := handler.todolistRepository.FindUserByEmail(request.Email)
+	if err != nil {
+		logrus.Errorf("failed when get user by email: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if user != nil && user.EmailVerifiedAt == nil {
+		if err := handler.sendVerificationEmail(ctx, user, user.Email); err != nil {
+			logrus.Errorf("failed to send verification email: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, dto.EmailVerificationResponse{
+		Message: "if the account exists and is not verified yet, a verification email has been sent",
+		Status:  http.StatusOK,
+	})
+}
+
+// sendVerificationEmail issues a token for email on behalf of user and mails
+// the verification link to that address.
+func (handler *HandlerImpl) sendVerificationEmail(ctx context.Context, user *entity.Users, email string) error {
+	tokenString, tokenHash, err := security.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	token := &entity.EmailVerificationTokens{
+		UserID:    user.UserID,
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(handler.cfg.EmailVerificationTTL),
+	}
+	if err := handler.todolistRepository.CreateEmailVerificationToken(token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/verify_email?token=%s", handler.cfg.AppBaseURL, url.QueryEscape(tokenString))
+	return handler.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nplease confirm your email address by opening the link below:\n\n%s\n\nThe link expires at %s.\n",
+			user.Username, link, token.ExpiresAt.Format(time.RFC1123)),
+	})
+}
+
+// sendAccountExistsEmail answers a registration for an address that already
+// has an account. An unverified account gets a fresh verification link instead.
+func (handler *HandlerImpl) sendAccountExistsEmail(ctx context.Context, user *entity.Users) error {
+	if user.EmailVerifiedAt == nil {
+		return handler.sendVerificationEmail(ctx, user, user.Email)
+	}
+
+	return handler.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "You already have an account",
+		Body: fmt.Sprintf("Hello %s,\n\nsomeone tried to register a new account with this email address, but it already has one. If it was you, log in instead or reset your password at:\n\n%s/password/forgot\n\nIf it wasn't you, you can ignore this email.\n",
+			user.Username, handler.cfg.AppBaseURL),
+	})
+}
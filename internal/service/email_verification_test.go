@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/mailer"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type recordingMailer struct {
+	messages []mailer.Message
+}
+
+func (mailer *recordingMailer) Send(ctx context.Context, message mailer.Message) error {
+	mailer.messages = append(mailer.messages, message)
+	return nil
+}
+
+func TestTableDrivenVerifyEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	validToken := &entity.EmailVerificationTokens{TokenID: 1, UserID: 7, Email: "alwi@mail.com", ExpiresAt: time.Now().Add(time.Hour)}
+	expiredToken := &entity.EmailVerificationTokens{TokenID: 2, UserID: 7, Email: "alwi@mail.com", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	testCase := []struct {
+		name            string
+		query           string
+		mock            func(mock *mocks.Repository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "missing token",
+			query:           "",
+			mock:            func(mock *mocks.Repository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "verification token is required",
+		},
+		{
+			name:  "unknown token",
+			query: "?token=unknown",
+			mock: func(mock *mocks.Repository) {
+				mock.On("FindEmailVerificationToken", security.HashToken("unknown")).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid or expired verification token",
+		},
+		{
+			name:  "expired token",
+			query: "?token=expired",
+			mock: func(mock *mocks.Repository) {
+				mock.On("FindEmailVerificationToken", security.HashToken("expired")).Return(expiredToken, nil)
+			},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid or expired verification token",
+		},
+		{
+			name:  "internal server error",
+			query: "?token=valid",
+			mock: func(mock *mocks.Repository) {
+				mock.On("FindEmailVerificationToken", security.HashToken("valid")).Return(validToken, nil)
+				mock.On("VerifyEmail", validToken).Return(errors.New("internal server error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "internal server error",
+		},
+		{
+			name:  "success",
+			query: "?token=valid",
+			mock: func(mock *mocks.Repository) {
+				mock.On("FindEmailVerificationToken", security.HashToken("valid")).Return(validToken, nil)
+				mock.On("VerifyEmail", validToken).Return(nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "email verified successfully",
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.GET("/verify_email", handler.VerifyEmailHandler)
+
+			req, err := http.NewRequest(http.MethodGet, "/verify_email"+test.query, nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			var result dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedMessage, result.Message)
+		})
+	}
+}
+
+func TestResendVerificationUnknownEmail(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserByEmail", "nobody@mail.com").Return(nil, nil)
+
+	mail := &recordingMailer{}
+	handler := NewHandlerImpl(mockRepo, WithMailer(mail))
+
+	router := gin.New()
+	router.POST("/resend_verification", handler.ResendVerificationHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/resend_verification", strings.NewReader(`{"email": "nobody@mail.com"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, mail.messages)
+}
+
+func TestResendVerificationSendsLink(t *testing.T) {
+	user := &entity.Users{UserID: 7, Username: "alwi", Email: "alwi@mail.com"}
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserByEmail", "alwi@mail.com").Return(user, nil)
+	mockRepo.On("CreateEmailVerificationToken", mock.MatchedBy(func(token *entity.EmailVerificationTokens) bool {
+		return token.UserID == 7 && token.Email == "alwi@mail.com" && len(token.TokenHash) == 64
+	})).Return(nil)
+
+	mail := &recordingMailer{}
+	cfg := &config.Config{AppBaseURL: "http://todo.test", EmailVerificationTTL: time.Hour}
+	handler := NewHandlerImpl(mockRepo, WithConfig(cfg), WithMailer(mail))
+
+	router := gin.New()
+	router.POST("/resend_verification", handler.ResendVerificationHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/resend_verification", strings.NewReader(`{"email": "alwi@mail.com"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.Len(t, mail.messages, 1)
+	assert.Equal(t, "alwi@mail.com", mail.messages[0].To)
+	assert.Contains(t, mail.messages[0].Body, "http://todo.test/verify_email?token=")
+}
+
+func TestLoginRejectsUnverifiedEmail(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserByEmail", "alwi@mail.com").Return(&entity.Users{UserID: 7, Email: "alwi@mail.com", Password: string(hash)}, nil)
+
+	handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{RequireEmailVerification: true}))
+
+	router := gin.New()
+	router.POST("/login", handler.LoginHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email": "alwi@mail.com", "password": "secret"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var result dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Equal(t, "email address is not verified", result.Message)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+	"todolist_gin_gorm/internal/export"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ExportTodosHandler streams the todos the user can see as a csv, json (the
+// default) or ics download. Todos are read and written in batches, so once
+// the first batch is sent a failure can only cut the download short.
+func (handler *HandlerImpl) ExportTodosHandler(ctx *gin.Context) {
+	query := new(dto.ExportQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if query.Format == "" {
+		query.Format = export.FormatJSON
+	}
+	query.ViewerID = ctx.GetInt64("user_id")
+
+	now := time.Now()
+	var writer export.Writer
+	// start sends the headers with the first batch, until then errors can
+	// still be answered with a status
+	start := func() error {
+		ctx.Header("Content-Type", export.ContentType(query.Format))
+		ctx.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{
+			"filename": fmt.Sprintf("todos-%s.%s", now.Format("20060102"), query.Format),
+		}))
+		ctx.Status(http.StatusOK)
+
+		var err error
+		writer, err = export.New(query.Format, ctx.Writer, now)
+		return err
+	}
+
+	err := handler.todolistRepository.ExportTodos(&query.TodoQuery, func(todos []entity.Todos) error {
+		if writer == nil {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		for i := range todos {
+			if err := writer.Write(&todos[i]); err != nil {
+				return err
+			}
+		}
+		if err := writer.Flush(); err != nil {
+			return err
+		}
+		ctx.Writer.Flush()
+
+		return nil
+	})
+	if err == nil && writer == nil {
+		err = start()
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+
+	if err != nil {
+		logrus.Errorf("failed when export todos: %v", err)
+		if writer != nil {
+			ctx.Abort()
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, "export todolist successfully")
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTodosStreamsBatches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("ExportTodos", mock.MatchedBy(func(query *dto.TodoQuery) bool {
+		return query.ViewerID == 7 && query.Status == entity.StatusTodo
+	}), mock.Anything).Return(func(query *dto.TodoQuery, fn func([]entity.Todos) error) error {
+		if err := fn([]entity.Todos{{Id: 1, Title: "Sholat tahajud", Status: entity.StatusTodo}}); err != nil {
+			return err
+		}
+		return fn([]entity.Todos{{Id: 2, Title: "Puasa senin", Status: entity.StatusTodo}})
+	})
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.GET("/todos/export", withUser(&entity.Users{UserID: 7}), handler.ExportTodosHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/todos/export?format=ics&status=todo", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/calendar; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Header().Get("Content-Disposition"), ".ics")
+	assert.Equal(t, 2, strings.Count(recorder.Body.String(), "BEGIN:VTODO"))
+	assert.True(t, strings.HasSuffix(recorder.Body.String(), "END:VCALENDAR\r\n"))
+}
+
+func TestTableDrivenExportTodos(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name         string
+		path         string
+		exportErr    error
+		expectedCode int
+		expectedBody string
+	}{
+		{name: "csv without todos", path: "/todos/export?format=csv", expectedCode: http.StatusOK, expectedBody: "id,title,"},
+		{name: "json by default", path: "/todos/export", expectedCode: http.StatusOK, expectedBody: "[\n]\n"},
+		{name: "unknown format", path: "/todos/export?format=xml", expectedCode: http.StatusBadRequest},
+		{name: "query fails", path: "/todos/export?format=csv", exportErr: errors.New("db down"), expectedCode: http.StatusInternalServerError, expectedBody: "internal server error"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			if tc.expectedCode != http.StatusBadRequest {
+				mockRepo.On("ExportTodos", mock.AnythingOfType("*dto.TodoQuery"), mock.Anything).Return(tc.exportErr)
+			}
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.GET("/todos/export", withUser(&entity.Users{UserID: 7}), handler.ExportTodosHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tc.path, nil))
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), tc.expectedBody)
+		})
+	}
+}
@@ -5,10 +5,15 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/mailer"
 	"todolist_gin_gorm/internal/model/dto"
 	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/oidc"
 	"todolist_gin_gorm/internal/repository"
+	"todolist_gin_gorm/internal/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -17,18 +22,34 @@ import (
 
 type HandlerImpl struct {
 	todolistRepository repository.Repository
+	cfg                *config.Config
+	mailer             mailer.Mailer
+	identityProvider   oidc.Provider
+	storage            storage.Storage
+
+	dummyHashOnce sync.Once
+	dummyHash     []byte
 }
 
-func NewHandlerImpl(repository repository.Repository) *HandlerImpl {
-	return &HandlerImpl{
+func NewHandlerImpl(repository repository.Repository, options ...Option) *HandlerImpl {
+	handler := &HandlerImpl{
 		todolistRepository: repository,
+		cfg:                &config.Config{},
+		mailer:             mailer.NewLogMailer("", ""),
+		storage:            storage.NewLocal("uploads"),
+	}
+
+	for _, option := range options {
+		option(handler)
 	}
+
+	return handler
 }
 
 // RegisterHandler handles the registration request
 func (handler *HandlerImpl) RegisterHandler(ctx *gin.Context) {
 	// Parse request body
-	var user entity.Users
+	var user dto.RegisterRequest
 	if err := ctx.ShouldBindJSON(&user); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: "failed to create user",
@@ -48,27 +69,46 @@ func (handler *HandlerImpl) RegisterHandler(ctx *gin.Context) {
 		return
 	}
 
-	// Check if user already exists
-	existingUser, err := handler.todolistRepository.FindUserByEmail(user.Email)
-	if existingUser != nil {
-		logrus.Warn("user already exist", err.Error())
-		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
-			Message: "user already exists",
-			Status:  http.StatusConflict,
+	// Hash password first, so registering a taken email costs the same as a new one
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), handler.cfg.BcryptCost)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
 		})
 		return
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+	// Check if user already exists
+	existingUser, err := handler.todolistRepository.FindUserByEmail(user.Email)
 	if err != nil {
+		logrus.Errorf("failed when get user by email: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Message: "an error occurred",
+			Message: "internal server error",
 			Status:  http.StatusInternalServerError,
 		})
 		return
 	}
 
+	if existingUser != nil {
+		if handler.cfg.EnumerationSafeRegistration {
+			// the owner of the address learns about the attempt by email, the caller doesn't
+			if err := handler.sendAccountExistsEmail(ctx, existingUser); err != nil {
+				logrus.Errorf("failed to send account exists email: %v", err)
+			}
+
+			ctx.JSON(http.StatusAccepted, registrationAcceptedResponse)
+			return
+		}
+
+		logrus.Warn("user already exist")
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "user already exists",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
 	// Create user
 	newUser := &entity.Users{
 		Username: user.Username,
@@ -84,6 +124,18 @@ func (handler *HandlerImpl) RegisterHandler(ctx *gin.Context) {
 		return
 	}
 
+	handler.auditAs(ctx, newUser.UserID, entity.AuditRegister, entity.AuditUser, newUser.UserID, nil, newUser)
+
+	// the account exists at this point, a failed email can be re-sent via /resend_verification
+	if err := handler.sendVerificationEmail(ctx, newUser, newUser.Email); err != nil {
+		logrus.Errorf("failed to send verification email: %v", err)
+	}
+
+	if handler.cfg.EnumerationSafeRegistration {
+		ctx.JSON(http.StatusAccepted, registrationAcceptedResponse)
+		return
+	}
+
 	// Return success message
 	ctx.JSON(http.StatusOK, dto.CreateUserResponse{
 		Message: "user created successfully",
@@ -91,10 +143,17 @@ func (handler *HandlerImpl) RegisterHandler(ctx *gin.Context) {
 	})
 }
 
+// registrationAcceptedResponse is the answer of an enumeration-safe
+// registration, whether or not the email was already taken
+var registrationAcceptedResponse = dto.CreateUserResponse{
+	Message: "registration received, check your email to continue",
+	Status:  http.StatusAccepted,
+}
+
 // LoginHandler handles the login request
 func (handler *HandlerImpl) LoginHandler(ctx *gin.Context) {
 	// Parse request body
-	var user entity.Users
+	var user dto.LoginRequest
 	if err := ctx.ShouldBindJSON(&user); err != nil {
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
 			Message: err.Error(),
@@ -117,13 +176,22 @@ func (handler *HandlerImpl) LoginHandler(ctx *gin.Context) {
 	// Find user by Email
 	existingUser, err := handler.todolistRepository.FindUserByEmail(user.Email)
 	if err != nil {
+		logrus.Errorf("failed when get user by email: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Message: "an error occurred",
 			Status:  http.StatusInternalServerError,
 		})
 		return
 	}
-	if existingUser == nil {
+
+	// Compare password, against a dummy hash when there is no such user so an
+	// unknown email takes as long as a wrong password
+	passwordHash := handler.dummyPasswordHash()
+	if existingUser != nil {
+		passwordHash = []byte(existingUser.Password)
+	}
+
+	if err := bcrypt.CompareHashAndPassword(passwordHash, []byte(user.Password)); err != nil || existingUser == nil {
 		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
 			Message: "Invalid credentials",
 			Status:  http.StatusUnauthorized,
@@ -131,17 +199,51 @@ func (handler *HandlerImpl) LoginHandler(ctx *gin.Context) {
 		return
 	}
 
-	// Compare password
-	if err := bcrypt.CompareHashAndPassword([]byte(existingUser.Password), []byte(user.Password)); err != nil {
-		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Message: "Invalid credentials",
-			Status:  http.StatusUnauthorized,
+	handler.completeLogin(ctx, existingUser)
+}
+
+// completeLogin answers a login whose first factor was accepted, by password
+// or by the identity provider: refused for a disabled or unverified account,
+// a challenge token when the user has 2FA, otherwise the access token.
+func (handler *HandlerImpl) completeLogin(ctx *gin.Context, user *entity.Users) {
+	if user.DisabledAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "account is disabled",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	if handler.cfg.RequireEmailVerification && user.EmailVerifiedAt == nil {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "email address is not verified",
+			Status:  http.StatusForbidden,
+		})
+		return
+	}
+
+	// with 2FA the first factor only earns a challenge token for /login/2fa
+	if user.TwoFactorEnabled() {
+		challengeToken, err := handler.issueChallengeToken(user)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "Failed to create token",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, dto.TwoFactorChallengeResponse{
+			Message:           "two-factor authentication required",
+			Status:            http.StatusOK,
+			TwoFactorRequired: true,
+			ChallengeToken:    challengeToken,
 		})
 		return
 	}
 
 	// Create JWT token
-	tokenString, err := config.CreateJWTToken(user.Email)
+	tokenString, err := handler.issueAccessToken(ctx, user)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
 			Message: "Failed to create token",
@@ -150,6 +252,8 @@ func (handler *HandlerImpl) LoginHandler(ctx *gin.Context) {
 		return
 	}
 
+	handler.auditAs(ctx, user.UserID, entity.AuditLogin, entity.AuditUser, user.UserID, nil, nil)
+
 	// Return token in response
 	ctx.JSON(http.StatusOK, dto.UserLoginResponse{
 		Message: fmt.Sprintf("hello %s! you are now logged in", user.Username),
@@ -171,17 +275,14 @@ func (handler *HandlerImpl) CreateHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
-	newList, errCreate := handler.todolistRepository.Create(todos.Title, todos.Description)
-	if errCreate != nil {
-		logrus.Error(errCreate.Error())
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
-
-			Message: "internal server error",
-			Status:  http.StatusInternalServerError,
-		})
+	newList, err := createTodo(handler.todolistRepository, ctx.GetInt64("user_id"), todos)
+	if err != nil {
+		handler.abortOperation(ctx, err)
 		return
 	}
 
+	handler.audit(ctx, entity.AuditCreate, entity.AuditTodo, newList.Id, nil, newList)
+
 	logrus.Info(http.StatusCreated, "create todolist successfully", todos)
 	ctx.JSON(http.StatusCreated, dto.TodolistResponseCreate{
 
@@ -193,7 +294,18 @@ func (handler *HandlerImpl) CreateHandlerTodolist(ctx *gin.Context) {
 }
 
 func (handler *HandlerImpl) GetAllHandlerTodolist(ctx *gin.Context) {
-	todos, err := handler.todolistRepository.GetAll()
+	query := new(dto.TodoQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	query.ViewerID = ctx.GetInt64("user_id")
+
+	todos, err := handler.todolistRepository.GetAll(query)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
 
@@ -227,7 +339,7 @@ func (handler *HandlerImpl) GetIDHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
-	todos, err := handler.todolistRepository.GetID(todoID)
+	todos, role, err := handler.todolistRepository.FindTodoForUser(ctx.GetInt64("user_id"), todoID)
 	if err != nil {
 		logrus.Errorf("failed when get todolist by id: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
@@ -248,12 +360,28 @@ func (handler *HandlerImpl) GetIDHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
+	if !handler.canAccessTodo(ctx, role, entity.ShareViewer) {
+		return
+	}
+
+	progress, err := handler.todolistRepository.SubtaskProgress(todoID)
+	if err != nil {
+		logrus.Errorf("failed when count subtasks: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
 	logrus.Info(http.StatusOK, "get todolist by id successfully")
 	ctx.AbortWithStatusJSON(http.StatusOK, dto.TodolistResponseGetID{
 
-		Message: "get todolist by id successfully",
-		Status:  http.StatusOK,
-		Data:    *todos,
+		Message:  "get todolist by id successfully",
+		Status:   http.StatusOK,
+		Data:     *todos,
+		Progress: &progress,
 	})
 
 }
@@ -283,7 +411,7 @@ func (handler *HandlerImpl) UpdateHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
-	id, err := handler.todolistRepository.GetID(todoID)
+	id, role, err := handler.todolistRepository.FindTodoForUser(ctx.GetInt64("user_id"), todoID)
 	if err != nil {
 		logrus.Errorf("failed when get todolist by id: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
@@ -304,14 +432,13 @@ func (handler *HandlerImpl) UpdateHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
-	update, err := handler.todolistRepository.Update(todoID, todos.RequestUpdateTodolist())
-	if err != nil {
-		logrus.Errorf("failed when get todolist by id: %v", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+	if !handler.canAccessTodo(ctx, role, entity.ShareEditor) {
+		return
+	}
 
-			Message: err.Error(),
-			Status:  http.StatusInternalServerError,
-		})
+	update, err := updateTodo(handler.todolistRepository, id, todos)
+	if err != nil {
+		handler.abortOperation(ctx, err)
 		return
 	}
 
@@ -324,12 +451,16 @@ func (handler *HandlerImpl) UpdateHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditTodo, todoID, id, update)
+	operationID := handler.recordUndo(ctx, entity.AuditUpdate, todoID, &entity.UndoSnapshot{Todos: []entity.Todos{*id}})
+
 	logrus.Info(http.StatusOK, "update todolist successfully")
 	ctx.JSON(http.StatusOK, dto.TodolistResponseUpdate{
 
-		Message: "update todolist successfully",
-		Status:  http.StatusOK,
-		Data:    todos,
+		Message:     "update todolist successfully",
+		Status:      http.StatusOK,
+		Data:        *update,
+		OperationID: operationID,
 	})
 
 }
@@ -347,6 +478,54 @@ func (handler *HandlerImpl) DeleteHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
+	todo, role, err := handler.todolistRepository.FindTodoForUser(ctx.GetInt64("user_id"), todoID)
+	if err != nil {
+		logrus.Errorf("failed when get todolist by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if todo == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+
+			Message: "id not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	if !handler.canAccessTodo(ctx, role, entity.ShareOwner) {
+		return
+	}
+
+	// the attachments of the todo and its subtasks are deleted with it
+	attachmentKeys, err := handler.todolistRepository.AttachmentKeys(todoID)
+	if err != nil {
+		logrus.Errorf("failed when get attachments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// taken before the delete, so it can be undone
+	snapshot, err := handler.todoSnapshot(handler.todolistRepository, todoID)
+	if err != nil {
+		logrus.Errorf("failed when snapshot todolist: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
 	IDtodo, err := handler.todolistRepository.Delete(todoID)
 	if err != nil {
 		logrus.Errorf("failed when get todolist by id: %v", err)
@@ -367,11 +546,212 @@ func (handler *HandlerImpl) DeleteHandlerTodolist(ctx *gin.Context) {
 		return
 	}
 
+	handler.removeStoredFiles(ctx, attachmentKeys)
+	handler.audit(ctx, entity.AuditDelete, entity.AuditTodo, todoID, todo, nil)
+	operationID := handler.recordUndo(ctx, entity.AuditDelete, todoID, snapshot)
+
 	logrus.Info(http.StatusOK, "delete todolist successfully")
 	ctx.JSON(http.StatusOK, dto.TodolistResponseDelete{
 
-		Message: "delete todolist successfully",
-		Status:  http.StatusOK,
+		Message:     "delete todolist successfully",
+		Status:      http.StatusOK,
+		OperationID: operationID,
+	})
+
+}
+
+// newTodo builds the todo a create request asks for, owned by userID.
+func newTodo(request *dto.CreateTodolistRequest, userID int64, now time.Time) *entity.Todos {
+	todo := &entity.Todos{
+		UserID:         userID,
+		Title:          request.Title,
+		Description:    request.Description,
+		Status:         dto.StatusOrTodo(request.Status),
+		Priority:       request.Priority,
+		DueAt:          request.DueAt,
+		Timezone:       dto.TimezoneOrUTC(request.Timezone),
+		RemindAt:       request.RemindAt,
+		RecurrenceRule: request.Recurrence,
+		Occurrence:     1,
+	}
+	if todo.Priority == "" {
+		todo.Priority = entity.PriorityMedium
+	}
+	if todo.Status == entity.StatusDone {
+		todo.CompletedAt = &now
+	}
+
+	return todo
+}
+
+// createTodo checks a create request and saves the todo it asks for, owned
+// by userID or, for editors of a shared list, by the owner of the list.
+func createTodo(repo repository.Repository, userID int64, request *dto.CreateTodolistRequest) (*entity.Todos, error) {
+	if err := dto.ValidateTimezone(request.Timezone); err != nil {
+		return nil, &operationError{status: http.StatusBadRequest, message: err.Error()}
+	}
+	if err := dto.ValidateRecurrence(request.Recurrence, request.DueAt); err != nil {
+		return nil, &operationError{status: http.StatusBadRequest, message: err.Error()}
+	}
+
+	todo := newTodo(request, userID, time.Now())
+	if request.ListID != nil {
+		list, role, err := listRole(repo, userID, *request.ListID)
+		if err != nil {
+			return nil, err
+		}
+		if list == nil {
+			return nil, &operationError{status: http.StatusNotFound, message: "list not found"}
+		}
+		if !entity.ShareRoleAllows(role, entity.ShareEditor) {
+			return nil, &operationError{status: http.StatusForbidden, message: "your role on this list does not allow it"}
+		}
+		if list.ArchivedAt != nil {
+			return nil, &operationError{status: http.StatusConflict, message: "list is archived"}
+		}
+		todo.ListID = &list.ListID
+		todo.UserID = list.UserID
+	}
+
+	if err := repo.Create(todo); err != nil {
+		return nil, err
+	}
+
+	return todo, nil
+}
+
+// updateTodo applies an update request to the current todo and returns it
+// as updated, nil when nothing was. A moved reminder fires again, closing the
+// todo closes its open subtasks with it and completing a recurring one
+// creates the next occurrence.
+func updateTodo(repo repository.Repository, current *entity.Todos, request *dto.UpdateTodolistRequest) (*entity.Todos, error) {
+	updated := request.Updated(current)
+	if err := dto.ValidateTimezone(updated.Timezone); err != nil {
+		return nil, &operationError{status: http.StatusBadRequest, message: err.Error()}
+	}
+	if err := dto.ValidateRecurrence(updated.RecurrenceRule, updated.DueAt); err != nil {
+		return nil, &operationError{status: http.StatusBadRequest, message: err.Error()}
+	}
+
+	now := time.Now()
+	updates, err := request.RequestUpdateTodolist(current, now)
+	if err != nil {
+		return nil, &operationError{status: http.StatusConflict, message: err.Error()}
+	}
+
+	if request.RemindAt.Set && !sameTime(current.RemindAt, request.RemindAt.Value) {
+		resetReminder(updates)
+	}
+
+	result, err := repo.Update(current.Id, updates)
+	if err != nil || result == nil {
+		return nil, err
+	}
+
+	if status, ok := updates["status"].(string); ok && entity.IsClosing(dto.StatusOrTodo(current.Status), status) {
+		if err := repo.CloseSubtasks(current.Id, status, now); err != nil {
+			return nil, err
+		}
+
+		if status == entity.StatusDone {
+			if _, err := createNextOccurrence(repo, updated); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return updatedTodo(current, updates), nil
+}
+
+// abortOperation answers a failed createTodo or updateTodo, unexpected
+// errors are logged and hidden.
+func (handler *HandlerImpl) abortOperation(ctx *gin.Context, err error) {
+	status, message := operationFailure(err)
+	ctx.AbortWithStatusJSON(status, dto.ErrorResponse{
+		Message: message,
+		Status:  status,
 	})
+}
+
+// resetReminder adds the updates that make a moved reminder fire again, the
+// failed attempts of the old one are forgotten.
+func resetReminder(updates map[string]interface{}) {
+	updates["reminded_at"] = nil
+	updates["reminder_attempts"] = 0
+	updates["reminder_retry_at"] = nil
+}
+
+func sameTime(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(*b)
+}
+
+// userTodo loads the todo named by the :todolistId param, which must belong
+// to the logged in user.
+func (handler *HandlerImpl) userTodo(ctx *gin.Context) (*entity.Todos, bool) {
+	return handler.todoFor(ctx, entity.ShareOwner)
+}
+
+// todoFor loads the todo named by the :todolistId param, which the logged in
+// user must own or have been given at least the required share role on.
+func (handler *HandlerImpl) todoFor(ctx *gin.Context, required string) (*entity.Todos, bool) {
+	todoID, err := strconv.ParseInt(ctx.Param("todolistId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	todo, role, err := handler.todolistRepository.FindTodoForUser(ctx.GetInt64("user_id"), todoID)
+	if err != nil {
+		logrus.Errorf("failed when get todolist by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+
+	if todo == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "todolist by id not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+
+	if !handler.canAccessTodo(ctx, role, required) {
+		return nil, false
+	}
+
+	return todo, true
+}
+
+// canAccessTodo checks the role the logged in user has on a todo, as found
+// by FindTodoForUser, allows the required one. A lower role gets a 403.
+func (handler *HandlerImpl) canAccessTodo(ctx *gin.Context, role string, required string) bool {
+	if !entity.ShareRoleAllows(role, required) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "your role on this todo does not allow it",
+			Status:  http.StatusForbidden,
+		})
+		return false
+	}
+
+	return true
+}
+
+// todoRole returns the role userID has on the todo, owner for their own
+// todos and "" when the todo isn't shared with them.
+func todoRole(repo repository.Repository, userID int64, todo *entity.Todos) (string, error) {
+	if todo.UserID == userID {
+		return entity.ShareOwner, nil
+	}
 
+	return repo.FindTodoShareRole(userID, todo)
 }
@@ -45,7 +45,7 @@ func TestGetAllTodolistsSuccess(t *testing.T) {
 	}
 
 	repoMock := mocks.NewRepository(t)
-	repoMock.On("GetAll").Return(expectedTodo, nil)
+	repoMock.On("GetAll", mock.AnythingOfType("*dto.TodoQuery")).Return(expectedTodo, nil)
 
 	handler := NewHandlerImpl(repoMock)
 
@@ -73,7 +73,7 @@ func TestGetAllTodolistsSuccess(t *testing.T) {
 
 func TestGetAllTodolistsInternalServerError(t *testing.T) {
 	repoMock := mocks.NewRepository(t)
-	repoMock.On("GetAll").Return(nil, errors.New("internal server error"))
+	repoMock.On("GetAll", mock.AnythingOfType("*dto.TodoQuery")).Return(nil, errors.New("internal server error"))
 
 	handler := NewHandlerImpl(repoMock)
 
@@ -99,7 +99,7 @@ func TestGetAllTodolistsInternalServerError(t *testing.T) {
 
 func TestGetAllTodolistsEmpty(t *testing.T) {
 	repoMock := mocks.NewRepository(t)
-	repoMock.On("GetAll").Return([]entity.Todos{}, nil)
+	repoMock.On("GetAll", mock.AnythingOfType("*dto.TodoQuery")).Return([]entity.Todos{}, nil)
 
 	handler := NewHandlerImpl(repoMock)
 
@@ -127,14 +127,18 @@ func TestGetAllTodolistsEmpty(t *testing.T) {
 
 func TestCreateTodolistSuccess(t *testing.T) {
 	repoMock := mocks.NewRepository(t)
+	expectAudit(repoMock)
 
 	newTodo := &entity.Todos{
 		Title:       "sholat",
 		Description: "sholat tahajud",
-		Status:      false,
+		Status:      entity.StatusTodo,
+		Priority:    entity.PriorityMedium,
+		Timezone:    "UTC",
+		Occurrence:  1,
 	}
 
-	repoMock.On("Create", "sholat", "sholat tahajud").Return(newTodo, nil)
+	repoMock.On("Create", newTodo).Return(nil)
 
 	handler := NewHandlerImpl(repoMock)
 
@@ -205,7 +209,7 @@ func TestCreateTodolistInternalServerError(t *testing.T) {
 	expectedErrors := errors.New("internal server error")
 	point := "/create_todolist"
 
-	repoMock.On("Create", "Sholat", "Sholat Tahajud").Return(nil, expectedErrors)
+	repoMock.On("Create", mock.AnythingOfType("*entity.Todos")).Return(expectedErrors)
 
 	reqBody := bytes.NewBufferString(`{"title": "Sholat", "description": "Sholat Tahajud"}`)
 	req, err := http.NewRequest(http.MethodPost, point, reqBody)
@@ -231,13 +235,14 @@ func TestCreateTodolistInternalServerError(t *testing.T) {
 
 func TestUpdateTodolistSuccess(t *testing.T) {
 	repoMock := mocks.NewRepository(t)
+	expectAudit(repoMock)
 
 	handler := NewHandlerImpl(repoMock)
 
 	reqBody := dto.UpdateTodolistRequest{
 		Title:       "new title",
 		Description: "new description",
-		Status:      false,
+		Status:      &dto.StatusInput{Legacy: true},
 	}
 	requestBodyBytes, _ := json.Marshal(reqBody)
 
@@ -245,10 +250,10 @@ func TestUpdateTodolistSuccess(t *testing.T) {
 		Id:          1,
 		Title:       "new title",
 		Description: "new description",
-		Status:      true,
+		Status:      entity.StatusDone,
 	}
 
-	repoMock.On("GetID", int64(1)).Return(&entity.Todos{}, nil)
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(&entity.Todos{Id: 1}, entity.ShareOwner, nil)
 	repoMock.On("Update", int64(1), mock.Anything).Return(&expextedTodo, nil)
 
 	req, _ := http.NewRequest(http.MethodPut, "/update_todolist/1", bytes.NewBuffer(requestBodyBytes))
@@ -264,6 +269,12 @@ func TestUpdateTodolistSuccess(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, result.Status)
 	assert.Equal(t, "update todolist successfully", result.Message)
+
+	// the stored todo is returned, not the request
+	var updated dto.TodolistResponseGetID
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &updated))
+	assert.Equal(t, int64(1), updated.Data.Id)
+	assert.Equal(t, "new title", updated.Data.Title)
 }
 
 func TestUpdateTodolistNotFound(t *testing.T) {
@@ -274,11 +285,11 @@ func TestUpdateTodolistNotFound(t *testing.T) {
 	reqBody := dto.UpdateTodolistRequest{
 		Title:       "new title",
 		Description: "new description",
-		Status:      false,
+		Status:      &dto.StatusInput{Legacy: true},
 	}
 	requestBodyBytes, _ := json.Marshal(reqBody)
 
-	repoMock.On("GetID", int64(1)).Return(nil, nil)
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(nil, "", nil)
 
 	req, _ := http.NewRequest(http.MethodPut, "/update_todolist/1", bytes.NewBuffer(requestBodyBytes))
 	recorder := httptest.NewRecorder()
@@ -300,13 +311,13 @@ func TestUpdateTodolistInternalServerError(t *testing.T) {
 
 	handler := NewHandlerImpl(repoMock)
 
-	repoMock.On("GetID", int64(1)).Return(&entity.Todos{}, nil)
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(&entity.Todos{Id: 1}, entity.ShareOwner, nil)
 	repoMock.On("Update", int64(1), mock.Anything).Return(nil, errors.New("internal server error"))
 
 	reqBody := dto.UpdateTodolistRequest{
 		Title:       "new title",
 		Description: "new description",
-		Status:      false,
+		Status:      &dto.StatusInput{Legacy: true},
 	}
 
 	requestBodyBytes, _ := json.Marshal(reqBody)
@@ -331,7 +342,8 @@ func TestGetTodolistByIdSuccess(t *testing.T) {
 
 	handler := NewHandlerImpl(repoMock)
 
-	repoMock.On("GetID", int64(1)).Return(&entity.Todos{Id: 1, Title: "sholat", Description: "sholat tahajud"}, nil)
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(&entity.Todos{Id: 1, Title: "sholat", Description: "sholat tahajud"}, entity.ShareOwner, nil)
+	repoMock.On("SubtaskProgress", int64(1)).Return(entity.SubtaskProgress{Done: 3, Total: 5}, nil)
 
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/find_by_id_todolist/1", nil)
@@ -351,6 +363,7 @@ func TestGetTodolistByIdSuccess(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, result.Status)
 	assert.Equal(t, "get todolist by id successfully", result.Message)
+	assert.Equal(t, &entity.SubtaskProgress{Done: 3, Total: 5}, result.Progress)
 }
 
 func TestGetTodolistByIdNotFound(t *testing.T) {
@@ -358,7 +371,7 @@ func TestGetTodolistByIdNotFound(t *testing.T) {
 
 	handler := NewHandlerImpl(repoMock)
 
-	repoMock.On("GetID", int64(1)).Return(nil, nil)
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(nil, "", nil)
 
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/find_by_id_todolist/1", nil)
@@ -385,7 +398,7 @@ func TestGetTodolistByIdInternalServerError(t *testing.T) {
 
 	handler := NewHandlerImpl(repoMock)
 
-	repoMock.On("GetID", int64(1)).Return(nil, errors.New("internal server error"))
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(nil, "", errors.New("internal server error"))
 
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodGet, "/find_by_id_todolist/1", nil)
@@ -409,9 +422,12 @@ func TestGetTodolistByIdInternalServerError(t *testing.T) {
 
 func TestDeleteTodolistSuccess(t *testing.T) {
 	repoMock := mocks.NewRepository(t)
+	expectAudit(repoMock)
 
 	handler := NewHandlerImpl(repoMock)
 
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(&entity.Todos{Id: 1}, entity.ShareOwner, nil)
+	repoMock.On("AttachmentKeys", int64(1)).Return([]string{}, nil)
 	repoMock.On("Delete", int64(1)).Return(int64(1), nil)
 
 	recorder := httptest.NewRecorder()
@@ -436,7 +452,7 @@ func TestDeleteTodolistNotFound(t *testing.T) {
 
 	handler := NewHandlerImpl(repoMock)
 
-	repoMock.On("Delete", int64(1)).Return(int64(0), nil)
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(nil, "", nil)
 
 	recorder := httptest.NewRecorder()
 	req, _ := http.NewRequest(http.MethodDelete, "/delete_todolist/1", nil)
@@ -460,6 +476,8 @@ func TestDeleteTodolistInternalServerError(t *testing.T) {
 
 	handler := NewHandlerImpl(repoMock)
 
+	repoMock.On("FindTodoForUser", int64(0), int64(1)).Return(&entity.Todos{Id: 1}, entity.ShareOwner, nil)
+	repoMock.On("AttachmentKeys", int64(1)).Return([]string{}, nil)
 	repoMock.On("Delete", int64(1)).Return(int64(0), errors.New("internal server error"))
 
 	recorder := httptest.NewRecorder()
@@ -42,8 +42,8 @@ func TestTableDrivenGetAllTodolist(t *testing.T) {
 			name:               "Success",
 			expectedStatusCode: http.StatusOK,
 			mockTodolist: []entity.Todos{
-				{Id: 1, Title: "title 1", Description: "description 1", Status: false},
-				{Id: 1, Title: "title 1", Description: "description 1", Status: false},
+				{Id: 1, Title: "title 1", Description: "description 1", Status: entity.StatusTodo},
+				{Id: 1, Title: "title 1", Description: "description 1", Status: entity.StatusTodo},
 			},
 			mockErr: nil,
 			expextedResponse: dto.TodolistResponseGetAll{
@@ -51,8 +51,8 @@ func TestTableDrivenGetAllTodolist(t *testing.T) {
 				Message: "get all todolist successfully",
 				More:    2,
 				Data: []entity.Todos{
-					{Id: 1, Title: "title 1", Description: "description 1", Status: false},
-					{Id: 1, Title: "title 1", Description: "description 1", Status: false},
+					{Id: 1, Title: "title 1", Description: "description 1", Status: entity.StatusTodo},
+					{Id: 1, Title: "title 1", Description: "description 1", Status: entity.StatusTodo},
 				},
 			},
 		},
@@ -85,7 +85,7 @@ func TestTableDrivenGetAllTodolist(t *testing.T) {
 	for _, test := range testCase {
 		t.Run(test.name, func(t *testing.T) {
 			repo := mocks.NewRepository(t)
-			repo.On("GetAll").Return(test.mockTodolist, test.mockErr)
+			repo.On("GetAll", mock.AnythingOfType("*dto.TodoQuery")).Return(test.mockTodolist, test.mockErr)
 
 			handler := NewHandlerImpl(repo)
 
@@ -131,19 +131,25 @@ func TestTableDrivenCreateTodolist(t *testing.T) {
 		{
 			name:        "success",
 			bodyRequest: `{"title": "sholat", "description": "sholat tahajud"}`,
-			mock: func(mock *mocks.Repository) {
+			mock: func(m *mocks.Repository) {
 				newTodo := &entity.Todos{
 					Title:       "sholat",
 					Description: "sholat tahajud",
-					Status:      false,
+					Status:      entity.StatusTodo,
+					Priority:    entity.PriorityMedium,
+					Timezone:    "UTC",
+					Occurrence:  1,
 				}
-				mock.On("Create", "sholat", "sholat tahajud").Return(newTodo, nil)
+				m.On("Create", newTodo).Return(nil)
 			},
 			expectedStatus: http.StatusCreated,
 			expectedData: entity.Todos{
 				Title:       "sholat",
 				Description: "sholat tahajud",
-				Status:      false,
+				Status:      entity.StatusTodo,
+				Priority:    entity.PriorityMedium,
+				Timezone:    "UTC",
+				Occurrence:  1,
 			},
 			expectedError: "",
 		},
@@ -158,9 +164,9 @@ func TestTableDrivenCreateTodolist(t *testing.T) {
 		{
 			name:        "internal server error",
 			bodyRequest: `{"title": "sholat", "description": "sholat tahajud"}`,
-			mock: func(mock *mocks.Repository) {
+			mock: func(m *mocks.Repository) {
 				expectedErr := errors.New("internal server error")
-				mock.On("Create", "sholat", "sholat tahajud").Return(nil, expectedErr)
+				m.On("Create", mock.AnythingOfType("*entity.Todos")).Return(expectedErr)
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedData:   entity.Todos{},
@@ -171,6 +177,7 @@ func TestTableDrivenCreateTodolist(t *testing.T) {
 	for _, test := range testCase {
 		t.Run(test.name, func(t *testing.T) {
 			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
 			test.mock(mockRepo)
 			handler := NewHandlerImpl(mockRepo)
 
@@ -209,6 +216,7 @@ func TestTableDrivenCreateTodolist(t *testing.T) {
 
 func TestTableDrivenDeleteTodolist(t *testing.T) {
 	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
 	handler := NewHandlerImpl(mockRepo)
 
 	gin.SetMode(gin.TestMode)
@@ -258,6 +266,8 @@ func TestTableDrivenDeleteTodolist(t *testing.T) {
 
 	for _, test := range testCase {
 		t.Run(test.name, func(t *testing.T) {
+			mockRepo.On("FindTodoForUser", int64(0), test.todoID).Return(&entity.Todos{Id: test.todoID}, entity.ShareOwner, nil)
+			mockRepo.On("AttachmentKeys", test.todoID).Return([]string{}, nil)
 			mockRepo.On("Delete", test.todoID).Return(test.isFound, test.responseError)
 
 			recorder := httptest.NewRecorder()
@@ -334,7 +344,10 @@ func TestTableDrivenGetTodolisyByID(t *testing.T) {
 			mockRepo := mocks.NewRepository(t)
 			handler := NewHandlerImpl(mockRepo)
 
-			mockRepo.On("GetID", test.inputID).Return(test.mockResult, test.mockError)
+			mockRepo.On("FindTodoForUser", int64(0), test.inputID).Return(test.mockResult, entity.ShareOwner, test.mockError)
+			if test.mockResult != nil {
+				mockRepo.On("SubtaskProgress", test.inputID).Return(entity.SubtaskProgress{}, nil)
+			}
 
 			recorder := httptest.NewRecorder()
 			req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("/find_by_id_todolist/%d", test.inputID), nil)
@@ -359,6 +372,8 @@ func TestTableDrivenUpdateTodolist(t *testing.T) {
 
 	mockRepo := mocks.NewRepository(t)
 
+	expectAudit(mockRepo)
+
 	handler := NewHandlerImpl(mockRepo)
 
 	testCase := []struct {
@@ -382,9 +397,9 @@ func TestTableDrivenUpdateTodolist(t *testing.T) {
 					Id:          1,
 					Title:       "sholat",
 					Description: "sholat tahajud",
-					Status:      false,
+					Status:      entity.StatusTodo,
 				}
-				mockRepo.On("GetID", int64(1)).Return(&entity.Todos{}, nil)
+				mockRepo.On("FindTodoForUser", int64(0), int64(1)).Return(&entity.Todos{Id: 1}, entity.ShareOwner, nil)
 				mockRepo.On("Update", int64(1), mock.Anything).Return(&expectedTodo, nil)
 			},
 			expextedStatus: http.StatusOK,
@@ -403,7 +418,7 @@ func TestTableDrivenUpdateTodolist(t *testing.T) {
 				Description: "sholat tahajud",
 			},
 			mockBehavior: func() {
-				mockRepo.On("GetID", int64(2)).Return(nil, nil)
+				mockRepo.On("FindTodoForUser", int64(0), int64(2)).Return(nil, "", nil)
 			},
 			expextedStatus: http.StatusNotFound,
 			expectedResponse: dto.ErrorResponse{
@@ -420,7 +435,7 @@ func TestTableDrivenUpdateTodolist(t *testing.T) {
 				Description: "sholat tahajud",
 			},
 			mockBehavior: func() {
-				mockRepo.On("GetID", int64(3)).Return(&entity.Todos{}, nil)
+				mockRepo.On("FindTodoForUser", int64(0), int64(3)).Return(&entity.Todos{Id: 3}, entity.ShareOwner, nil)
 				mockRepo.On("Update", int64(3), mock.Anything).Return(nil, errors.New("internal server error"))
 			},
 			expextedStatus: http.StatusInternalServerError,
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/importer"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+)
+
+// ImportTodosHandler creates todos from the "file" part of a multipart form.
+// Every row is checked with the rules of a create request first; any invalid
+// row refuses the whole import, and all valid rows are created in one
+// transaction. Rows whose external id was imported before are skipped. A dry
+// run only reports what would happen.
+func (handler *HandlerImpl) ImportTodosHandler(ctx *gin.Context) {
+	query := new(dto.ImportQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	ownerID := ctx.GetInt64("user_id")
+	var listID *int64
+	if query.ListID != 0 {
+		// editors of a shared list import todos on behalf of its owner
+		list, ok := handler.writableList(ctx, query.ListID, entity.ShareEditor)
+		if !ok {
+			return
+		}
+		ownerID = list.UserID
+		listID = &list.ListID
+	}
+
+	maxSize := handler.cfg.ImportMaxSize
+	if maxSize > 0 {
+		ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxSize+multipartOverhead)
+	}
+
+	header, err := ctx.FormFile("file")
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || (err == nil && maxSize > 0 && header.Size > maxSize) {
+		ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+			Message: fmt.Sprintf("file is larger than %d bytes", maxSize),
+			Status:  http.StatusRequestEntityTooLarge,
+		})
+		return
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "a file is required",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		logrus.Errorf("failed when open upload: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	defer file.Close()
+
+	records, err := importer.Parse(query.Format, file)
+	if err == nil && len(records) == 0 {
+		err = errors.New("the file has no todos")
+	}
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	externalIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		if record.ExternalID != "" {
+			externalIDs = append(externalIDs, record.ExternalID)
+		}
+	}
+	imported, err := handler.todolistRepository.ImportedExternalIDs(ownerID, externalIDs)
+	if err != nil {
+		logrus.Errorf("failed when get imported todos: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	seen := make(map[string]bool, len(records))
+	for _, externalID := range imported {
+		seen[externalID] = true
+	}
+
+	now := time.Now()
+	report := dto.ImportReport{DryRun: query.DryRun, Rows: make([]dto.ImportRow, len(records))}
+	todos := make(map[int]*entity.Todos, len(records))
+	for i := range records {
+		record := &records[i]
+		row := dto.ImportRow{Row: record.Row, ExternalID: record.ExternalID, Result: dto.ImportValid}
+
+		switch {
+		case record.ExternalID != "" && seen[record.ExternalID]:
+			row.Result = dto.ImportDuplicate
+			report.Duplicates++
+		default:
+			if row.Errors = validateImportRecord(record); len(row.Errors) > 0 {
+				row.Result = dto.ImportInvalid
+				report.Invalid++
+				break
+			}
+
+			todo := newTodo(&record.Todo, ownerID, now)
+			todo.ListID = listID
+			if record.ExternalID != "" {
+				seen[record.ExternalID] = true
+				todo.ExternalID = &record.ExternalID
+			}
+			todos[i] = todo
+		}
+
+		report.Rows[i] = row
+	}
+
+	if query.DryRun {
+		ctx.JSON(http.StatusOK, dto.ImportResponse{
+			Message: "import checked, nothing was imported",
+			Status:  http.StatusOK,
+			Data:    report,
+		})
+		return
+	}
+
+	if report.Invalid > 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ImportResponse{
+			Message: "the file has invalid rows, nothing was imported",
+			Status:  http.StatusBadRequest,
+			Data:    report,
+		})
+		return
+	}
+
+	err = handler.todolistRepository.Transaction(func(tx repository.Repository) error {
+		for i := range records {
+			todo, ok := todos[i]
+			if !ok {
+				continue
+			}
+
+			// another import may have created the item since it was checked
+			err := tx.Transaction(func(item repository.Repository) error {
+				return item.Create(todo)
+			})
+			if errors.Is(err, repository.ErrDuplicateTodo) {
+				delete(todos, i)
+				report.Rows[i].Result = dto.ImportDuplicate
+				report.Duplicates++
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logrus.Errorf("failed when import todos: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	for i, todo := range todos {
+		report.Rows[i].Result = dto.ImportCreated
+		report.Rows[i].Data = todo
+		report.Created++
+		handler.audit(ctx, entity.AuditCreate, entity.AuditTodo, todo.Id, nil, todo)
+	}
+
+	logrus.Info(http.StatusCreated, "import todolist successfully")
+	ctx.JSON(http.StatusCreated, dto.ImportResponse{
+		Message: "import todolist successfully",
+		Status:  http.StatusCreated,
+		Data:    report,
+	})
+}
+
+// validateImportRecord checks a record with the rules of a create request.
+func validateImportRecord(record *importer.Record) []string {
+	var messages []string
+	if record.Err != nil {
+		messages = append(messages, record.Err.Error())
+	}
+
+	var fieldErrors validator.ValidationErrors
+	if err := binding.Validator.ValidateStruct(&record.Todo); errors.As(err, &fieldErrors) {
+		requestType := reflect.TypeOf(record.Todo)
+		for _, fieldError := range fieldErrors {
+			name := fieldError.Field()
+			if field, ok := requestType.FieldByName(fieldError.StructField()); ok {
+				name = strings.Split(field.Tag.Get("json"), ",")[0]
+			}
+
+			rule := fieldError.Tag()
+			if fieldError.Param() != "" {
+				rule += "=" + fieldError.Param()
+			}
+			messages = append(messages, fmt.Sprintf("%s fails %s", name, rule))
+		}
+	} else if err != nil {
+		messages = append(messages, err.Error())
+	}
+
+	if err := dto.ValidateTimezone(record.Todo.Timezone); err != nil {
+		messages = append(messages, err.Error())
+	}
+	if err := dto.ValidateRecurrence(record.Todo.Recurrence, record.Todo.DueAt); err != nil {
+		messages = append(messages, err.Error())
+	}
+
+	return messages
+}
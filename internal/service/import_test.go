@@ -0,0 +1,158 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const importCSV = "id,title,description,status\n" +
+	"1,Sholat,subuh berjamaah,done\n" +
+	"2,Puasa,senin kamis,todo\n" +
+	"2,Puasa,senin kamis,todo\n" +
+	",Zakat,fitrah sebelum ied,\n"
+
+func serveImport(t *testing.T, handler *HandlerImpl, path string, content string) (*httptest.ResponseRecorder, dto.ImportResponse) {
+	router := gin.New()
+	router.POST("/todos/import", withUser(&entity.Users{UserID: 7}), handler.ImportTodosHandler)
+
+	body, contentType := multipartFile(t, "todos", []byte(content))
+	request := httptest.NewRequest(http.MethodPost, path, body)
+	request.Header.Set("Content-Type", contentType)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	var result dto.ImportResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	return recorder, result
+}
+
+func importResults(report dto.ImportReport) []string {
+	results := make([]string, len(report.Rows))
+	for i, row := range report.Rows {
+		results[i] = row.Result
+	}
+
+	return results
+}
+
+func TestImportTodosCommitsAndDedupes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("ImportedExternalIDs", int64(7), []string{"todolist:1", "todolist:2", "todolist:2"}).Return([]string{"todolist:1"}, nil)
+	runTransactions(mockRepo)
+	mockRepo.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+		return todo.UserID == 7 && todo.Title == "Puasa" && *todo.ExternalID == "todolist:2"
+	})).Return(nil).Once()
+	mockRepo.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+		return todo.UserID == 7 && todo.Title == "Zakat" && todo.ExternalID == nil && todo.Status == entity.StatusTodo
+	})).Return(nil).Once()
+	handler := NewHandlerImpl(mockRepo)
+
+	recorder, result := serveImport(t, handler, "/todos/import?format=csv", importCSV)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, []string{dto.ImportDuplicate, dto.ImportCreated, dto.ImportDuplicate, dto.ImportCreated}, importResults(result.Data))
+	assert.Equal(t, 2, result.Data.Created)
+	assert.Equal(t, 2, result.Data.Duplicates)
+	assert.Equal(t, 3, result.Data.Rows[1].Row)
+	assert.Equal(t, "Zakat", result.Data.Rows[3].Data.Title)
+}
+
+func TestImportTodosReportsConcurrentDuplicate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("ImportedExternalIDs", int64(7), []string{"todolist:1", "todolist:2", "todolist:2"}).Return(nil, nil)
+	runTransactions(mockRepo)
+	mockRepo.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+		return todo.ExternalID != nil && *todo.ExternalID == "todolist:1"
+	})).Return(repository.ErrDuplicateTodo).Once()
+	mockRepo.On("Create", mock.AnythingOfType("*entity.Todos")).Return(nil).Twice()
+	handler := NewHandlerImpl(mockRepo)
+
+	recorder, result := serveImport(t, handler, "/todos/import?format=csv", importCSV)
+
+	require.Equal(t, http.StatusCreated, recorder.Code)
+	assert.Equal(t, []string{dto.ImportDuplicate, dto.ImportCreated, dto.ImportDuplicate, dto.ImportCreated}, importResults(result.Data))
+	assert.Equal(t, 2, result.Data.Created)
+	assert.Equal(t, 2, result.Data.Duplicates)
+	assert.Nil(t, result.Data.Rows[0].Data)
+}
+
+func TestImportTodosDryRunReportsRows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("ImportedExternalIDs", int64(7), []string{"todoist:1", "todoist:2"}).Return([]string{}, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	recorder, result := serveImport(t, handler, "/todos/import?format=todoist&dry_run=true", `[
+		{"id":"1","content":"Buy Milk","priority":4},
+		{"id":"2","content":"x","description":"ok"}
+	]`)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, result.Data.DryRun)
+	assert.Equal(t, []string{dto.ImportValid, dto.ImportInvalid}, importResults(result.Data))
+	assert.Equal(t, []string{"title fails min=2", "description fails min=4"}, result.Data.Rows[1].Errors)
+	mockRepo.AssertNotCalled(t, "Transaction", mock.Anything)
+}
+
+func TestTableDrivenImportTodosRefused(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCases := []struct {
+		name         string
+		path         string
+		content      string
+		maxSize      int64
+		lookup       bool
+		importedErr  error
+		expectedCode int
+		expectedMsg  string
+	}{
+		{name: "invalid row", path: "/todos/import?format=csv", content: "title,description\nSholat,subuh\nPuasa,x\n", lookup: true, expectedCode: http.StatusBadRequest, expectedMsg: "the file has invalid rows, nothing was imported"},
+		{name: "unreadable file", path: "/todos/import?format=trello", content: "not json", expectedCode: http.StatusBadRequest},
+		{name: "no todos", path: "/todos/import?format=json", content: "[]", expectedCode: http.StatusBadRequest, expectedMsg: "the file has no todos"},
+		{name: "unknown format", path: "/todos/import?format=xml", content: "[]", expectedCode: http.StatusBadRequest, expectedMsg: "invalid query parameters"},
+		{name: "too large", path: "/todos/import?format=json", content: "[                    ]", maxSize: 20, expectedCode: http.StatusRequestEntityTooLarge},
+		{name: "lookup fails", path: "/todos/import?format=json", content: `[{"id":1,"title":"Sholat","description":"subuh"}]`, lookup: true, importedErr: errors.New("db down"), expectedCode: http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			if tc.lookup {
+				mockRepo.On("ImportedExternalIDs", int64(7), mock.Anything).Return(nil, tc.importedErr)
+			}
+			handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{ImportMaxSize: tc.maxSize}))
+
+			recorder, result := serveImport(t, handler, tc.path, tc.content)
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+			if tc.expectedMsg != "" {
+				assert.Equal(t, tc.expectedMsg, result.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,554 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/rank"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func (handler *HandlerImpl) CreateListHandler(ctx *gin.Context) {
+	request := new(dto.CreateListRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	list := &entity.Lists{
+		UserID: ctx.GetInt64("user_id"),
+		Name:   request.Name,
+		SortBy: request.SortBy,
+	}
+	if list.SortBy == "" {
+		list.SortBy = entity.SortPosition
+	}
+
+	if err := handler.todolistRepository.CreateList(list); err != nil {
+		logrus.Errorf("failed when create list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditCreate, entity.AuditList, list.ListID, nil, list)
+
+	logrus.Info(http.StatusCreated, "create list successfully")
+	ctx.JSON(http.StatusCreated, dto.ListResponse{
+		Message: "create list successfully",
+		Status:  http.StatusCreated,
+		Data:    *list,
+	})
+}
+
+func (handler *HandlerImpl) GetListsHandler(ctx *gin.Context) {
+	query := new(dto.ListQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	lists, err := handler.todolistRepository.GetLists(ctx.GetInt64("user_id"), query.IncludeArchived)
+	if err != nil {
+		logrus.Errorf("failed when get lists: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ListResponseGetAll{
+		Message: "get all lists successfully",
+		Status:  http.StatusOK,
+		More:    len(lists),
+		Data:    lists,
+	})
+}
+
+func (handler *HandlerImpl) GetListHandler(ctx *gin.Context) {
+	list, ok := handler.listFor(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ListResponse{
+		Message: "get list successfully",
+		Status:  http.StatusOK,
+		Data:    *list,
+	})
+}
+
+func (handler *HandlerImpl) UpdateListHandler(ctx *gin.Context) {
+	list, ok := handler.userList(ctx)
+	if !ok {
+		return
+	}
+
+	request := new(dto.UpdateListRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	updates := request.RequestUpdateList()
+	if len(updates) == 0 {
+		ctx.JSON(http.StatusOK, dto.ListResponse{
+			Message: "not change",
+			Status:  http.StatusOK,
+			Data:    *list,
+		})
+		return
+	}
+
+	handler.updateList(ctx, list, updates, "update list successfully")
+}
+
+// ArchiveListHandler hides a list from the default listing, its todos are kept
+func (handler *HandlerImpl) ArchiveListHandler(ctx *gin.Context) {
+	handler.setListArchived(ctx, true)
+}
+
+func (handler *HandlerImpl) UnarchiveListHandler(ctx *gin.Context) {
+	handler.setListArchived(ctx, false)
+}
+
+// DeleteListHandler deletes a list, its todos move to the inbox
+func (handler *HandlerImpl) DeleteListHandler(ctx *gin.Context) {
+	list, ok := handler.userList(ctx)
+	if !ok {
+		return
+	}
+
+	if list.IsInbox {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "the inbox can't be deleted",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.DeleteList(list); err != nil {
+		logrus.Errorf("failed when delete list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditDelete, entity.AuditList, list.ListID, list, nil)
+
+	logrus.Info(http.StatusOK, "delete list successfully")
+	ctx.JSON(http.StatusOK, dto.ListResponseDelete{
+		Message: "delete list successfully, its todos moved to the inbox",
+		Status:  http.StatusOK,
+	})
+}
+
+// GetListTodosHandler returns the todos of a list in the list's own order,
+// unless the query asks for another one
+func (handler *HandlerImpl) GetListTodosHandler(ctx *gin.Context) {
+	list, ok := handler.listFor(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	query := new(dto.TodoQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	query.ListID = list.ListID
+	query.ViewerID = ctx.GetInt64("user_id")
+	if query.Sort == "" {
+		query.Sort = list.SortBy
+	}
+
+	todos, err := handler.todolistRepository.GetAll(query)
+	if err != nil {
+		logrus.Errorf("failed when get todos of list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetAll{
+		Message: "get all todolist successfully",
+		Status:  http.StatusOK,
+		More:    len(todos),
+		Data:    todos,
+	})
+}
+
+// MoveTodoHandler moves one of the user's todos, with its subtasks, to another
+// of their lists
+func (handler *HandlerImpl) MoveTodoHandler(ctx *gin.Context) {
+	todo, ok := handler.userTodo(ctx)
+	if !ok {
+		return
+	}
+
+	if todo.ParentID != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "subtasks stay in their parent's list",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	request := new(dto.MoveTodoRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	list, ok := handler.writableList(ctx, request.ListID, entity.ShareOwner)
+	if !ok {
+		return
+	}
+
+	updates := map[string]interface{}{"list_id": list.ListID}
+	// the todo goes to the end of its new list
+	if !sameList(todo.ListID, &list.ListID) {
+		last, err := handler.todolistRepository.LastPosition(&list.ListID)
+		if err != nil {
+			logrus.Errorf("failed when get last position: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+
+		position, err := rank.Between(last, "")
+		if err != nil {
+			logrus.Errorf("failed when rank todo: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+		updates["position"] = position
+	}
+
+	snapshot, err := handler.moveTodo(handler.todolistRepository, todo, &list.ListID, updates)
+	if err != nil {
+		logrus.Errorf("failed when move todo: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// Update only returns the columns it changed
+	moved := updatedTodo(todo, updates)
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditTodo, todo.Id, todo, moved)
+	operationID := handler.recordUndo(ctx, entity.AuditUpdate, todo.Id, snapshot)
+
+	logrus.Info(http.StatusOK, "move todolist successfully")
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetID{
+		Message:     "move todolist successfully",
+		Status:      http.StatusOK,
+		Data:        *moved,
+		OperationID: operationID,
+	})
+}
+
+// RepositionTodoHandler drags a todo right before or after another top level
+// todo, into that todo's list. Only the moved todo gets a new position, its
+// subtasks follow it to the list.
+func (handler *HandlerImpl) RepositionTodoHandler(ctx *gin.Context) {
+	todo, ok := handler.userTodo(ctx)
+	if !ok {
+		return
+	}
+
+	request := new(dto.RepositionTodoRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil || (request.Before == nil) == (request.After == nil) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "exactly one of before or after is required",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if todo.ParentID != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "subtasks are ordered under their parent",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	anchorID, after := request.Before, false
+	if request.After != nil {
+		anchorID, after = request.After, true
+	}
+
+	if *anchorID == todo.Id {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "a todo can't be moved next to itself",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	anchor, err := handler.todolistRepository.GetID(*anchorID)
+	if err != nil {
+		logrus.Errorf("failed when get todolist by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if anchor == nil || anchor.UserID != todo.UserID || anchor.ParentID != nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "todo to move next to not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	if anchor.ListID != nil {
+		if _, ok := handler.writableList(ctx, *anchor.ListID, entity.ShareOwner); !ok {
+			return
+		}
+	}
+
+	position, err := handler.positionNextTo(anchor, after)
+	if err != nil {
+		logrus.Errorf("failed when rank todo: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	updates := map[string]interface{}{"position": position}
+	if !sameList(todo.ListID, anchor.ListID) {
+		updates["list_id"] = anchor.ListID
+	}
+
+	snapshot, err := handler.moveTodo(handler.todolistRepository, todo, anchor.ListID, updates)
+	if err != nil {
+		logrus.Errorf("failed when move todo: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditTodo, todo.Id, todo, updatedTodo(todo, updates))
+	operationID := handler.recordUndo(ctx, entity.AuditUpdate, todo.Id, snapshot)
+
+	todo.Position = position
+	todo.ListID = anchor.ListID
+
+	logrus.Info(http.StatusOK, "move todolist successfully")
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetID{
+		Message:     "move todolist successfully",
+		Status:      http.StatusOK,
+		Data:        *todo,
+		OperationID: operationID,
+	})
+}
+
+// moveTodo updates a top level todo that may change lists, its subtasks
+// follow it to listID in the same transaction. It returns what the move
+// overwrote for undo.
+func (handler *HandlerImpl) moveTodo(repo repository.Repository, todo *entity.Todos, listID *int64, updates map[string]interface{}) (*entity.UndoSnapshot, error) {
+	var snapshot *entity.UndoSnapshot
+	err := repo.Transaction(func(tx repository.Repository) error {
+		var err error
+		if snapshot, err = handler.updateSnapshot(tx, todo, true); err != nil {
+			return err
+		}
+
+		if _, err := tx.Update(todo.Id, updates); err != nil {
+			return err
+		}
+
+		return tx.MoveSubtasks(todo.Id, listID)
+	})
+
+	return snapshot, err
+}
+
+// positionNextTo returns a position between anchor and its neighbour on one side.
+func (handler *HandlerImpl) positionNextTo(anchor *entity.Todos, after bool) (string, error) {
+	neighbour, err := handler.todolistRepository.AdjacentPosition(anchor, after)
+	if err != nil {
+		return "", err
+	}
+
+	if after {
+		return rank.Between(anchor.Position, neighbour)
+	}
+
+	return rank.Between(neighbour, anchor.Position)
+}
+
+func sameList(a *int64, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+func (handler *HandlerImpl) setListArchived(ctx *gin.Context, archived bool) {
+	list, ok := handler.userList(ctx)
+	if !ok {
+		return
+	}
+
+	if list.IsInbox {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "the inbox can't be archived",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	if archived {
+		handler.updateList(ctx, list, map[string]interface{}{"archived_at": time.Now()}, "archive list successfully")
+		return
+	}
+
+	handler.updateList(ctx, list, map[string]interface{}{"archived_at": nil}, "unarchive list successfully")
+}
+
+func (handler *HandlerImpl) updateList(ctx *gin.Context, list *entity.Lists, updates map[string]interface{}, message string) {
+	updated, err := handler.todolistRepository.UpdateList(list.ListID, updates)
+	if err != nil {
+		logrus.Errorf("failed when update list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditList, list.ListID, list, updated)
+
+	logrus.Info(http.StatusOK, message)
+	ctx.JSON(http.StatusOK, dto.ListResponse{
+		Message: message,
+		Status:  http.StatusOK,
+		Data:    *updated,
+	})
+}
+
+// userList loads the list named by the :listId param, which must belong to
+// the logged in user.
+func (handler *HandlerImpl) userList(ctx *gin.Context) (*entity.Lists, bool) {
+	return handler.listFor(ctx, entity.ShareOwner)
+}
+
+// listFor loads the list named by the :listId param, which the logged in user
+// must own or have been given at least the required share role on.
+func (handler *HandlerImpl) listFor(ctx *gin.Context, required string) (*entity.Lists, bool) {
+	listID, err := strconv.ParseInt(ctx.Param("listId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	return handler.findList(ctx, listID, required)
+}
+
+// writableList loads a list todos are being put in, archived lists are refused.
+func (handler *HandlerImpl) writableList(ctx *gin.Context, listID int64, required string) (*entity.Lists, bool) {
+	list, ok := handler.findList(ctx, listID, required)
+	if !ok {
+		return nil, false
+	}
+
+	if list.ArchivedAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "list is archived",
+			Status:  http.StatusConflict,
+		})
+		return nil, false
+	}
+
+	return list, true
+}
+
+// findList loads a list of the logged in user, or one shared with them with
+// at least the required role.
+func (handler *HandlerImpl) findList(ctx *gin.Context, listID int64, required string) (*entity.Lists, bool) {
+	list, role, err := listRole(handler.todolistRepository, ctx.GetInt64("user_id"), listID)
+	if err != nil {
+		logrus.Errorf("failed when get list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+
+	if list == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "list not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+
+	if !entity.ShareRoleAllows(role, required) {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, dto.ErrorResponse{
+			Message: "your role on this list does not allow it",
+			Status:  http.StatusForbidden,
+		})
+		return nil, false
+	}
+
+	return list, true
+}
+
+// listRole loads a list with the role the user has on it, owner for their
+// own lists. It returns a nil list when the user can't see it.
+func listRole(repo repository.Repository, userID int64, listID int64) (*entity.Lists, string, error) {
+	list, err := repo.FindList(userID, listID)
+	if err != nil || list != nil {
+		return list, entity.ShareOwner, err
+	}
+
+	return repo.FindSharedList(userID, listID)
+}
@@ -0,0 +1,299 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDrivenDeleteList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+
+	testCase := []struct {
+		name           string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name: "todos move to the inbox",
+			mock: func(m *mocks.Repository) {
+				list := &entity.Lists{ListID: 3, UserID: 7, Name: "work"}
+				m.On("FindList", int64(7), int64(3)).Return(list, nil)
+				m.On("DeleteList", list).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "inbox can't be deleted",
+			mock: func(m *mocks.Repository) {
+				m.On("FindList", int64(7), int64(3)).Return(&entity.Lists{ListID: 3, UserID: 7, IsInbox: true}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "list of another user",
+			mock: func(m *mocks.Repository) {
+				m.On("FindList", int64(7), int64(3)).Return(nil, nil)
+				m.On("FindSharedList", int64(7), int64(3)).Return(nil, "", nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.DELETE("/lists/:listId", withUser(user), handler.DeleteListHandler)
+
+			req, err := http.NewRequest(http.MethodDelete, "/lists/3", nil)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestArchiveInboxIsRefused(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindList", int64(7), int64(3)).Return(&entity.Lists{ListID: 3, UserID: 7, IsInbox: true}, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/lists/:listId/archive", withUser(&entity.Users{UserID: 7}), handler.ArchiveListHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/lists/3/archive", nil))
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestGetListTodosUsesListOrder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindList", int64(7), int64(3)).Return(&entity.Lists{ListID: 3, UserID: 7, SortBy: entity.SortDueAt}, nil)
+	mockRepo.On("GetAll", mock.MatchedBy(func(query *dto.TodoQuery) bool {
+		return query.ListID == 3 && query.Sort == entity.SortDueAt
+	})).Return([]entity.Todos{}, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.GET("/lists/:listId/todos", withUser(&entity.Users{UserID: 7}), handler.GetListTodosHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/lists/3/todos", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestTableDrivenMoveTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+	archivedAt := time.Now()
+
+	testCase := []struct {
+		name           string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name: "move to another list",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				m.On("FindList", int64(7), int64(3)).Return(&entity.Lists{ListID: 3, UserID: 7}, nil)
+				m.On("LastPosition", mock.MatchedBy(func(listID *int64) bool { return *listID == 3 })).Return("i0000001", nil)
+				runTransactions(m)
+				m.On("Update", int64(1), map[string]interface{}{"list_id": int64(3), "position": "i0000002"}).Return(&entity.Todos{Id: 1, UserID: 7}, nil)
+				m.On("MoveSubtasks", int64(1), mock.MatchedBy(func(listID *int64) bool { return *listID == 3 })).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "subtask on its own",
+			mock: func(m *mocks.Repository) {
+				parentID := int64(2)
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7, ParentID: &parentID}, entity.ShareOwner, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "archived list",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				m.On("FindList", int64(7), int64(3)).Return(&entity.Lists{ListID: 3, UserID: 7, ArchivedAt: &archivedAt}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "todo of another user",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(nil, "", nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.PUT("/todos/:todolistId/list", withUser(user), handler.MoveTodoHandler)
+
+			req, err := http.NewRequest(http.MethodPut, "/todos/1/list", strings.NewReader(`{"list_id": 3}`))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestMoveTodoReturnsWholeTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	inbox, work := int64(2), int64(3)
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7, ListID: &inbox, Title: "sholat", Description: "sholat subuh", Position: "i0000001"}, entity.ShareOwner, nil)
+	mockRepo.On("FindList", int64(7), work).Return(&entity.Lists{ListID: work, UserID: 7}, nil)
+	mockRepo.On("LastPosition", &work).Return("", nil)
+	// like the repository, Update only returns the columns it changed
+	runTransactions(mockRepo)
+	mockRepo.On("Update", int64(1), mock.Anything).Return(&entity.Todos{ListID: &work, Position: "i0000001"}, nil)
+	mockRepo.On("MoveSubtasks", int64(1), &work).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.PUT("/todos/:todolistId/list", withUser(&entity.Users{UserID: 7}), handler.MoveTodoHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/todos/1/list", strings.NewReader(`{"list_id": 3}`)))
+
+	var result dto.TodolistResponseGetID
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, int64(1), result.Data.Id)
+	assert.Equal(t, "sholat", result.Data.Title)
+	assert.Equal(t, work, *result.Data.ListID)
+}
+
+func TestTableDrivenRepositionTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+	inbox, work := int64(3), int64(4)
+	archivedAt := time.Now()
+
+	testCase := []struct {
+		name           string
+		bodyRequest    string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:        "before its neighbour",
+			bodyRequest: `{"before": 2}`,
+			mock: func(m *mocks.Repository) {
+				anchor := &entity.Todos{Id: 2, UserID: 7, ListID: &inbox, Position: "b"}
+				m.On("GetID", int64(2)).Return(anchor, nil)
+				m.On("FindList", int64(7), inbox).Return(&entity.Lists{ListID: inbox, UserID: 7}, nil)
+				m.On("AdjacentPosition", anchor, false).Return("a", nil)
+				runTransactions(m)
+				m.On("Update", int64(1), map[string]interface{}{"position": "ai"}).Return(&entity.Todos{}, nil)
+				m.On("MoveSubtasks", int64(1), &inbox).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "after the last todo of another list",
+			bodyRequest: `{"after": 2}`,
+			mock: func(m *mocks.Repository) {
+				anchor := &entity.Todos{Id: 2, UserID: 7, ListID: &work, Position: "i0000001"}
+				m.On("GetID", int64(2)).Return(anchor, nil)
+				m.On("FindList", int64(7), work).Return(&entity.Lists{ListID: work, UserID: 7}, nil)
+				m.On("AdjacentPosition", anchor, true).Return("", nil)
+				runTransactions(m)
+				m.On("Update", int64(1), map[string]interface{}{"position": "i0000002", "list_id": &work}).Return(&entity.Todos{}, nil)
+				m.On("MoveSubtasks", int64(1), &work).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "next to a todo of an archived list",
+			bodyRequest: `{"after": 2}`,
+			mock: func(m *mocks.Repository) {
+				m.On("GetID", int64(2)).Return(&entity.Todos{Id: 2, UserID: 7, ListID: &work, Position: "i0000001"}, nil)
+				m.On("FindList", int64(7), work).Return(&entity.Lists{ListID: work, UserID: 7, ArchivedAt: &archivedAt}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "both sides",
+			bodyRequest:    `{"before": 2, "after": 3}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "next to a todo of another user",
+			bodyRequest: `{"before": 2}`,
+			mock: func(m *mocks.Repository) {
+				m.On("GetID", int64(2)).Return(&entity.Todos{Id: 2, UserID: 8, Position: "b"}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7, ListID: &inbox, Position: "c"}, entity.ShareOwner, nil)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/todos/:todolistId/move", withUser(user), handler.RepositionTodoHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/todos/1/move", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
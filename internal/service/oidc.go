@@ -0,0 +1,211 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/oidc"
+	"todolist_gin_gorm/internal/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	oidcStateCookie = "oidc_state"
+	oidcStateTTL    = 10 * time.Minute
+)
+
+var errIdentityConflict = errors.New("an account with this email already exists")
+
+// OIDCLoginHandler redirects the browser to the identity provider
+func (handler *HandlerImpl) OIDCLoginHandler(ctx *gin.Context) {
+	if handler.identityProvider == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "oidc login is not configured",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	state, err := oidc.NewLoginState(oidcStateTTL)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	cookie, err := state.Encode([]byte(handler.cfg.OIDCStateSecret))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.SetSameSite(http.SameSiteLaxMode)
+	ctx.SetCookie(oidcStateCookie, cookie, int(oidcStateTTL.Seconds()), "/auth/oidc", "", strings.HasPrefix(handler.cfg.AppBaseURL, "https://"), true)
+	ctx.Redirect(http.StatusFound, handler.identityProvider.AuthCodeURL(state.State, state.Nonce, state.CodeChallenge()))
+}
+
+// OIDCCallbackHandler finishes the login started by OIDCLoginHandler and
+// answers like LoginHandler does, with the app's own JWT or a 2FA challenge
+func (handler *HandlerImpl) OIDCCallbackHandler(ctx *gin.Context) {
+	if handler.identityProvider == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "oidc login is not configured",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	if providerError := ctx.Query("error"); providerError != "" {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: fmt.Sprintf("login was not completed: %s", providerError),
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	cookie, _ := ctx.Cookie(oidcStateCookie)
+	ctx.SetCookie(oidcStateCookie, "", -1, "/auth/oidc", "", strings.HasPrefix(handler.cfg.AppBaseURL, "https://"), true)
+
+	state, err := oidc.DecodeLoginState([]byte(handler.cfg.OIDCStateSecret), cookie)
+	if err != nil || ctx.Query("state") != state.State || ctx.Query("code") == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid or expired login state",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	identity, err := handler.identityProvider.Exchange(ctx, ctx.Query("code"), state.CodeVerifier, state.Nonce)
+	if err != nil {
+		logrus.Errorf("failed when exchange oidc code: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "Invalid credentials",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	user, err := handler.userForIdentity(ctx, identity)
+	if errors.Is(err, errIdentityConflict) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "an account with this email already exists, log in with your password first",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when link oidc identity: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.completeLogin(ctx, user)
+}
+
+// userForIdentity returns the user linked to identity. An unknown identity is
+// linked to the account with the same email when the provider has verified
+// that address, otherwise a new account is created.
+func (handler *HandlerImpl) userForIdentity(ctx *gin.Context, identity *oidc.Identity) (*entity.Users, error) {
+	providerName := handler.identityProvider.Name()
+
+	linked, err := handler.todolistRepository.FindUserIdentity(providerName, identity.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	if linked != nil {
+		user, err := handler.todolistRepository.FindUserByID(linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, fmt.Errorf("identity %d points to a missing user", linked.IdentityID)
+		}
+		return user, nil
+	}
+
+	if identity.Email == "" {
+		return nil, errors.New("identity provider did not return an email address")
+	}
+
+	link := &entity.UserIdentities{
+		Provider: providerName,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}
+
+	existingUser, err := handler.todolistRepository.FindUserByEmail(identity.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	if existingUser != nil {
+		if !identity.EmailVerified {
+			return nil, errIdentityConflict
+		}
+
+		link.UserID = existingUser.UserID
+		if err := handler.todolistRepository.CreateUserIdentity(link); err != nil {
+			return nil, err
+		}
+		return existingUser, nil
+	}
+
+	// the account can only be used through the identity provider until the
+	// user sets a password with /password/forgot
+	unusablePassword, _, err := security.GenerateToken()
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(unusablePassword), handler.cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &entity.Users{
+		Username: usernameForIdentity(identity),
+		Email:    identity.Email,
+		Password: string(hashedPassword),
+		Role:     entity.RoleUser,
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+
+	if err := handler.todolistRepository.CreateUserWithIdentity(user, link); err != nil {
+		return nil, err
+	}
+
+	handler.auditAs(ctx, user.UserID, entity.AuditRegister, entity.AuditUser, user.UserID, nil, user)
+
+	return user, nil
+}
+
+func usernameForIdentity(identity *oidc.Identity) string {
+	username := identity.Name
+	if username == "" {
+		username, _, _ = strings.Cut(identity.Email, "@")
+	}
+
+	if runes := []rune(username); len(runes) > 55 {
+		username = string(runes[:55])
+	}
+
+	return username
+}
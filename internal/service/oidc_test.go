@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/oidc"
+	"todolist_gin_gorm/internal/oidc/oidctest"
+	"todolist_gin_gorm/mocks"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// runOIDCLogin drives the browser side of the flow: login redirect, provider
+// redirect, callback. It returns the callback response. configure adjusts
+// the handler's config.
+func runOIDCLogin(t *testing.T, mockRepo *mocks.Repository, identity oidc.Identity, configure ...func(cfg *config.Config)) *httptest.ResponseRecorder {
+	server := oidctest.NewServer("todolist")
+	t.Cleanup(server.Close)
+	server.Identity = identity
+
+	provider, err := oidc.Discover(context.Background(), oidc.Config{
+		Name:        "sso",
+		Issuer:      server.URL,
+		ClientID:    "todolist",
+		RedirectURL: "http://localhost:1234/auth/oidc/callback",
+	}, nil)
+	require.NoError(t, err)
+
+	cfg := &config.Config{OIDCStateSecret: "state-secret", BcryptCost: bcrypt.MinCost}
+	for _, apply := range configure {
+		apply(cfg)
+	}
+	handler := NewHandlerImpl(mockRepo, WithConfig(cfg), WithIdentityProvider(provider))
+
+	router := gin.New()
+	router.GET("/auth/oidc/login", handler.OIDCLoginHandler)
+	router.GET("/auth/oidc/callback", handler.OIDCCallbackHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auth/oidc/login", nil))
+	require.Equal(t, http.StatusFound, recorder.Code)
+	cookies := recorder.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	response, err := client.Get(recorder.Header().Get("Location"))
+	require.NoError(t, err)
+	response.Body.Close()
+
+	callback, err := url.Parse(response.Header.Get("Location"))
+	require.NoError(t, err)
+
+	request := httptest.NewRequest(http.MethodGet, callback.RequestURI(), nil)
+	request.AddCookie(cookies[0])
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestOIDCLoginCreatesLinkedUser(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindUserIdentity", "sso", "sso|42").Return(nil, nil)
+	mockRepo.On("FindUserByEmail", "alwi@corp.com").Return(nil, nil)
+	mockRepo.On("CreateUserWithIdentity", mock.MatchedBy(func(user *entity.Users) bool {
+		return user.Email == "alwi@corp.com" && user.Username == "Alwi" && user.EmailVerifiedAt != nil
+	}), mock.MatchedBy(func(identity *entity.UserIdentities) bool {
+		return identity.Provider == "sso" && identity.Subject == "sso|42"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.Users).UserID = 9
+	}).Return(nil)
+	mockRepo.On("CreateSession", mock.AnythingOfType("*entity.Sessions")).Return(nil)
+
+	recorder := runOIDCLogin(t, mockRepo, oidc.Identity{Subject: "sso|42", Email: "alwi@corp.com", EmailVerified: true, Name: "Alwi"})
+
+	var result dto.UserLoginResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	claims, err := config.ParseJWTToken(result.Token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), claims.UserID)
+	assert.Equal(t, "alwi@corp.com", claims.Email)
+}
+
+func TestOIDCLoginUsesExistingLink(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindUserIdentity", "sso", "sso|42").Return(&entity.UserIdentities{IdentityID: 1, UserID: 7}, nil)
+	mockRepo.On("FindUserByID", int64(7)).Return(&entity.Users{UserID: 7, Email: "alwi@mail.com"}, nil)
+	mockRepo.On("CreateSession", mock.AnythingOfType("*entity.Sessions")).Return(nil)
+
+	recorder := runOIDCLogin(t, mockRepo, oidc.Identity{Subject: "sso|42", Email: "alwi@corp.com"})
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestOIDCLoginAsksForSecondFactor(t *testing.T) {
+	enabledAt := time.Now()
+	secret := "JBSWY3DPEHPK3PXP"
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserIdentity", "sso", "sso|42").Return(&entity.UserIdentities{IdentityID: 1, UserID: 7}, nil)
+	mockRepo.On("FindUserByID", int64(7)).Return(&entity.Users{UserID: 7, Email: "alwi@mail.com", TOTPSecret: &secret, TwoFactorEnabledAt: &enabledAt}, nil)
+	mockRepo.On("CreateTwoFactorChallenge", mock.AnythingOfType("*entity.TwoFactorChallenges")).Return(nil)
+
+	recorder := runOIDCLogin(t, mockRepo, oidc.Identity{Subject: "sso|42", Email: "alwi@corp.com"})
+
+	var result dto.TwoFactorChallengeResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	assert.True(t, result.TwoFactorRequired)
+	_, err := config.ParseChallengeToken(result.ChallengeToken)
+	assert.NoError(t, err)
+}
+
+func TestOIDCLoginRequiresVerifiedEmail(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserIdentity", "sso", "sso|42").Return(&entity.UserIdentities{IdentityID: 1, UserID: 7}, nil)
+	mockRepo.On("FindUserByID", int64(7)).Return(&entity.Users{UserID: 7, Email: "alwi@mail.com"}, nil)
+
+	recorder := runOIDCLogin(t, mockRepo, oidc.Identity{Subject: "sso|42", Email: "alwi@corp.com"}, func(cfg *config.Config) {
+		cfg.RequireEmailVerification = true
+	})
+
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestUsernameForIdentityTruncatesRunes(t *testing.T) {
+	username := usernameForIdentity(&oidc.Identity{Name: strings.Repeat("é", 60)})
+
+	assert.True(t, utf8.ValidString(username))
+	assert.Equal(t, 55, utf8.RuneCountInString(username))
+}
+
+func TestOIDCLoginRefusesUnverifiedEmailTakeover(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserIdentity", "sso", "sso|43").Return(nil, nil)
+	mockRepo.On("FindUserByEmail", "alwi@mail.com").Return(&entity.Users{UserID: 7, Email: "alwi@mail.com"}, nil)
+
+	recorder := runOIDCLogin(t, mockRepo, oidc.Identity{Subject: "sso|43", Email: "alwi@mail.com", EmailVerified: false})
+
+	assert.Equal(t, http.StatusConflict, recorder.Code)
+}
+
+func TestOIDCCallbackRejectsMissingState(t *testing.T) {
+	server := oidctest.NewServer("todolist")
+	defer server.Close()
+
+	provider, err := oidc.Discover(context.Background(), oidc.Config{Issuer: server.URL, ClientID: "todolist"}, nil)
+	require.NoError(t, err)
+
+	handler := NewHandlerImpl(mocks.NewRepository(t), WithConfig(&config.Config{OIDCStateSecret: "state-secret"}), WithIdentityProvider(provider))
+
+	router := gin.New()
+	router.GET("/auth/oidc/callback", handler.OIDCCallbackHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/auth/oidc/callback?code=abc&state=forged", nil))
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
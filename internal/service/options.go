@@ -0,0 +1,35 @@
+package service
+
+import (
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/mailer"
+	"todolist_gin_gorm/internal/oidc"
+	"todolist_gin_gorm/internal/storage"
+)
+
+// Option configures optional collaborators of HandlerImpl.
+type Option func(handler *HandlerImpl)
+
+func WithConfig(cfg *config.Config) Option {
+	return func(handler *HandlerImpl) {
+		handler.cfg = cfg
+	}
+}
+
+func WithMailer(mailer mailer.Mailer) Option {
+	return func(handler *HandlerImpl) {
+		handler.mailer = mailer
+	}
+}
+
+func WithIdentityProvider(provider oidc.Provider) Option {
+	return func(handler *HandlerImpl) {
+		handler.identityProvider = provider
+	}
+}
+
+func WithStorage(storage storage.Storage) Option {
+	return func(handler *HandlerImpl) {
+		handler.storage = storage
+	}
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+	"todolist_gin_gorm/internal/mailer"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+	"todolist_gin_gorm/internal/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ForgotPasswordHandler mails a password reset link. The response does not
+// reveal whether the address is registered.
+func (handler *HandlerImpl) ForgotPasswordHandler(ctx *gin.Context) {
+	request := new(dto.ForgotPasswordRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	user, err := handler.todolistRepository.FindUserByEmail(request.Email)
+	if err != nil {
+		logrus.Errorf("failed when get user by email: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if user != nil {
+		if err := handler.sendPasswordResetEmail(ctx, user); err != nil {
+			logrus.Errorf("failed to send password reset email: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, dto.PasswordResponse{
+		Message: "if the account exists, a password reset email has been sent",
+		Status:  http.StatusOK,
+	})
+}
+
+// ResetPasswordHandler sets a new password using a token from ForgotPasswordHandler
+func (handler *HandlerImpl) ResetPasswordHandler(ctx *gin.Context) {
+	request := new(dto.ResetPasswordRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	token, err := handler.todolistRepository.FindPasswordResetToken(security.HashToken(request.Token))
+	if err != nil {
+		logrus.Errorf("failed when get password reset token: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if token == nil || token.UsedAt != nil || token.Expired(time.Now()) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid or expired password reset token",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.Password), handler.cfg.BcryptCost)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	err = handler.todolistRepository.ResetPassword(token, string(hashedPassword))
+	if errors.Is(err, repository.ErrTokenAlreadyUsed) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid or expired password reset token",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when reset password: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.auditAs(ctx, token.UserID, entity.AuditResetPassword, entity.AuditUser, token.UserID, nil, nil)
+
+	logrus.Info(http.StatusOK, "password reset successfully")
+	ctx.JSON(http.StatusOK, dto.PasswordResponse{
+		Message: "password reset successfully",
+		Status:  http.StatusOK,
+	})
+}
+
+// ChangePasswordHandler changes the password of the logged in user. Every
+// other token is revoked, the response carries a fresh one.
+func (handler *HandlerImpl) ChangePasswordHandler(ctx *gin.Context) {
+	user, ok := currentUser(ctx)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "unauthorized",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	request := new(dto.ChangePasswordRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.CurrentPassword)); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "current password is incorrect",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(request.NewPassword), handler.cfg.BcryptCost)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	updatedUser, err := handler.todolistRepository.UpdatePassword(user.UserID, string(hashedPassword))
+	if err != nil {
+		logrus.Errorf("failed when update password: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	tokenString, err := handler.issueAccessToken(ctx, updatedUser)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "Failed to create token",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditChangePassword, entity.AuditUser, user.UserID, nil, nil)
+
+	logrus.Info(http.StatusOK, "password changed successfully")
+	ctx.JSON(http.StatusOK, dto.ChangePasswordResponse{
+		Message: "password changed successfully",
+		Status:  http.StatusOK,
+		Token:   tokenString,
+	})
+}
+
+// dummyPasswordHash is compared against when a login names an unknown email.
+// It is created with the configured cost so it takes as long as a real hash.
+func (handler *HandlerImpl) dummyPasswordHash() []byte {
+	handler.dummyHashOnce.Do(func() {
+		hash, err := bcrypt.GenerateFromPassword([]byte("dummy-password"), handler.cfg.BcryptCost)
+		if err != nil {
+			logrus.Errorf("failed to create dummy password hash: %v", err)
+		}
+		handler.dummyHash = hash
+	})
+
+	return handler.dummyHash
+}
+
+func (handler *HandlerImpl) sendPasswordResetEmail(ctx context.Context, user *entity.Users) error {
+	tokenString, tokenHash, err := security.GenerateToken()
+	if err != nil {
+		return err
+	}
+
+	token := &entity.PasswordResetTokens{
+		UserID:    user.UserID,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(handler.cfg.PasswordResetTTL),
+	}
+	if err := handler.todolistRepository.CreatePasswordResetToken(token); err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/password/reset?token=%s", handler.cfg.AppBaseURL, url.QueryEscape(tokenString))
+	return handler.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hello %s,\n\nsomeone asked to reset the password of your account. If it was you, open the link below:\n\n%s\n\nThe link can be used once and expires at %s. If you did not ask for it you can ignore this email.\n",
+			user.Username, link, token.ExpiresAt.Format(time.RFC1123)),
+	})
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func withUser(user *entity.Users) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set("email", user.Email)
+		ctx.Set("user_id", user.UserID)
+		ctx.Set("user", user)
+	}
+}
+
+func TestTableDrivenChangePassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("old-password"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com", Password: string(hash)}
+
+	testCase := []struct {
+		name            string
+		bodyRequest     string
+		mock            func(mock *mocks.Repository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:            "wrong current password",
+			bodyRequest:     `{"current_password": "guess", "new_password": "new-password"}`,
+			mock:            func(mock *mocks.Repository) {},
+			expectedStatus:  http.StatusUnauthorized,
+			expectedMessage: "current password is incorrect",
+		},
+		{
+			name:            "new password too short",
+			bodyRequest:     `{"current_password": "old-password", "new_password": "short"}`,
+			mock:            func(mock *mocks.Repository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "invalid input validation",
+		},
+		{
+			name:        "success",
+			bodyRequest: `{"current_password": "old-password", "new_password": "new-password"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("UpdatePassword", int64(7), mock.MatchedBy(func(hash string) bool {
+					return bcrypt.CompareHashAndPassword([]byte(hash), []byte("new-password")) == nil
+				})).Return(&entity.Users{UserID: 7, Email: "alwi@mail.com", TokenVersion: 1}, nil)
+				m.On("CreateSession", mock.MatchedBy(func(session *entity.Sessions) bool {
+					return session.UserID == 7 && session.TokenVersion == 1
+				})).Return(nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "password changed successfully",
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{BcryptCost: bcrypt.MinCost}))
+
+			router := gin.New()
+			router.PUT("/me/password", withUser(user), handler.ChangePasswordHandler)
+
+			req, err := http.NewRequest(http.MethodPut, "/me/password", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			var result dto.ChangePasswordResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedMessage, result.Message)
+
+			if test.expectedStatus == http.StatusOK {
+				claims, err := config.ParseJWTToken(result.Token)
+				require.NoError(t, err)
+				assert.Equal(t, 1, claims.TokenVersion)
+			}
+		})
+	}
+}
+
+func TestResetPasswordExpiredToken(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindPasswordResetToken", security.HashToken("expired")).
+		Return(&entity.PasswordResetTokens{TokenID: 1, UserID: 7, ExpiresAt: time.Now().Add(-time.Minute)}, nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/password/reset", handler.ResetPasswordHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/password/reset", strings.NewReader(`{"token": "expired", "password": "new-password"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestResetPasswordSuccess(t *testing.T) {
+	token := &entity.PasswordResetTokens{TokenID: 1, UserID: 7, ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindPasswordResetToken", security.HashToken("valid")).Return(token, nil)
+	mockRepo.On("ResetPassword", token, mock.AnythingOfType("string")).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{BcryptCost: bcrypt.MinCost}))
+
+	router := gin.New()
+	router.POST("/password/reset", handler.ResetPasswordHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/password/reset", strings.NewReader(`{"token": "valid", "password": "new-password"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestForgotPasswordSendsLink(t *testing.T) {
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserByEmail", "alwi@mail.com").Return(&entity.Users{UserID: 7, Email: "alwi@mail.com"}, nil)
+	mockRepo.On("CreatePasswordResetToken", mock.AnythingOfType("*entity.PasswordResetTokens")).Return(nil)
+
+	mail := &recordingMailer{}
+	handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{AppBaseURL: "http://todo.test", PasswordResetTTL: time.Hour}), WithMailer(mail))
+
+	router := gin.New()
+	router.POST("/password/forgot", handler.ForgotPasswordHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/password/forgot", strings.NewReader(`{"email": "alwi@mail.com"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.Len(t, mail.messages, 1)
+	assert.Contains(t, mail.messages[0].Body, "http://todo.test/password/reset?token=")
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// GetProfileHandler returns the logged in user
+func (handler *HandlerImpl) GetProfileHandler(ctx *gin.Context) {
+	user, ok := currentUser(ctx)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "unauthorized",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ProfileResponse{
+		Message: "get profile successfully",
+		Status:  http.StatusOK,
+		Data:    dto.NewUserResponse(user),
+	})
+}
+
+// UpdateProfileHandler changes the username right away. A new email address
+// only replaces the current one after it has been verified.
+func (handler *HandlerImpl) UpdateProfileHandler(ctx *gin.Context) {
+	user, ok := currentUser(ctx)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "unauthorized",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	request := new(dto.UpdateProfileRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	before := dto.NewUserResponse(user)
+	pendingEmail := ""
+	if request.Email != nil && !strings.EqualFold(*request.Email, user.Email) {
+		existingUser, err := handler.todolistRepository.FindUserByEmail(*request.Email)
+		if err != nil {
+			logrus.Errorf("failed when get user by email: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+
+		if existingUser != nil {
+			ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Message: "email already in use",
+				Status:  http.StatusConflict,
+			})
+			return
+		}
+
+		pendingEmail = *request.Email
+	}
+
+	if request.Username != nil && *request.Username != user.Username {
+		updatedUser, err := handler.todolistRepository.UpdateUser(user.UserID, map[string]interface{}{
+			"username": *request.Username,
+		})
+		if err != nil {
+			logrus.Errorf("failed when update user: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+
+		if updatedUser != nil {
+			user = updatedUser
+		}
+	}
+
+	if pendingEmail != "" {
+		if err := handler.sendVerificationEmail(ctx, user, pendingEmail); err != nil {
+			logrus.Errorf("failed to send verification email: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+				Message: "internal server error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+	}
+
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditUser, user.UserID, before, dto.NewUserResponse(user))
+
+	message := "update profile successfully"
+	if pendingEmail != "" {
+		message = "update profile successfully, check the new email address to confirm the change"
+	}
+
+	logrus.Info(http.StatusOK, message)
+	ctx.JSON(http.StatusOK, dto.ProfileResponse{
+		Message:      message,
+		Status:       http.StatusOK,
+		PendingEmail: pendingEmail,
+		Data:         dto.NewUserResponse(user),
+	})
+}
+
+// DeleteAccountHandler deletes the logged in user and all of its todos after
+// the password has been confirmed.
+func (handler *HandlerImpl) DeleteAccountHandler(ctx *gin.Context) {
+	user, ok := currentUser(ctx)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "unauthorized",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	request := new(dto.DeleteAccountRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "password is incorrect",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	// collected before the delete, the rows go with the account
+	attachmentKeys, err := handler.todolistRepository.UserAttachmentKeys(user.UserID)
+	if err != nil {
+		logrus.Errorf("failed when get attachments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.DeleteUser(user.UserID); err != nil {
+		logrus.Errorf("failed when delete user: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.removeStoredFiles(ctx, attachmentKeys)
+	handler.audit(ctx, entity.AuditDelete, entity.AuditUser, user.UserID, dto.NewUserResponse(user), nil)
+
+	logrus.Info(http.StatusOK, "delete account successfully")
+	ctx.JSON(http.StatusOK, dto.DeleteAccountResponse{
+		Message: "delete account successfully",
+		Status:  http.StatusOK,
+	})
+}
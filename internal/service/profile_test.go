@@ -0,0 +1,153 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestGetProfileHidesPassword(t *testing.T) {
+	user := &entity.Users{UserID: 7, Username: "alwi", Email: "alwi@mail.com", Password: "$2a$10$hash"}
+
+	handler := NewHandlerImpl(mocks.NewRepository(t))
+
+	router := gin.New()
+	router.GET("/me", withUser(user), handler.GetProfileHandler)
+
+	req, err := http.NewRequest(http.MethodGet, "/me", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "password")
+	assert.NotContains(t, recorder.Body.String(), "$2a$10$hash")
+
+	var result dto.ProfileResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	assert.Equal(t, "alwi@mail.com", result.Data.Email)
+}
+
+func TestTableDrivenUpdateProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name                 string
+		bodyRequest          string
+		mock                 func(mock *mocks.Repository)
+		expectedStatus       int
+		expectedPendingEmail string
+		expectedMails        int
+	}{
+		{
+			name:        "change username",
+			bodyRequest: `{"username": "alwi09"}`,
+			mock: func(mock *mocks.Repository) {
+				mock.On("UpdateUser", int64(7), map[string]interface{}{"username": "alwi09"}).
+					Return(&entity.Users{UserID: 7, Username: "alwi09", Email: "alwi@mail.com"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "email already in use",
+			bodyRequest: `{"email": "taken@mail.com"}`,
+			mock: func(mock *mocks.Repository) {
+				mock.On("FindUserByEmail", "taken@mail.com").Return(&entity.Users{UserID: 8}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:        "change email needs verification",
+			bodyRequest: `{"email": "new@mail.com"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("FindUserByEmail", "new@mail.com").Return(nil, nil)
+				m.On("CreateEmailVerificationToken", mock.MatchedBy(func(token *entity.EmailVerificationTokens) bool {
+					return token.Email == "new@mail.com"
+				})).Return(nil)
+			},
+			expectedStatus:       http.StatusOK,
+			expectedPendingEmail: "new@mail.com",
+			expectedMails:        1,
+		},
+		{
+			name:           "invalid email",
+			bodyRequest:    `{"email": "not-an-email"}`,
+			mock:           func(mock *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			user := &entity.Users{UserID: 7, Username: "alwi", Email: "alwi@mail.com"}
+
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+
+			mail := &recordingMailer{}
+			handler := NewHandlerImpl(mockRepo, WithMailer(mail), WithConfig(&config.Config{EmailVerificationTTL: time.Hour}))
+
+			router := gin.New()
+			router.PATCH("/me", withUser(user), handler.UpdateProfileHandler)
+
+			req, err := http.NewRequest(http.MethodPatch, "/me", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			var result dto.ProfileResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+			assert.Equal(t, test.expectedPendingEmail, result.PendingEmail)
+			assert.Len(t, mail.messages, test.expectedMails)
+			if test.expectedStatus == http.StatusOK {
+				assert.Equal(t, "alwi@mail.com", result.Data.Email)
+			}
+		})
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com", Password: string(hash)}
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("UserAttachmentKeys", int64(7)).Return([]string{}, nil).Once()
+	mockRepo.On("DeleteUser", int64(7)).Return(nil).Once()
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.DELETE("/me", withUser(user), handler.DeleteAccountHandler)
+
+	req, err := http.NewRequest(http.MethodDelete, "/me", strings.NewReader(`{"password": "wrong"}`))
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	req, err = http.NewRequest(http.MethodDelete, "/me", strings.NewReader(`{"password": "secret"}`))
+	require.NoError(t, err)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"net/http"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/recurrence"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// SkipOccurrenceHandler moves a recurring todo to its next occurrence
+// without completing it.
+func (handler *HandlerImpl) SkipOccurrenceHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareEditor)
+	if !ok {
+		return
+	}
+
+	if todo.RecurrenceRule == "" {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "todo doesn't repeat",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	next, ok := nextOccurrence(todo)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "this is the last occurrence of the series",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	updates := map[string]interface{}{
+		"due_at":     next.DueAt,
+		"remind_at":  next.RemindAt,
+		"occurrence": next.Occurrence,
+	}
+	resetReminder(updates)
+	if _, err := handler.todolistRepository.Update(todo.Id, updates); err != nil {
+		logrus.Errorf("failed when skip occurrence: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditTodo, todo.Id, todo, updatedTodo(todo, updates))
+	operationID := handler.recordUndo(ctx, entity.AuditUpdate, todo.Id, &entity.UndoSnapshot{Todos: []entity.Todos{*todo}})
+
+	todo.DueAt = next.DueAt
+	todo.RemindAt = next.RemindAt
+	todo.RemindedAt = nil
+	todo.Occurrence = next.Occurrence
+
+	logrus.Info(http.StatusOK, "skip occurrence successfully")
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetID{
+		Message:     "skip occurrence successfully",
+		Status:      http.StatusOK,
+		Data:        *todo,
+		OperationID: operationID,
+	})
+}
+
+// StopRecurrenceHandler ends the series, the todo itself is kept.
+func (handler *HandlerImpl) StopRecurrenceHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareEditor)
+	if !ok {
+		return
+	}
+
+	updates := map[string]interface{}{"recurrence_rule": ""}
+	if _, err := handler.todolistRepository.Update(todo.Id, updates); err != nil {
+		logrus.Errorf("failed when stop recurrence: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditTodo, todo.Id, todo, updatedTodo(todo, updates))
+	operationID := handler.recordUndo(ctx, entity.AuditUpdate, todo.Id, &entity.UndoSnapshot{Todos: []entity.Todos{*todo}})
+
+	todo.RecurrenceRule = ""
+
+	logrus.Info(http.StatusOK, "stop recurrence successfully")
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetID{
+		Message:     "stop recurrence successfully",
+		Status:      http.StatusOK,
+		Data:        *todo,
+		OperationID: operationID,
+	})
+}
+
+// createNextOccurrence creates the todo following a completed recurring one.
+// It does nothing when the todo doesn't repeat or its series has ended.
+func createNextOccurrence(repo repository.Repository, todo *entity.Todos) (*entity.Todos, error) {
+	next, ok := nextOccurrence(todo)
+	if !ok {
+		return nil, nil
+	}
+
+	if err := repo.CreateNextOccurrence(next, todo.Id); err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
+
+// nextOccurrence builds the todo after the given one of its series. The due
+// date is computed in the todo's timezone and the reminder keeps its offset.
+func nextOccurrence(todo *entity.Todos) (*entity.Todos, bool) {
+	if todo.RecurrenceRule == "" || todo.DueAt == nil {
+		return nil, false
+	}
+
+	rule, err := recurrence.Parse(todo.RecurrenceRule)
+	if err != nil {
+		logrus.Warnf("todo %d has an invalid recurrence rule: %v", todo.Id, err)
+		return nil, false
+	}
+
+	location, err := time.LoadLocation(dto.TimezoneOrUTC(todo.Timezone))
+	if err != nil {
+		location = time.UTC
+	}
+
+	occurrence := todo.Occurrence
+	if occurrence < 1 {
+		occurrence = 1
+	}
+
+	dueAt, ok := rule.Next(todo.DueAt.In(location), occurrence)
+	if !ok {
+		return nil, false
+	}
+
+	next := &entity.Todos{
+		UserID:         todo.UserID,
+		ListID:         todo.ListID,
+		ParentID:       todo.ParentID,
+		Title:          todo.Title,
+		Description:    todo.Description,
+		Status:         entity.StatusTodo,
+		Priority:       todo.Priority,
+		DueAt:          &dueAt,
+		Timezone:       dto.TimezoneOrUTC(todo.Timezone),
+		RecurrenceRule: todo.RecurrenceRule,
+		Occurrence:     occurrence + 1,
+	}
+	if todo.RemindAt != nil {
+		remindAt := dueAt.Add(todo.RemindAt.Sub(*todo.DueAt))
+		next.RemindAt = &remindAt
+	}
+
+	return next, true
+}
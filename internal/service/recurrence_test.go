@@ -0,0 +1,187 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletingRecurringTodoCreatesNextOccurrence(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dueAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	remindAt := dueAt.Add(-30 * time.Minute)
+	current := &entity.Todos{Id: 1, UserID: 7, Status: entity.StatusTodo, DueAt: &dueAt, RemindAt: &remindAt, RecurrenceRule: "FREQ=WEEKLY", Occurrence: 2}
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(current, entity.ShareOwner, nil)
+	mockRepo.On("Update", int64(1), mock.AnythingOfType("map[string]interface {}")).Return(&entity.Todos{}, nil)
+	mockRepo.On("CloseSubtasks", int64(1), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("CreateNextOccurrence", mock.MatchedBy(func(next *entity.Todos) bool {
+		return next.DueAt.Equal(dueAt.AddDate(0, 0, 7)) &&
+			next.RemindAt.Equal(remindAt.AddDate(0, 0, 7)) &&
+			next.Status == entity.StatusTodo &&
+			next.Occurrence == 3 &&
+			next.RecurrenceRule == "FREQ=WEEKLY" &&
+			next.Title == "piket"
+	}), int64(1)).Return(nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.PUT("/update_todolist/:todolistId", withUser(&entity.Users{UserID: 7}), handler.UpdateHandlerTodolist)
+
+	body := `{"title": "piket", "description": "piket kelas", "status": "done", "due_at": "2026-10-19T09:00:00Z", "remind_at": "2026-10-19T08:30:00Z", "recurrence": "FREQ=WEEKLY"}`
+	req, err := http.NewRequest(http.MethodPut, "/update_todolist/1", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCompletingRecurringTodoKeepsStoredRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dueAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	current := &entity.Todos{Id: 1, UserID: 7, Title: "piket", Status: entity.StatusTodo, DueAt: &dueAt, Timezone: "UTC", RecurrenceRule: "FREQ=WEEKLY", Occurrence: 2}
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(current, entity.ShareOwner, nil)
+	mockRepo.On("Update", int64(1), mock.MatchedBy(func(updates map[string]interface{}) bool {
+		_, rule := updates["recurrence_rule"]
+		_, due := updates["due_at"]
+		return updates["status"] == entity.StatusDone && !rule && !due
+	})).Return(&entity.Todos{}, nil)
+	mockRepo.On("CloseSubtasks", int64(1), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("CreateNextOccurrence", mock.MatchedBy(func(next *entity.Todos) bool {
+		return next.DueAt.Equal(dueAt.AddDate(0, 0, 7)) && next.RecurrenceRule == "FREQ=WEEKLY" && next.Occurrence == 3
+	}), int64(1)).Return(nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.PUT("/update_todolist/:todolistId", withUser(&entity.Users{UserID: 7}), handler.UpdateHandlerTodolist)
+
+	body := `{"title": "piket", "description": "piket kelas", "status": "done"}`
+	req, err := http.NewRequest(http.MethodPut, "/update_todolist/1", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestLastOccurrenceEndsSeries(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dueAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	current := &entity.Todos{Id: 1, UserID: 7, Status: entity.StatusTodo, DueAt: &dueAt, RecurrenceRule: "FREQ=DAILY;COUNT=3", Occurrence: 3}
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(current, entity.ShareOwner, nil)
+	runTransactions(mockRepo)
+	mockRepo.On("Update", int64(1), mock.AnythingOfType("map[string]interface {}")).Return(&entity.Todos{}, nil)
+	mockRepo.On("CloseSubtasks", int64(1), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/todos/:todolistId/toggle", withUser(&entity.Users{UserID: 7}), handler.ToggleTodoHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/todos/1/toggle", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestTableDrivenSkipOccurrence(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	dueAt := time.Date(2026, 10, 23, 7, 0, 0, 0, jakarta) // friday
+
+	testCase := []struct {
+		name           string
+		current        entity.Todos
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:    "moves to the next weekday",
+			current: entity.Todos{Id: 1, UserID: 7, DueAt: &dueAt, Timezone: "Asia/Jakarta", RecurrenceRule: "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", Occurrence: 1},
+			mock: func(m *mocks.Repository) {
+				m.On("Update", int64(1), mock.MatchedBy(func(updates map[string]interface{}) bool {
+					next, ok := updates["due_at"].(*time.Time)
+					return ok && next.Equal(time.Date(2026, 10, 26, 7, 0, 0, 0, jakarta)) && updates["occurrence"] == 2
+				})).Return(&entity.Todos{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "last occurrence",
+			current:        entity.Todos{Id: 1, UserID: 7, DueAt: &dueAt, RecurrenceRule: "FREQ=DAILY;COUNT=1", Occurrence: 1},
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "todo doesn't repeat",
+			current:        entity.Todos{Id: 1, UserID: 7, DueAt: &dueAt},
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			current := test.current
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&current, entity.ShareOwner, nil)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/todos/:todolistId/recurrence/skip", withUser(&entity.Users{UserID: 7}), handler.SkipOccurrenceHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/todos/1/recurrence/skip", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestCreateRecurringTodoNeedsDueDate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := NewHandlerImpl(mocks.NewRepository(t))
+
+	router := gin.New()
+	router.POST("/create_todolist", handler.CreateHandlerTodolist)
+
+	for _, body := range []string{
+		`{"title": "piket", "description": "piket kelas", "recurrence": "FREQ=WEEKLY"}`,
+		`{"title": "piket", "description": "piket kelas", "recurrence": "FREQ=HOURLY", "due_at": "2026-10-19T09:00:00Z"}`,
+	} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/create_todolist", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, body)
+	}
+}
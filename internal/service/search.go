@@ -0,0 +1,59 @@
+package service
+
+import (
+	"net/http"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/search"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// snippetWidth is about how many characters of the description are returned
+// around the first match
+const snippetWidth = 160
+
+// SearchTodosHandler searches the titles and descriptions of the user's own
+// and shared todos, best matches first
+func (handler *HandlerImpl) SearchTodosHandler(ctx *gin.Context) {
+	query := new(dto.SearchQuery)
+	if err := ctx.ShouldBindQuery(query); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid query parameters",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	terms := search.Terms(query.Q)
+	if len(terms) == 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "the search has no words",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	query.ViewerID = ctx.GetInt64("user_id")
+	matches, err := handler.todolistRepository.SearchTodos(query)
+	if err != nil {
+		logrus.Errorf("failed when search todos: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	for i := range matches {
+		matches[i].TitleHighlight = search.Highlight(matches[i].Title, terms)
+		matches[i].Snippet = search.Snippet(matches[i].Description, terms, snippetWidth)
+	}
+
+	ctx.JSON(http.StatusOK, dto.SearchResponse{
+		Message: "search todolist successfully",
+		Status:  http.StatusOK,
+		More:    len(matches),
+		Data:    matches,
+	})
+}
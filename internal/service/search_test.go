@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTodosHighlightsMatches(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("SearchTodos", mock.MatchedBy(func(query *dto.SearchQuery) bool {
+		return query.Q == "sholat" && query.ViewerID == 7 && query.Status == entity.StatusTodo && query.Tag == "ibadah"
+	})).Return([]entity.TodoMatch{{
+		Todos: entity.Todos{Id: 1, Title: "Sholat tahajud", Description: "bangun jam 3 untuk <sholat>"},
+		Score: 1.5,
+	}}, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.GET("/todos/search", withUser(&entity.Users{UserID: 7}), handler.SearchTodosHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/todos/search?q=sholat&status=todo&tag=ibadah", nil))
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var result dto.SearchResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	require.Len(t, result.Data, 1)
+	assert.Equal(t, "<mark>Sholat</mark> tahajud", result.Data[0].TitleHighlight)
+	assert.Equal(t, "bangun jam 3 untuk &lt;<mark>sholat</mark>&gt;", result.Data[0].Snippet)
+	assert.Equal(t, 1.5, result.Data[0].Score)
+}
+
+func TestTableDrivenSearchTodosInvalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, path := range []string{"/todos/search", "/todos/search?q=+*-", "/todos/search?q=a&status=someday", "/todos/search?q=a&limit=500"} {
+		t.Run(path, func(t *testing.T) {
+			handler := NewHandlerImpl(mocks.NewRepository(t))
+
+			router := gin.New()
+			router.GET("/todos/search", withUser(&entity.Users{UserID: 7}), handler.SearchTodosHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+
+			assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+const maxUserAgentLength = 255
+
+// issueAccessToken records a new session for the device making the request
+// and signs an access token bound to it.
+func (handler *HandlerImpl) issueAccessToken(ctx *gin.Context, user *entity.Users) (string, error) {
+	jti, _, err := security.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	userAgent := ctx.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	now := time.Now()
+	session := &entity.Sessions{
+		UserID:       user.UserID,
+		JTI:          jti,
+		TokenVersion: user.TokenVersion,
+		IPAddress:    ctx.ClientIP(),
+		UserAgent:    userAgent,
+		LastSeenAt:   now,
+		ExpiresAt:    now.Add(config.AccessTokenExpiration),
+	}
+	if err := handler.todolistRepository.CreateSession(session); err != nil {
+		logrus.Errorf("failed when create session: %v", err)
+		return "", err
+	}
+
+	return config.CreateJWTToken(user, jti)
+}
+
+// ListSessionsHandler lists the devices the logged in user is signed in on
+func (handler *HandlerImpl) ListSessionsHandler(ctx *gin.Context) {
+	sessions, err := handler.todolistRepository.ListSessions(ctx.GetInt64("user_id"))
+	if err != nil {
+		logrus.Errorf("failed when list sessions: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	currentSessionID := ctx.GetInt64("session_id")
+	data := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		data = append(data, dto.SessionResponse{
+			Sessions: session,
+			Current:  session.SessionID == currentSessionID,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, dto.SessionResponseGetAll{
+		Message: "get all sessions successfully",
+		Status:  http.StatusOK,
+		More:    len(data),
+		Data:    data,
+	})
+}
+
+// RevokeSessionHandler logs one device out. Revoking the current session is
+// the same as logging out.
+func (handler *HandlerImpl) RevokeSessionHandler(ctx *gin.Context) {
+	sessionID, err := strconv.ParseInt(ctx.Param("sessionId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	revoked, err := handler.todolistRepository.RevokeSession(ctx.GetInt64("user_id"), sessionID)
+	if err != nil {
+		logrus.Errorf("failed when revoke session: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if revoked == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "session not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditRevoke, entity.AuditSession, sessionID, nil, nil)
+
+	logrus.Info(http.StatusOK, "revoke session successfully")
+	ctx.JSON(http.StatusOK, dto.SessionResponseDelete{
+		Message: "revoke session successfully",
+		Status:  http.StatusOK,
+	})
+}
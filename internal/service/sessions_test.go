@@ -0,0 +1,132 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestLoginRecordsSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("FindUserByEmail", "alwi@mail.com").Return(&entity.Users{UserID: 7, Email: "alwi@mail.com", Password: string(hash), TokenVersion: 2}, nil)
+	mockRepo.On("CreateSession", mock.MatchedBy(func(session *entity.Sessions) bool {
+		return session.UserID == 7 &&
+			session.TokenVersion == 2 &&
+			len(session.JTI) == 64 &&
+			session.UserAgent == "test-agent" &&
+			session.IPAddress == "192.0.2.1" &&
+			session.ExpiresAt.After(time.Now())
+	})).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/login", handler.LoginHandler)
+
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email": "alwi@mail.com", "password": "secret"}`))
+	req.Header.Set("User-Agent", "test-agent")
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestListSessionsMarksCurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("ListSessions", int64(7)).Return([]entity.Sessions{
+		{SessionID: 3, UserAgent: "laptop"},
+		{SessionID: 4, UserAgent: "phone"},
+	}, nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.GET("/me/sessions", withUser(&entity.Users{UserID: 7}), func(ctx *gin.Context) {
+		ctx.Set("session_id", int64(4))
+	}, handler.ListSessionsHandler)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/me/sessions", nil))
+
+	var result dto.SessionResponseGetAll
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	require.Len(t, result.Data, 2)
+	assert.False(t, result.Data[0].Current)
+	assert.True(t, result.Data[1].Current)
+	assert.Equal(t, "phone", result.Data[1].UserAgent)
+}
+
+func TestTableDrivenRevokeSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		sessionID      string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:           "invalid id",
+			sessionID:      "abc",
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:      "not found or other user's session",
+			sessionID: "9",
+			mock: func(m *mocks.Repository) {
+				m.On("RevokeSession", int64(7), int64(9)).Return(int64(0), nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:      "success",
+			sessionID: "3",
+			mock: func(m *mocks.Repository) {
+				m.On("RevokeSession", int64(7), int64(3)).Return(int64(1), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.DELETE("/me/sessions/:sessionId", withUser(&entity.Users{UserID: 7}), handler.RevokeSessionHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/me/sessions/"+test.sessionID, nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// ShareTodoHandler shares one of the user's todos, with its subtasks, with
+// another user by email
+func (handler *HandlerImpl) ShareTodoHandler(ctx *gin.Context) {
+	todo, ok := handler.userTodo(ctx)
+	if !ok {
+		return
+	}
+
+	handler.share(ctx, &entity.Shares{OwnerID: todo.UserID, TodoID: &todo.Id})
+}
+
+// ShareListHandler shares one of the user's lists, with every todo in it,
+// with another user by email
+func (handler *HandlerImpl) ShareListHandler(ctx *gin.Context) {
+	list, ok := handler.userList(ctx)
+	if !ok {
+		return
+	}
+
+	handler.share(ctx, &entity.Shares{OwnerID: list.UserID, ListID: &list.ListID})
+}
+
+func (handler *HandlerImpl) GetTodoSharesHandler(ctx *gin.Context) {
+	todo, ok := handler.userTodo(ctx)
+	if !ok {
+		return
+	}
+
+	handler.listShares(ctx, &todo.Id, nil)
+}
+
+func (handler *HandlerImpl) GetListSharesHandler(ctx *gin.Context) {
+	list, ok := handler.userList(ctx)
+	if !ok {
+		return
+	}
+
+	handler.listShares(ctx, nil, &list.ListID)
+}
+
+// RevokeTodoShareHandler removes a share of a todo. The owner can revoke any
+// of them, the user it was shared with can leave it.
+func (handler *HandlerImpl) RevokeTodoShareHandler(ctx *gin.Context) {
+	todoID, err := strconv.ParseInt(ctx.Param("todolistId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	handler.revokeShare(ctx, func(share *entity.Shares) bool {
+		return share.TodoID != nil && *share.TodoID == todoID
+	})
+}
+
+// RevokeListShareHandler removes a share of a list, like RevokeTodoShareHandler
+func (handler *HandlerImpl) RevokeListShareHandler(ctx *gin.Context) {
+	listID, err := strconv.ParseInt(ctx.Param("listId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	handler.revokeShare(ctx, func(share *entity.Shares) bool {
+		return share.ListID != nil && *share.ListID == listID
+	})
+}
+
+// SharedWithMeHandler returns the todos and lists other users shared with
+// the logged in user
+func (handler *HandlerImpl) SharedWithMeHandler(ctx *gin.Context) {
+	shares, err := handler.todolistRepository.ListSharedWithUser(ctx.GetInt64("user_id"))
+	if err != nil {
+		logrus.Errorf("failed when get shared with user: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ShareResponseGetAll{
+		Message: "get shared with me successfully",
+		Status:  http.StatusOK,
+		More:    len(shares),
+		Data:    shares,
+	})
+}
+
+var shareAcceptedResponse = dto.ShareAcceptedResponse{
+	Message: "shared with the user if they have an account",
+	Status:  http.StatusAccepted,
+}
+
+func (handler *HandlerImpl) share(ctx *gin.Context, share *entity.Shares) {
+	request := new(dto.ShareRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	user, err := handler.todolistRepository.FindUserByEmail(request.Email)
+	if err != nil {
+		logrus.Errorf("failed when get user by email: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// an unknown email is answered like a known one, so sharing can't be used
+	// to find out who has an account
+	if user == nil {
+		ctx.JSON(http.StatusAccepted, shareAcceptedResponse)
+		return
+	}
+
+	if user.UserID == share.OwnerID {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "you can't share with yourself",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	share.UserID = user.UserID
+	share.Role = request.Role
+	if err := handler.todolistRepository.SaveShare(share); err != nil {
+		logrus.Errorf("failed when save share: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditCreate, entity.AuditShare, share.ShareID, nil, share)
+
+	logrus.Info(http.StatusAccepted, "share successfully")
+	ctx.JSON(http.StatusAccepted, shareAcceptedResponse)
+}
+
+func (handler *HandlerImpl) listShares(ctx *gin.Context, todoID *int64, listID *int64) {
+	shares, err := handler.todolistRepository.ListShares(todoID, listID)
+	if err != nil {
+		logrus.Errorf("failed when get shares: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ShareResponseGetAll{
+		Message: "get all shares successfully",
+		Status:  http.StatusOK,
+		More:    len(shares),
+		Data:    shares,
+	})
+}
+
+// revokeShare deletes the share named by the :shareId param when it is about
+// the right todo or list and the logged in user is its owner or grantee.
+func (handler *HandlerImpl) revokeShare(ctx *gin.Context, target func(share *entity.Shares) bool) {
+	shareID, err := strconv.ParseInt(ctx.Param("shareId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	share, err := handler.todolistRepository.FindShare(shareID)
+	if err != nil {
+		logrus.Errorf("failed when get share: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	userID := ctx.GetInt64("user_id")
+	if share == nil || !target(share) || (share.OwnerID != userID && share.UserID != userID) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "share not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	if _, err := handler.todolistRepository.DeleteShare(share.ShareID); err != nil {
+		logrus.Errorf("failed when delete share: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditDelete, entity.AuditShare, share.ShareID, share, nil)
+
+	logrus.Info(http.StatusOK, "revoke share successfully")
+	ctx.JSON(http.StatusOK, dto.ShareResponseDelete{
+		Message: "revoke share successfully",
+		Status:  http.StatusOK,
+	})
+}
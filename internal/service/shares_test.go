@@ -0,0 +1,316 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDrivenShareTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+	todoID := int64(1)
+
+	testCase := []struct {
+		name           string
+		bodyRequest    string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:        "share as editor",
+			bodyRequest: `{"email": "budi@mail.com", "role": "editor"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				m.On("FindUserByEmail", "budi@mail.com").Return(&entity.Users{UserID: 8, Email: "budi@mail.com"}, nil)
+				m.On("SaveShare", &entity.Shares{OwnerID: 7, UserID: 8, TodoID: &todoID, Role: entity.ShareEditor}).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:        "unknown user",
+			bodyRequest: `{"email": "nobody@mail.com", "role": "viewer"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				m.On("FindUserByEmail", "nobody@mail.com").Return(nil, nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+		{
+			name:        "with yourself",
+			bodyRequest: `{"email": "alwi@mail.com", "role": "viewer"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				m.On("FindUserByEmail", "alwi@mail.com").Return(user, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "owner role can't be given",
+			bodyRequest: `{"email": "budi@mail.com", "role": "owner"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:        "editors can't reshare",
+			bodyRequest: `{"email": "cici@mail.com", "role": "viewer"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 8}, entity.ShareEditor, nil)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/todos/:todolistId/shares", withUser(user), handler.ShareTodoHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/todos/1/shares", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestShareAnswersUnknownEmailLikeKnown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+	mockRepo.On("FindUserByEmail", "budi@mail.com").Return(&entity.Users{UserID: 8, Email: "budi@mail.com"}, nil)
+	mockRepo.On("FindUserByEmail", "nobody@mail.com").Return(nil, nil)
+	mockRepo.On("SaveShare", mock.AnythingOfType("*entity.Shares")).Return(nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/todos/:todolistId/shares", withUser(&entity.Users{UserID: 7}), handler.ShareTodoHandler)
+
+	var bodies []string
+	for _, email := range []string{"budi@mail.com", "nobody@mail.com"} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/todos/1/shares", strings.NewReader(`{"email": "`+email+`", "role": "viewer"}`)))
+
+		assert.Equal(t, http.StatusAccepted, recorder.Code)
+		bodies = append(bodies, recorder.Body.String())
+	}
+
+	assert.Equal(t, bodies[0], bodies[1])
+}
+
+func TestTableDrivenSharedTodoAccess(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+	shared := &entity.Todos{Id: 1, UserID: 8, Title: "piket", Status: entity.StatusTodo}
+
+	testCase := []struct {
+		name           string
+		role           string
+		method         string
+		path           string
+		bodyRequest    string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:   "viewer reads",
+			role:   entity.ShareViewer,
+			method: http.MethodGet,
+			path:   "/find_by_id_todolist/1",
+			mock: func(m *mocks.Repository) {
+				m.On("SubtaskProgress", int64(1)).Return(entity.SubtaskProgress{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "viewer can't update",
+			role:           entity.ShareViewer,
+			method:         http.MethodPut,
+			path:           "/update_todolist/1",
+			bodyRequest:    `{"title": "piket", "description": "piket kelas"}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:        "editor updates",
+			role:        entity.ShareEditor,
+			method:      http.MethodPut,
+			path:        "/update_todolist/1",
+			bodyRequest: `{"title": "piket", "description": "piket kelas"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("Update", int64(1), mock.AnythingOfType("map[string]interface {}")).Return(shared, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "editor can't delete",
+			role:           entity.ShareEditor,
+			method:         http.MethodDelete,
+			path:           "/delete_todolist/1",
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "not shared",
+			method:         http.MethodGet,
+			path:           "/find_by_id_todolist/1",
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			current := *shared
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			if test.role == "" {
+				mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(nil, "", nil)
+			} else {
+				mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&current, test.role, nil)
+			}
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.Use(withUser(user))
+			router.GET("/find_by_id_todolist/:todolistId", handler.GetIDHandlerTodolist)
+			router.PUT("/update_todolist/:todolistId", handler.UpdateHandlerTodolist)
+			router.DELETE("/delete_todolist/:todolistId", handler.DeleteHandlerTodolist)
+
+			req, err := http.NewRequest(test.method, test.path, strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestCreateTodoInSharedList(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+
+	expectAudit(mockRepo)
+	mockRepo.On("FindList", int64(7), int64(3)).Return(nil, nil)
+	mockRepo.On("FindSharedList", int64(7), int64(3)).Return(&entity.Lists{ListID: 3, UserID: 8}, entity.ShareEditor, nil)
+	mockRepo.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+		return todo.UserID == 8 && *todo.ListID == 3
+	})).Return(nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/create_todolist", withUser(&entity.Users{UserID: 7}), handler.CreateHandlerTodolist)
+
+	body := `{"title": "piket", "description": "piket kelas", "list_id": 3}`
+	req, err := http.NewRequest(http.MethodPost, "/create_todolist", strings.NewReader(body))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusCreated, recorder.Code)
+}
+
+func TestTableDrivenRevokeShare(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	listID := int64(3)
+	otherListID := int64(4)
+
+	testCase := []struct {
+		name           string
+		userID         int64
+		share          *entity.Shares
+		expectedStatus int
+	}{
+		{
+			name:           "owner revokes",
+			userID:         7,
+			share:          &entity.Shares{ShareID: 5, OwnerID: 7, UserID: 8, ListID: &listID},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "grantee leaves",
+			userID:         8,
+			share:          &entity.Shares{ShareID: 5, OwnerID: 7, UserID: 8, ListID: &listID},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "someone else",
+			userID:         9,
+			share:          &entity.Shares{ShareID: 5, OwnerID: 7, UserID: 8, ListID: &listID},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "share of another list",
+			userID:         7,
+			share:          &entity.Shares{ShareID: 5, OwnerID: 7, UserID: 8, ListID: &otherListID},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindShare", int64(5)).Return(test.share, nil)
+			if test.expectedStatus == http.StatusOK {
+				mockRepo.On("DeleteShare", int64(5)).Return(int64(1), nil)
+			}
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.DELETE("/lists/:listId/shares/:shareId", withUser(&entity.Users{UserID: test.userID}), handler.RevokeListShareHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/lists/3/shares/5", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetAllTodolistScopedToViewer(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("GetAll", mock.MatchedBy(func(query *dto.TodoQuery) bool {
+		return query.ViewerID == 7
+	})).Return([]entity.Todos{}, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.GET("/find_all_todolist", withUser(&entity.Users{UserID: 7}), handler.GetAllHandlerTodolist)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/find_all_todolist", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
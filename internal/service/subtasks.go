@@ -0,0 +1,289 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// AddSubtaskHandler creates a subtask at the end of its parent's checklist.
+// Subtasks live in the parent's list and can be nested MaxSubtaskDepth levels deep.
+func (handler *HandlerImpl) AddSubtaskHandler(ctx *gin.Context) {
+	parent, ok := handler.todoFor(ctx, entity.ShareEditor)
+	if !ok {
+		return
+	}
+
+	request := new(dto.CreateSubtaskRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if !entity.IsOpenStatus(dto.StatusOrTodo(parent.Status)) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "can't add a subtask to a closed todo",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	depth, err := handler.todoDepth(parent)
+	if err != nil {
+		logrus.Errorf("failed when get todo depth: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if depth >= entity.MaxSubtaskDepth {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: fmt.Sprintf("subtasks can only be nested %d levels deep", entity.MaxSubtaskDepth),
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	order, err := handler.todolistRepository.NextSubtaskOrder(parent.Id)
+	if err != nil {
+		logrus.Errorf("failed when get subtask order: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	subtask := &entity.Todos{
+		UserID:       parent.UserID,
+		ListID:       parent.ListID,
+		ParentID:     &parent.Id,
+		SubtaskOrder: order,
+		Title:        request.Title,
+		Description:  request.Description,
+		Status:       entity.StatusTodo,
+		Priority:     request.Priority,
+		Timezone:     dto.TimezoneOrUTC(parent.Timezone),
+	}
+	if subtask.Priority == "" {
+		subtask.Priority = entity.PriorityMedium
+	}
+
+	if err := handler.todolistRepository.Create(subtask); err != nil {
+		logrus.Errorf("failed when create subtask: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditCreate, entity.AuditTodo, subtask.Id, nil, subtask)
+
+	logrus.Info(http.StatusCreated, "create subtask successfully")
+	ctx.JSON(http.StatusCreated, dto.TodolistResponseCreate{
+		Message: "create subtask successfully",
+		Status:  http.StatusCreated,
+		Data:    *subtask,
+	})
+}
+
+func (handler *HandlerImpl) GetSubtasksHandler(ctx *gin.Context) {
+	parent, ok := handler.todoFor(ctx, entity.ShareViewer)
+	if !ok {
+		return
+	}
+
+	subtasks, err := handler.todolistRepository.GetSubtasks(parent.Id)
+	if err != nil {
+		logrus.Errorf("failed when get subtasks: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetAll{
+		Message: "get all subtasks successfully",
+		Status:  http.StatusOK,
+		More:    len(subtasks),
+		Data:    subtasks,
+	})
+}
+
+// ReorderSubtasksHandler takes every subtask id of the parent in the new order.
+func (handler *HandlerImpl) ReorderSubtasksHandler(ctx *gin.Context) {
+	parent, ok := handler.todoFor(ctx, entity.ShareEditor)
+	if !ok {
+		return
+	}
+
+	request := new(dto.ReorderSubtasksRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	subtasks, err := handler.todolistRepository.GetSubtasks(parent.Id)
+	if err != nil {
+		logrus.Errorf("failed when get subtasks: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	byID := make(map[int64]entity.Todos, len(subtasks))
+	for _, subtask := range subtasks {
+		byID[subtask.Id] = subtask
+	}
+
+	reordered := make([]entity.Todos, 0, len(request.Order))
+	for position, todoID := range request.Order {
+		subtask, found := byID[todoID]
+		if !found {
+			break
+		}
+		delete(byID, todoID)
+
+		subtask.SubtaskOrder = position
+		reordered = append(reordered, subtask)
+	}
+
+	if len(reordered) != len(subtasks) || len(request.Order) != len(subtasks) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "order must list every subtask exactly once",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.ReorderSubtasks(parent.Id, request.Order); err != nil {
+		logrus.Errorf("failed when reorder subtasks: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	snapshot := &entity.UndoSnapshot{Todos: append([]entity.Todos{*parent}, subtasks...)}
+	operationID := handler.recordUndo(ctx, entity.AuditUpdate, parent.Id, snapshot)
+
+	logrus.Info(http.StatusOK, "reorder subtasks successfully")
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetAll{
+		Message:     "reorder subtasks successfully",
+		Status:      http.StatusOK,
+		More:        len(reordered),
+		Data:        reordered,
+		OperationID: operationID,
+	})
+}
+
+// ToggleTodoHandler ticks a todo or subtask off, or reopens it when it's
+// already done. Like any other completion, ticking off closes the open
+// subtasks and creates the next occurrence of a recurring todo.
+func (handler *HandlerImpl) ToggleTodoHandler(ctx *gin.Context) {
+	todo, ok := handler.todoFor(ctx, entity.ShareEditor)
+	if !ok {
+		return
+	}
+
+	from := dto.StatusOrTodo(todo.Status)
+	to := entity.StatusDone
+	if !entity.IsOpenStatus(from) {
+		to = entity.StatusTodo
+	}
+
+	if !entity.CanTransition(from, to) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: fmt.Sprintf("%v: %s to %s", dto.ErrInvalidTransition, from, to),
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": to}
+	for key, value := range dto.CompletionUpdate(from, to, now) {
+		updates[key] = value
+	}
+
+	// the todo, its subtasks and the next occurrence change together
+	var snapshot *entity.UndoSnapshot
+	err := handler.todolistRepository.Transaction(func(tx repository.Repository) error {
+		var err error
+		if snapshot, err = handler.updateSnapshot(tx, todo, entity.IsClosing(from, to)); err != nil {
+			return err
+		}
+
+		if _, err := tx.Update(todo.Id, updates); err != nil {
+			return err
+		}
+
+		if !entity.IsClosing(from, to) {
+			return nil
+		}
+
+		if err := tx.CloseSubtasks(todo.Id, to, now); err != nil {
+			return err
+		}
+
+		_, err = createNextOccurrence(tx, todo)
+		return err
+	})
+	if err != nil {
+		handler.abortOperation(ctx, err)
+		return
+	}
+
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditTodo, todo.Id, todo, updatedTodo(todo, updates))
+	operationID := handler.recordUndo(ctx, entity.AuditUpdate, todo.Id, snapshot)
+
+	todo.Status = to
+	todo.CompletedAt = nil
+	if to == entity.StatusDone {
+		todo.CompletedAt = &now
+	}
+
+	logrus.Info(http.StatusOK, "toggle todolist successfully")
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetID{
+		Message:     "toggle todolist successfully",
+		Status:      http.StatusOK,
+		Data:        *todo,
+		OperationID: operationID,
+	})
+}
+
+// todoDepth returns how many levels the todo is down its tree, 1 for a top level todo.
+func (handler *HandlerImpl) todoDepth(todo *entity.Todos) (int, error) {
+	depth := 1
+	for current := todo; current.ParentID != nil && depth <= entity.MaxSubtaskDepth; depth++ {
+		parent, err := handler.todolistRepository.GetID(*current.ParentID)
+		if err != nil {
+			return 0, err
+		}
+		if parent == nil {
+			break
+		}
+		current = parent
+	}
+
+	return depth, nil
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDrivenAddSubtask(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+	listID := int64(3)
+	one, two := int64(1), int64(2)
+
+	testCase := []struct {
+		name           string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name: "added after its siblings",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(5)).Return(&entity.Todos{Id: 5, UserID: 7, ListID: &listID, Status: entity.StatusTodo}, entity.ShareOwner, nil)
+				m.On("NextSubtaskOrder", int64(5)).Return(2, nil)
+				m.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+					return *todo.ParentID == 5 && *todo.ListID == 3 && todo.UserID == 7 && todo.SubtaskOrder == 2
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: "too deep",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(5)).Return(&entity.Todos{Id: 5, UserID: 7, ParentID: &two, Status: entity.StatusTodo}, entity.ShareOwner, nil)
+				m.On("GetID", int64(2)).Return(&entity.Todos{Id: 2, UserID: 7, ParentID: &one, Status: entity.StatusTodo}, nil)
+				m.On("GetID", int64(1)).Return(&entity.Todos{Id: 1, UserID: 7, Status: entity.StatusTodo}, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "parent is done",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(5)).Return(&entity.Todos{Id: 5, UserID: 7, Status: entity.StatusDone}, entity.ShareOwner, nil)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: "todo of another user",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(5)).Return(nil, "", nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/todos/:todolistId/subtasks", withUser(user), handler.AddSubtaskHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/todos/5/subtasks", strings.NewReader(`{"title": "wudhu"}`))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenReorderSubtasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+
+	testCase := []struct {
+		name           string
+		bodyRequest    string
+		expectedStatus int
+		expectedOrder  []int64
+	}{
+		{
+			name:           "new order",
+			bodyRequest:    `{"order": [12, 10, 11]}`,
+			expectedStatus: http.StatusOK,
+			expectedOrder:  []int64{12, 10, 11},
+		},
+		{
+			name:           "missing a subtask",
+			bodyRequest:    `{"order": [12, 10]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "repeated subtask",
+			bodyRequest:    `{"order": [12, 10, 10]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "subtask of another todo",
+			bodyRequest:    `{"order": [12, 10, 11, 99]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			mockRepo.On("FindTodoForUser", int64(7), int64(5)).Return(&entity.Todos{Id: 5, UserID: 7}, entity.ShareOwner, nil)
+			mockRepo.On("GetSubtasks", int64(5)).Return([]entity.Todos{{Id: 10}, {Id: 11}, {Id: 12}}, nil)
+			if test.expectedOrder != nil {
+				mockRepo.On("ReorderSubtasks", int64(5), test.expectedOrder).Return(nil)
+			}
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.PUT("/todos/:todolistId/subtasks/order", withUser(user), handler.ReorderSubtasksHandler)
+
+			req, err := http.NewRequest(http.MethodPut, "/todos/5/subtasks/order", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenToggleTodo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+
+	testCase := []struct {
+		name           string
+		current        entity.Todos
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+		expectedTodo   string
+	}{
+		{
+			name:    "ticking off closes the subtasks",
+			current: entity.Todos{Id: 5, UserID: 7, Status: entity.StatusInProgress},
+			mock: func(m *mocks.Repository) {
+				runTransactions(m)
+				m.On("Update", int64(5), mock.MatchedBy(func(updates map[string]interface{}) bool {
+					return updates["status"] == entity.StatusDone && updates["completed_at"] != nil
+				})).Return(&entity.Todos{}, nil)
+				m.On("CloseSubtasks", int64(5), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTodo:   entity.StatusDone,
+		},
+		{
+			name:    "subtasks fail to close",
+			current: entity.Todos{Id: 5, UserID: 7, Status: entity.StatusTodo},
+			mock: func(m *mocks.Repository) {
+				runTransactions(m)
+				m.On("Update", int64(5), mock.Anything).Return(&entity.Todos{}, nil)
+				m.On("CloseSubtasks", int64(5), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(errors.New("connection lost"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name:    "reopen",
+			current: entity.Todos{Id: 5, UserID: 7, Status: entity.StatusDone},
+			mock: func(m *mocks.Repository) {
+				runTransactions(m)
+				m.On("Update", int64(5), map[string]interface{}{"status": entity.StatusTodo, "completed_at": nil}).Return(&entity.Todos{}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTodo:   entity.StatusTodo,
+		},
+		{
+			name:           "blocked can't be ticked off",
+			current:        entity.Todos{Id: 5, UserID: 7, Status: entity.StatusBlocked},
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			current := test.current
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(7), int64(5)).Return(&current, entity.ShareOwner, nil)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/todos/:todolistId/toggle", withUser(user), handler.ToggleTodoHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/todos/5/toggle", nil))
+
+			require.Equal(t, test.expectedStatus, recorder.Code)
+			if test.expectedTodo != "" {
+				var result dto.TodolistResponseGetID
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+				assert.Equal(t, test.expectedTodo, result.Data.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+func (handler *HandlerImpl) CreateTagHandler(ctx *gin.Context) {
+	name, ok := bindTagName(ctx)
+	if !ok {
+		return
+	}
+
+	tag := &entity.Tags{
+		UserID: ctx.GetInt64("user_id"),
+		Name:   name,
+	}
+
+	err := handler.todolistRepository.CreateTag(tag)
+	if errors.Is(err, repository.ErrDuplicateTag) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "tag already exists",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when create tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditCreate, entity.AuditTag, tag.TagID, nil, tag)
+
+	logrus.Info(http.StatusCreated, "create tag successfully")
+	ctx.JSON(http.StatusCreated, dto.TagResponse{
+		Message: "create tag successfully",
+		Status:  http.StatusCreated,
+		Data:    *tag,
+	})
+}
+
+func (handler *HandlerImpl) GetTagsHandler(ctx *gin.Context) {
+	tags, err := handler.todolistRepository.GetTags(ctx.GetInt64("user_id"))
+	if err != nil {
+		logrus.Errorf("failed when get tags: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.TagResponseGetAll{
+		Message: "get all tags successfully",
+		Status:  http.StatusOK,
+		More:    len(tags),
+		Data:    tags,
+	})
+}
+
+func (handler *HandlerImpl) RenameTagHandler(ctx *gin.Context) {
+	tag, ok := handler.userTag(ctx)
+	if !ok {
+		return
+	}
+
+	name, ok := bindTagName(ctx)
+	if !ok {
+		return
+	}
+
+	err := handler.todolistRepository.RenameTag(tag, name)
+	if errors.Is(err, repository.ErrDuplicateTag) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "tag already exists",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when rename tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	before := *tag
+	tag.Name = name
+	handler.audit(ctx, entity.AuditUpdate, entity.AuditTag, tag.TagID, before, tag)
+
+	logrus.Info(http.StatusOK, "rename tag successfully")
+	ctx.JSON(http.StatusOK, dto.TagResponse{
+		Message: "rename tag successfully",
+		Status:  http.StatusOK,
+		Data:    *tag,
+	})
+}
+
+// DeleteTagHandler deletes the tag and takes it off every todo
+func (handler *HandlerImpl) DeleteTagHandler(ctx *gin.Context) {
+	tag, ok := handler.userTag(ctx)
+	if !ok {
+		return
+	}
+
+	if err := handler.todolistRepository.DeleteTag(tag.TagID); err != nil {
+		logrus.Errorf("failed when delete tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditDelete, entity.AuditTag, tag.TagID, tag, nil)
+
+	logrus.Info(http.StatusOK, "delete tag successfully")
+	ctx.JSON(http.StatusOK, dto.TagResponseDelete{
+		Message: "delete tag successfully",
+		Status:  http.StatusOK,
+	})
+}
+
+func (handler *HandlerImpl) AttachTagHandler(ctx *gin.Context) {
+	todo, ok := handler.userTodo(ctx)
+	if !ok {
+		return
+	}
+
+	tag, ok := handler.userTag(ctx)
+	if !ok {
+		return
+	}
+
+	if err := handler.todolistRepository.AttachTag(todo.Id, tag.TagID); err != nil {
+		logrus.Errorf("failed when attach tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditAttach, entity.AuditTag, tag.TagID, nil, gin.H{"todo_id": todo.Id})
+
+	logrus.Info(http.StatusOK, "attach tag successfully")
+	ctx.JSON(http.StatusOK, dto.TagResponse{
+		Message: "attach tag successfully",
+		Status:  http.StatusOK,
+		Data:    *tag,
+	})
+}
+
+func (handler *HandlerImpl) DetachTagHandler(ctx *gin.Context) {
+	todo, ok := handler.userTodo(ctx)
+	if !ok {
+		return
+	}
+
+	tag, ok := handler.userTag(ctx)
+	if !ok {
+		return
+	}
+
+	detached, err := handler.todolistRepository.DetachTag(todo.Id, tag.TagID)
+	if err != nil {
+		logrus.Errorf("failed when detach tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if detached == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "todo doesn't have this tag",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditDetach, entity.AuditTag, tag.TagID, gin.H{"todo_id": todo.Id}, nil)
+
+	logrus.Info(http.StatusOK, "detach tag successfully")
+	ctx.JSON(http.StatusOK, dto.TagResponseDelete{
+		Message: "detach tag successfully",
+		Status:  http.StatusOK,
+	})
+}
+
+// userTag loads the tag named by the :tagId param, which must belong to the
+// logged in user.
+func (handler *HandlerImpl) userTag(ctx *gin.Context) (*entity.Tags, bool) {
+	tagID, err := strconv.ParseInt(ctx.Param("tagId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: err.Error(),
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+
+	tag, err := handler.todolistRepository.FindTag(ctx.GetInt64("user_id"), tagID)
+	if err != nil {
+		logrus.Errorf("failed when get tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+
+	if tag == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "tag not found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+
+	return tag, true
+}
+
+func bindTagName(ctx *gin.Context) (string, bool) {
+	request := new(dto.TagRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil || strings.TrimSpace(request.Name) == "" {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return "", false
+	}
+
+	return strings.TrimSpace(request.Name), true
+}
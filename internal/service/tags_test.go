@@ -0,0 +1,152 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDrivenCreateTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+
+	testCase := []struct {
+		name           string
+		bodyRequest    string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:        "name is trimmed",
+			bodyRequest: `{"name": " ibadah "}`,
+			mock: func(m *mocks.Repository) {
+				m.On("CreateTag", &entity.Tags{UserID: 7, Name: "ibadah"}).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:        "duplicate name",
+			bodyRequest: `{"name": "ibadah"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("CreateTag", mock.AnythingOfType("*entity.Tags")).Return(repository.ErrDuplicateTag)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "comma in name",
+			bodyRequest:    `{"name": "ibadah,harian"}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "blank name",
+			bodyRequest:    `{"name": "   "}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/tags", withUser(user), handler.CreateTagHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/tags", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenAttachTag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user := &entity.Users{UserID: 7, Email: "alwi@mail.com"}
+
+	testCase := []struct {
+		name           string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name: "attach",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				m.On("FindTag", int64(7), int64(4)).Return(&entity.Tags{TagID: 4, UserID: 7, Name: "ibadah"}, nil)
+				m.On("AttachTag", int64(1), int64(4)).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: "tag of another user",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(&entity.Todos{Id: 1, UserID: 7}, entity.ShareOwner, nil)
+				m.On("FindTag", int64(7), int64(4)).Return(nil, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "todo of another user",
+			mock: func(m *mocks.Repository) {
+				m.On("FindTodoForUser", int64(7), int64(1)).Return(nil, "", nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.PUT("/todos/:todolistId/tags/:tagId", withUser(user), handler.AttachTagHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/todos/1/tags/4", nil))
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestGetAllTodolistTagFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("GetAll", mock.MatchedBy(func(query *dto.TodoQuery) bool {
+		return assert.ObjectsAreEqual([]string{"ibadah", "harian"}, query.TagNames()) && query.TagMode == dto.TagModeAll
+	})).Return([]entity.Todos{{Id: 1, Tags: []string{"harian", "ibadah"}}}, nil)
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.GET("/find_all_todolist", handler.GetAllHandlerTodolist)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/find_all_todolist?tag=ibadah,%20harian,,ibadah&tag_mode=all", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `"tags":["harian","ibadah"]`)
+}
@@ -0,0 +1,179 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTableDrivenUpdateTodolistStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	completedAt := time.Now().Add(-time.Hour)
+
+	testCase := []struct {
+		name           string
+		current        entity.Todos
+		status         string
+		expectedStatus int
+		expectedUpdate func(updates map[string]interface{}) bool
+		closesSubtasks bool
+	}{
+		{
+			name:           "start working",
+			current:        entity.Todos{Id: 1, Status: entity.StatusTodo},
+			status:         `"in_progress"`,
+			expectedStatus: http.StatusOK,
+			expectedUpdate: func(updates map[string]interface{}) bool {
+				_, completed := updates["completed_at"]
+				return updates["status"] == entity.StatusInProgress && !completed
+			},
+		},
+		{
+			name:           "finish stamps completed_at",
+			current:        entity.Todos{Id: 1, Status: entity.StatusInProgress},
+			status:         `"done"`,
+			expectedStatus: http.StatusOK,
+			expectedUpdate: func(updates map[string]interface{}) bool {
+				completedAt, ok := updates["completed_at"].(time.Time)
+				return updates["status"] == entity.StatusDone && ok && !completedAt.IsZero()
+			},
+			closesSubtasks: true,
+		},
+		{
+			name:           "legacy true completes",
+			current:        entity.Todos{Id: 1, Status: entity.StatusInProgress},
+			status:         `true`,
+			expectedStatus: http.StatusOK,
+			expectedUpdate: func(updates map[string]interface{}) bool {
+				return updates["status"] == entity.StatusDone
+			},
+			closesSubtasks: true,
+		},
+		{
+			name:           "legacy false keeps an open status",
+			current:        entity.Todos{Id: 1, Status: entity.StatusBlocked},
+			status:         `false`,
+			expectedStatus: http.StatusOK,
+			expectedUpdate: func(updates map[string]interface{}) bool {
+				return updates["status"] == entity.StatusBlocked
+			},
+		},
+		{
+			name:           "legacy false reopens a done todo",
+			current:        entity.Todos{Id: 1, Status: entity.StatusDone, CompletedAt: &completedAt},
+			status:         `false`,
+			expectedStatus: http.StatusOK,
+			expectedUpdate: func(updates map[string]interface{}) bool {
+				completedAt, cleared := updates["completed_at"]
+				return updates["status"] == entity.StatusTodo && cleared && completedAt == nil
+			},
+		},
+		{
+			name:           "done can't become blocked",
+			current:        entity.Todos{Id: 1, Status: entity.StatusDone},
+			status:         `"blocked"`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "blocked can't be finished",
+			current:        entity.Todos{Id: 1, Status: entity.StatusBlocked},
+			status:         `"done"`,
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "unknown status",
+			current:        entity.Todos{Id: 1, Status: entity.StatusTodo},
+			status:         `"someday"`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			current := test.current
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			if test.expectedStatus != http.StatusBadRequest {
+				mockRepo.On("FindTodoForUser", int64(0), int64(1)).Return(&current, entity.ShareOwner, nil)
+			}
+			if test.expectedUpdate != nil {
+				mockRepo.On("Update", int64(1), mock.MatchedBy(test.expectedUpdate)).Return(&current, nil)
+			}
+			if test.closesSubtasks {
+				mockRepo.On("CloseSubtasks", int64(1), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(nil)
+			}
+
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.PUT("/update_todolist/:todolistId", handler.UpdateHandlerTodolist)
+
+			body := `{"title": "sholat", "description": "sholat tahajud", "status": ` + test.status + `}`
+			req, err := http.NewRequest(http.MethodPut, "/update_todolist/1", strings.NewReader(body))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestTableDrivenCreateTodolistPriority(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	testCase := []struct {
+		name           string
+		bodyRequest    string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:        "urgent and already done",
+			bodyRequest: `{"title": "sholat", "description": "sholat tahajud", "priority": "urgent", "status": "done"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("Create", mock.MatchedBy(func(todo *entity.Todos) bool {
+					return todo.Priority == entity.PriorityUrgent && todo.Status == entity.StatusDone && todo.CompletedAt != nil
+				})).Return(nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "unknown priority",
+			bodyRequest:    `{"title": "sholat", "description": "sholat tahajud", "priority": "whenever"}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/create_todolist", handler.CreateHandlerTodolist)
+
+			req, err := http.NewRequest(http.MethodPost, "/create_todolist", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,397 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	recoveryCodeCount = 10
+
+	// maxTwoFactorAttempts is how many codes a challenge token may try, after
+	// that the user has to log in with the password again
+	maxTwoFactorAttempts = 5
+)
+
+// SetupTwoFactorHandler generates a TOTP secret for the logged in user. 2FA
+// is only enabled once a code has been confirmed.
+func (handler *HandlerImpl) SetupTwoFactorHandler(ctx *gin.Context) {
+	user, ok := currentUser(ctx)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "unauthorized",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	if user.TwoFactorEnabled() {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "two-factor authentication is already enabled",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.SetTOTPSecret(user.UserID, secret); err != nil {
+		logrus.Errorf("failed when set totp secret: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.TwoFactorSetupResponse{
+		Message:    "scan the otpauth uri with an authenticator app, then confirm with a code",
+		Status:     http.StatusOK,
+		Secret:     secret,
+		OTPAuthURI: security.TOTPURI(handler.cfg.TOTPIssuer, user.Email, secret),
+	})
+}
+
+// ConfirmTwoFactorHandler enables 2FA once the user proves the authenticator
+// works, and hands out the recovery codes (only this once).
+func (handler *HandlerImpl) ConfirmTwoFactorHandler(ctx *gin.Context) {
+	user, ok := currentUser(ctx)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "unauthorized",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	request := new(dto.TwoFactorCodeRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if user.TwoFactorEnabled() {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: "two-factor authentication is already enabled",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	if user.TOTPSecret == nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "two-factor authentication has not been set up",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	valid, err := handler.verifySecondFactor(user, request.Code, "")
+	if err != nil {
+		logrus.Errorf("failed when verify totp code: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "invalid two-factor code",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	codes, hashes, err := security.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "an error occurred",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.EnableTwoFactor(user.UserID, hashes); err != nil {
+		logrus.Errorf("failed when enable two-factor: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, "two-factor authentication enabled")
+	ctx.JSON(http.StatusOK, dto.TwoFactorConfirmResponse{
+		Message:       "two-factor authentication enabled, store the recovery codes somewhere safe",
+		Status:        http.StatusOK,
+		RecoveryCodes: codes,
+	})
+}
+
+// DisableTwoFactorHandler turns 2FA off for the logged in user
+func (handler *HandlerImpl) DisableTwoFactorHandler(ctx *gin.Context) {
+	user, ok := currentUser(ctx)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "unauthorized",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	request := new(dto.DisableTwoFactorRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if !user.TwoFactorEnabled() {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "two-factor authentication is not enabled",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "password is incorrect",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	valid, err := handler.verifySecondFactor(user, request.Code, request.RecoveryCode)
+	if err != nil {
+		logrus.Errorf("failed when verify second factor: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "invalid two-factor code",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	if err := handler.todolistRepository.DisableTwoFactor(user.UserID); err != nil {
+		logrus.Errorf("failed when disable two-factor: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, "two-factor authentication disabled")
+	ctx.JSON(http.StatusOK, dto.TwoFactorResponse{
+		Message: "two-factor authentication disabled",
+		Status:  http.StatusOK,
+	})
+}
+
+// issueChallengeToken records a challenge for the user and signs a challenge
+// token bound to it.
+func (handler *HandlerImpl) issueChallengeToken(user *entity.Users) (string, error) {
+	jti, _, err := security.GenerateToken()
+	if err != nil {
+		return "", err
+	}
+
+	challenge := &entity.TwoFactorChallenges{
+		UserID:    user.UserID,
+		JTI:       jti,
+		ExpiresAt: time.Now().Add(config.ChallengeExpiration),
+	}
+	if err := handler.todolistRepository.CreateTwoFactorChallenge(challenge); err != nil {
+		logrus.Errorf("failed when create two-factor challenge: %v", err)
+		return "", err
+	}
+
+	return config.CreateChallengeToken(user, jti)
+}
+
+// LoginTwoFactorHandler is the second step of the login of a user with 2FA:
+// it trades the challenge token plus a code for an access token. A challenge
+// token works once and allows maxTwoFactorAttempts codes.
+func (handler *HandlerImpl) LoginTwoFactorHandler(ctx *gin.Context) {
+	request := new(dto.LoginTwoFactorRequest)
+	if err := ctx.ShouldBindJSON(request); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid input validation",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	claims, err := config.ParseChallengeToken(request.ChallengeToken)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "invalid or expired challenge token",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	claimed, err := handler.todolistRepository.ClaimTwoFactorAttempt(claims.Id, maxTwoFactorAttempts)
+	if err != nil {
+		logrus.Errorf("failed when claim two-factor attempt: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if !claimed {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "invalid or expired challenge token",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	user, err := handler.todolistRepository.FindUserByID(claims.UserID)
+	if err != nil {
+		logrus.Errorf("failed when get user by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if user == nil || user.TokenVersion != claims.TokenVersion || user.DisabledAt != nil || !user.TwoFactorEnabled() {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "invalid or expired challenge token",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	valid, err := handler.verifySecondFactor(user, request.Code, request.RecoveryCode)
+	if err != nil {
+		logrus.Errorf("failed when verify second factor: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if !valid {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "invalid two-factor code",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	consumed, err := handler.todolistRepository.ConsumeTwoFactorChallenge(claims.Id)
+	if err != nil {
+		logrus.Errorf("failed when consume two-factor challenge: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if !consumed {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Message: "invalid or expired challenge token",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	tokenString, err := handler.issueAccessToken(ctx, user)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "Failed to create token",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.auditAs(ctx, user.UserID, entity.AuditLogin, entity.AuditUser, user.UserID, nil, nil)
+
+	ctx.JSON(http.StatusOK, dto.UserLoginResponse{
+		Message: fmt.Sprintf("hello %s! you are now logged in", user.Username),
+		Status:  http.StatusOK,
+		Token:   tokenString,
+	})
+}
+
+// AdminDisableTwoFactorHandler removes 2FA from an account, e.g. when the
+// user lost both the authenticator and the recovery codes
+func (handler *HandlerImpl) AdminDisableTwoFactorHandler(ctx *gin.Context) {
+	target, ok := handler.adminTargetUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := handler.todolistRepository.DisableTwoFactor(target.UserID); err != nil {
+		logrus.Errorf("failed when disable two-factor: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditDisableTwoFactor, entity.AuditUser, target.UserID, nil, nil)
+
+	ctx.JSON(http.StatusOK, dto.AdminActionResponse{
+		Message: "two-factor authentication disabled",
+		Status:  http.StatusOK,
+	})
+}
+
+// verifySecondFactor accepts a TOTP code (each time step only once) or an
+// unused recovery code.
+func (handler *HandlerImpl) verifySecondFactor(user *entity.Users, code string, recoveryCode string) (bool, error) {
+	if code != "" && user.TOTPSecret != nil {
+		step, ok := security.ValidateTOTP(*user.TOTPSecret, code, time.Now())
+		if !ok {
+			return false, nil
+		}
+
+		return handler.todolistRepository.AdvanceTOTPStep(user.UserID, step)
+	}
+
+	if recoveryCode != "" {
+		return handler.todolistRepository.UseRecoveryCode(user.UserID, security.HashRecoveryCode(recoveryCode))
+	}
+
+	return false, nil
+}
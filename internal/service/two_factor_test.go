@@ -0,0 +1,271 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/security"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func twoFactorUser(t *testing.T) (*entity.Users, string) {
+	secret, err := security.GenerateTOTPSecret()
+	require.NoError(t, err)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	enabledAt := time.Now()
+	return &entity.Users{
+		UserID:             7,
+		Email:              "alwi@mail.com",
+		Password:           string(hash),
+		TOTPSecret:         &secret,
+		TwoFactorEnabledAt: &enabledAt,
+	}, secret
+}
+
+func TestSetupTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("SetTOTPSecret", int64(7), mock.AnythingOfType("string")).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo, WithConfig(&config.Config{TOTPIssuer: "Todolist"}))
+
+	router := gin.New()
+	router.POST("/me/2fa/setup", withUser(&entity.Users{UserID: 7, Email: "alwi@mail.com"}), handler.SetupTwoFactorHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/me/2fa/setup", nil)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var result dto.TwoFactorSetupResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotEmpty(t, result.Secret)
+	assert.Contains(t, result.OTPAuthURI, "otpauth://totp/Todolist:alwi@mail.com")
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user, secret := twoFactorUser(t)
+	user.TwoFactorEnabledAt = nil
+
+	code, err := security.TOTPCode(secret, time.Now())
+	require.NoError(t, err)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("AdvanceTOTPStep", int64(7), mock.AnythingOfType("int64")).Return(true, nil)
+	mockRepo.On("EnableTwoFactor", int64(7), mock.MatchedBy(func(hashes []string) bool {
+		return len(hashes) == recoveryCodeCount
+	})).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/me/2fa/confirm", withUser(user), handler.ConfirmTwoFactorHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/me/2fa/confirm", strings.NewReader(`{"code": "`+code+`"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var result dto.TwoFactorConfirmResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Len(t, result.RecoveryCodes, recoveryCodeCount)
+}
+
+func TestLoginWithTwoFactorReturnsChallenge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user, _ := twoFactorUser(t)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("FindUserByEmail", "alwi@mail.com").Return(user, nil)
+	var challengeID string
+	mockRepo.On("CreateTwoFactorChallenge", mock.MatchedBy(func(challenge *entity.TwoFactorChallenges) bool {
+		return challenge.UserID == 7 && len(challenge.JTI) == 64 && challenge.ExpiresAt.After(time.Now())
+	})).Run(func(args mock.Arguments) {
+		challengeID = args.Get(0).(*entity.TwoFactorChallenges).JTI
+	}).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.POST("/login", handler.LoginHandler)
+
+	req, err := http.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email": "alwi@mail.com", "password": "secret"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	var result dto.TwoFactorChallengeResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.True(t, result.TwoFactorRequired)
+	assert.NotEmpty(t, result.ChallengeToken)
+
+	// the challenge token must not work as an access token
+	_, err = config.ParseJWTToken(result.ChallengeToken)
+	assert.Error(t, err)
+
+	claims, err := config.ParseChallengeToken(result.ChallengeToken)
+	require.NoError(t, err)
+	assert.Equal(t, challengeID, claims.Id)
+}
+
+func TestTableDrivenLoginTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user, secret := twoFactorUser(t)
+
+	challengeToken, err := config.CreateChallengeToken(user, "challenge-1")
+	require.NoError(t, err)
+
+	accessToken, err := config.CreateJWTToken(user, "")
+	require.NoError(t, err)
+
+	code, err := security.TOTPCode(secret, time.Now())
+	require.NoError(t, err)
+
+	testCase := []struct {
+		name           string
+		bodyRequest    string
+		mock           func(m *mocks.Repository)
+		expectedStatus int
+	}{
+		{
+			name:           "access token is not a challenge",
+			bodyRequest:    `{"challenge_token": "` + accessToken + `", "code": "` + code + `"}`,
+			mock:           func(m *mocks.Repository) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "wrong code",
+			bodyRequest: `{"challenge_token": "` + challengeToken + `", "code": "000000"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("ClaimTwoFactorAttempt", "challenge-1", maxTwoFactorAttempts).Return(true, nil)
+				m.On("FindUserByID", int64(7)).Return(user, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "locked or used challenge",
+			bodyRequest: `{"challenge_token": "` + challengeToken + `", "code": "` + code + `"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("ClaimTwoFactorAttempt", "challenge-1", maxTwoFactorAttempts).Return(false, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "replayed code",
+			bodyRequest: `{"challenge_token": "` + challengeToken + `", "code": "` + code + `"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("ClaimTwoFactorAttempt", "challenge-1", maxTwoFactorAttempts).Return(true, nil)
+				m.On("FindUserByID", int64(7)).Return(user, nil)
+				m.On("AdvanceTOTPStep", int64(7), mock.AnythingOfType("int64")).Return(false, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "challenge used concurrently",
+			bodyRequest: `{"challenge_token": "` + challengeToken + `", "recovery_code": "abcde-fghij"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("ClaimTwoFactorAttempt", "challenge-1", maxTwoFactorAttempts).Return(true, nil)
+				m.On("FindUserByID", int64(7)).Return(user, nil)
+				m.On("UseRecoveryCode", int64(7), security.HashRecoveryCode("abcde-fghij")).Return(true, nil)
+				m.On("ConsumeTwoFactorChallenge", "challenge-1").Return(false, nil)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "success with code",
+			bodyRequest: `{"challenge_token": "` + challengeToken + `", "code": "` + code + `"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("ClaimTwoFactorAttempt", "challenge-1", maxTwoFactorAttempts).Return(true, nil)
+				m.On("FindUserByID", int64(7)).Return(user, nil)
+				m.On("AdvanceTOTPStep", int64(7), mock.AnythingOfType("int64")).Return(true, nil)
+				m.On("ConsumeTwoFactorChallenge", "challenge-1").Return(true, nil)
+				m.On("CreateSession", mock.AnythingOfType("*entity.Sessions")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:        "success with recovery code",
+			bodyRequest: `{"challenge_token": "` + challengeToken + `", "recovery_code": "abcde-fghij"}`,
+			mock: func(m *mocks.Repository) {
+				m.On("ClaimTwoFactorAttempt", "challenge-1", maxTwoFactorAttempts).Return(true, nil)
+				m.On("FindUserByID", int64(7)).Return(user, nil)
+				m.On("UseRecoveryCode", int64(7), security.HashRecoveryCode("abcde-fghij")).Return(true, nil)
+				m.On("ConsumeTwoFactorChallenge", "challenge-1").Return(true, nil)
+				m.On("CreateSession", mock.AnythingOfType("*entity.Sessions")).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, test := range testCase {
+		t.Run(test.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			test.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo)
+
+			router := gin.New()
+			router.POST("/login/2fa", handler.LoginTwoFactorHandler)
+
+			req, err := http.NewRequest(http.MethodPost, "/login/2fa", strings.NewReader(test.bodyRequest))
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			assert.Equal(t, test.expectedStatus, recorder.Code)
+		})
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	user, _ := twoFactorUser(t)
+
+	mockRepo := mocks.NewRepository(t)
+	mockRepo.On("UseRecoveryCode", int64(7), security.HashRecoveryCode("abcde-fghij")).Return(true, nil)
+	mockRepo.On("DisableTwoFactor", int64(7)).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo)
+
+	router := gin.New()
+	router.DELETE("/me/2fa", withUser(user), handler.DisableTwoFactorHandler)
+
+	req, err := http.NewRequest(http.MethodDelete, "/me/2fa", strings.NewReader(`{"password": "secret", "recovery_code": "abcde-fghij"}`))
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"todolist_gin_gorm/internal/audit"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// errAlreadyUndone aborts an undo that lost the race against another one
+var errAlreadyUndone = errors.New("operation was already undone")
+
+// undoIgnore are todo fields whose changes don't stop an undo, reminders
+// being delivered isn't the todo being modified
+var undoIgnore = append([]string{"reminded_at"}, todoAuditIgnore...)
+
+// UndoHandler reverts an update or delete of a todo, named by the
+// operation_id its response returned, within the undo window. A deleted todo
+// comes back with its subtasks, tags and comments, its attachments are gone
+// for good. An update is only undone while the todo is still as the update
+// left it; the subtasks it closed and a next occurrence it created are kept.
+func (handler *HandlerImpl) UndoHandler(ctx *gin.Context) {
+	operationID, err := strconv.ParseInt(ctx.Param("operationId"), 10, 64)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+			Message: "invalid operation id",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	operation, err := handler.todolistRepository.FindUndoOperation(ctx.GetInt64("user_id"), operationID)
+	if err != nil {
+		logrus.Errorf("failed when get undo operation: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	if operation == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, dto.ErrorResponse{
+			Message: "operation not found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	if operation.UndoneAt != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+			Message: errAlreadyUndone.Error(),
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	now := time.Now()
+	if now.After(operation.ExpiresAt) {
+		ctx.AbortWithStatusJSON(http.StatusGone, dto.ErrorResponse{
+			Message: "the operation can no longer be undone",
+			Status:  http.StatusGone,
+		})
+		return
+	}
+
+	snapshot := new(entity.UndoSnapshot)
+	if err := json.Unmarshal(operation.Snapshot, snapshot); err != nil || len(snapshot.Todos) == 0 {
+		logrus.Errorf("failed when read undo snapshot %d: %v", operation.OperationID, err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	// the todo is checked and restored in one transaction, so it can't change
+	// in between
+	var current *entity.Todos
+	err = handler.todolistRepository.Transaction(func(tx repository.Repository) error {
+		var err error
+		if current, err = tx.GetID(operation.TodoID); err != nil {
+			return err
+		}
+
+		// the user may have lost their share since
+		target, required := current, entity.ShareEditor
+		if operation.Action == entity.AuditDelete {
+			target, required = &snapshot.Todos[0], entity.ShareOwner
+		}
+		if target != nil {
+			role, err := todoRole(tx, ctx.GetInt64("user_id"), target)
+			if err != nil {
+				return err
+			}
+			if !entity.ShareRoleAllows(role, required) {
+				return &operationError{status: http.StatusForbidden, message: "your role on this todo does not allow it"}
+			}
+		}
+
+		if message := undoConflict(tx, operation, snapshot, current); message != "" {
+			return &operationError{status: http.StatusConflict, message: message}
+		}
+
+		claimed, err := tx.ClaimUndoOperation(operation.OperationID, now)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return &operationError{status: http.StatusConflict, message: errAlreadyUndone.Error()}
+		}
+
+		return tx.RestoreTodos(snapshot)
+	})
+	if err != nil {
+		handler.abortOperation(ctx, err)
+		return
+	}
+
+	restored, err := handler.todolistRepository.GetID(operation.TodoID)
+	if err != nil || restored == nil {
+		logrus.Errorf("failed when get restored todolist: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, dto.ErrorResponse{
+			Message: "internal server error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	handler.audit(ctx, entity.AuditUndo, entity.AuditTodo, restored.Id, current, restored)
+
+	logrus.Info(http.StatusOK, "undo operation successfully")
+	ctx.JSON(http.StatusOK, dto.TodolistResponseGetID{
+		Message: "undo " + operation.Action + " todolist successfully",
+		Status:  http.StatusOK,
+		Data:    *restored,
+	})
+}
+
+// undoConflict explains why the todo can't be restored any more, it is empty
+// when it can.
+func undoConflict(repo repository.Repository, operation *entity.UndoOperations, snapshot *entity.UndoSnapshot, current *entity.Todos) string {
+	if operation.Action == entity.AuditDelete {
+		if current != nil {
+			return "todo exists again, it can't be restored"
+		}
+
+		parentID := snapshot.Todos[0].ParentID
+		if parentID == nil {
+			return ""
+		}
+
+		parent, err := repo.GetID(*parentID)
+		if err != nil {
+			logrus.Errorf("failed when get todolist by id: %v", err)
+		}
+		if err != nil || parent == nil {
+			return "the parent todo no longer exists"
+		}
+		return ""
+	}
+
+	if current == nil {
+		return "todo was deleted since, it can't be undone"
+	}
+
+	result := new(entity.Todos)
+	if err := json.Unmarshal(operation.Result, result); err != nil {
+		return "todo was modified since, it can't be undone"
+	}
+
+	changes, err := audit.Diff(result, current, undoIgnore...)
+	if err != nil || changes != nil {
+		return "todo was modified since, it can't be undone"
+	}
+
+	return ""
+}
+
+// todoSnapshot returns what a delete of the todo would lose, nil when undo
+// is turned off.
+func (handler *HandlerImpl) todoSnapshot(repo repository.Repository, todoID int64) (*entity.UndoSnapshot, error) {
+	if handler.cfg.UndoWindow <= 0 {
+		return nil, nil
+	}
+
+	return repo.TodoSnapshot(todoID)
+}
+
+// updateSnapshot returns what an update of the todo overwrites, with its
+// subtasks when the update changes them too. It is nil when undo is turned
+// off.
+func (handler *HandlerImpl) updateSnapshot(repo repository.Repository, todo *entity.Todos, subtasks bool) (*entity.UndoSnapshot, error) {
+	if handler.cfg.UndoWindow <= 0 {
+		return nil, nil
+	}
+
+	if !subtasks {
+		return &entity.UndoSnapshot{Todos: []entity.Todos{*todo}}, nil
+	}
+
+	snapshot, err := repo.TodoSnapshot(todo.Id)
+	if err != nil || snapshot == nil {
+		return nil, err
+	}
+
+	// an update leaves tags, comments and shares alone, undoing it must not
+	// bring back the ones removed since
+	return &entity.UndoSnapshot{Todos: snapshot.Todos}, nil
+}
+
+// recordUndo stores an update or delete of a todo for the undo window and
+// returns its operation id. After an update the todo is read back, undoing
+// is refused once it changes from that. It returns 0 when undo is turned off
+// or the operation couldn't be stored, the change itself already happened.
+func (handler *HandlerImpl) recordUndo(ctx *gin.Context, action string, todoID int64, snapshot *entity.UndoSnapshot) int64 {
+	if handler.cfg.UndoWindow <= 0 || snapshot == nil {
+		return 0
+	}
+
+	operation := &entity.UndoOperations{
+		UserID:    ctx.GetInt64("user_id"),
+		Action:    action,
+		TodoID:    todoID,
+		ExpiresAt: time.Now().Add(handler.cfg.UndoWindow),
+	}
+
+	var err error
+	if operation.Snapshot, err = json.Marshal(snapshot); err != nil {
+		logrus.Errorf("failed to encode undo snapshot: %v", err)
+		return 0
+	}
+
+	if action == entity.AuditUpdate {
+		result, err := handler.todolistRepository.GetID(todoID)
+		if err != nil || result == nil {
+			logrus.Errorf("failed when get updated todolist: %v", err)
+			return 0
+		}
+
+		if operation.Result, err = json.Marshal(result); err != nil {
+			logrus.Errorf("failed to encode undo result: %v", err)
+			return 0
+		}
+	}
+
+	if err := handler.todolistRepository.CreateUndoOperation(operation); err != nil {
+		logrus.Errorf("failed to record undo operation: %v", err)
+		return 0
+	}
+
+	return operation.OperationID
+}
@@ -0,0 +1,476 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todolist_gin_gorm/internal/config"
+	"todolist_gin_gorm/internal/model/dto"
+	"todolist_gin_gorm/internal/model/entity"
+	"todolist_gin_gorm/mocks"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var undoConfig = &config.Config{UndoWindow: 10 * time.Minute}
+
+func undoOperation(t *testing.T, action string, snapshot []entity.Todos, result *entity.Todos) *entity.UndoOperations {
+	operation := &entity.UndoOperations{OperationID: 5, UserID: 7, Action: action, TodoID: 1, ExpiresAt: time.Now().Add(time.Minute)}
+
+	var err error
+	operation.Snapshot, err = json.Marshal(entity.UndoSnapshot{Todos: snapshot})
+	require.NoError(t, err)
+	if result != nil {
+		operation.Result, err = json.Marshal(result)
+		require.NoError(t, err)
+	}
+
+	return operation
+}
+
+func TestDeleteTodolistReturnsOperationID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	existing := &entity.Todos{Id: 1, UserID: 7, Title: "sholat", Description: "sholat subuh"}
+	snapshot := &entity.UndoSnapshot{Todos: []entity.Todos{*existing, {Id: 2, UserID: 7, ParentID: &existing.Id, Title: "wudhu"}}}
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(existing, entity.ShareOwner, nil)
+	mockRepo.On("AttachmentKeys", int64(1)).Return(nil, nil)
+	mockRepo.On("TodoSnapshot", int64(1)).Return(snapshot, nil)
+	mockRepo.On("Delete", int64(1)).Return(int64(1), nil)
+	mockRepo.On("CreateUndoOperation", mock.MatchedBy(func(operation *entity.UndoOperations) bool {
+		var stored entity.UndoSnapshot
+		return operation.UserID == 7 && operation.Action == entity.AuditDelete && operation.TodoID == 1 &&
+			operation.ExpiresAt.After(time.Now().Add(9*time.Minute)) &&
+			json.Unmarshal(operation.Snapshot, &stored) == nil && len(stored.Todos) == 2
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.UndoOperations).OperationID = 5
+	}).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo, WithConfig(undoConfig))
+
+	router := gin.New()
+	router.DELETE("/delete_todolist/:todolistId", withUser(&entity.Users{UserID: 7}), handler.DeleteHandlerTodolist)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/delete_todolist/1", nil))
+
+	var result dto.TodolistResponseDelete
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, int64(5), result.OperationID)
+}
+
+func TestUpdateTodolistRecordsResult(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	existing := &entity.Todos{Id: 1, UserID: 7, Title: "sholat", Description: "sholat subuh"}
+	updated := &entity.Todos{Id: 1, UserID: 7, Title: "sholat tahajud", Description: "sholat subuh"}
+
+	mockRepo := mocks.NewRepository(t)
+	expectAudit(mockRepo)
+	mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(existing, entity.ShareOwner, nil)
+	mockRepo.On("Update", int64(1), mock.Anything).Return(updated, nil)
+	mockRepo.On("GetID", int64(1)).Return(updated, nil).Once()
+	mockRepo.On("CreateUndoOperation", mock.MatchedBy(func(operation *entity.UndoOperations) bool {
+		return operation.Action == entity.AuditUpdate &&
+			strings.Contains(string(operation.Snapshot), `"title":"sholat"`) &&
+			strings.Contains(string(operation.Result), `"title":"sholat tahajud"`)
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*entity.UndoOperations).OperationID = 6
+	}).Return(nil)
+
+	handler := NewHandlerImpl(mockRepo, WithConfig(undoConfig))
+
+	router := gin.New()
+	router.PUT("/update_todolist/:todolistId", withUser(&entity.Users{UserID: 7}), handler.UpdateHandlerTodolist)
+
+	body := `{"title": "sholat tahajud", "description": "sholat subuh"}`
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, "/update_todolist/1", strings.NewReader(body)))
+
+	var result dto.TodolistResponseUpdate
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, int64(6), result.OperationID)
+}
+
+func TestTableDrivenChangesRecordUndo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	dueAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	inbox, work := int64(2), int64(3)
+	todo := entity.Todos{Id: 1, UserID: 7, ListID: &inbox, Title: "sholat", Status: entity.StatusTodo, Position: "i0000001", DueAt: &dueAt, RecurrenceRule: "FREQ=DAILY"}
+	subtask := entity.Todos{Id: 2, UserID: 7, ListID: &inbox, ParentID: &todo.Id, Title: "wudhu"}
+	tree := &entity.UndoSnapshot{
+		Todos:    []entity.Todos{todo, subtask},
+		Comments: []entity.Comments{{CommentID: 4, TodoID: 1, Body: "jangan lupa"}},
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		path          string
+		body          string
+		mock          func(mockRepo *mocks.Repository)
+		snapshotTodos int
+	}{
+		{
+			name:   "toggle closes the subtasks",
+			method: http.MethodPost,
+			path:   "/todos/1/toggle",
+			mock: func(mockRepo *mocks.Repository) {
+				runTransactions(mockRepo)
+				mockRepo.On("TodoSnapshot", int64(1)).Return(tree, nil)
+				mockRepo.On("Update", int64(1), mock.Anything).Return(&entity.Todos{}, nil)
+				mockRepo.On("CloseSubtasks", int64(1), entity.StatusDone, mock.AnythingOfType("time.Time")).Return(nil)
+				mockRepo.On("CreateNextOccurrence", mock.Anything, int64(1)).Return(nil)
+			},
+			snapshotTodos: 2,
+		},
+		{
+			name:   "skip occurrence",
+			method: http.MethodPost,
+			path:   "/todos/1/recurrence/skip",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("Update", int64(1), mock.Anything).Return(&entity.Todos{}, nil)
+			},
+			snapshotTodos: 1,
+		},
+		{
+			name:   "stop recurrence",
+			method: http.MethodDelete,
+			path:   "/todos/1/recurrence",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("Update", int64(1), mock.Anything).Return(&entity.Todos{}, nil)
+			},
+			snapshotTodos: 1,
+		},
+		{
+			name:   "reorder subtasks",
+			method: http.MethodPut,
+			path:   "/todos/1/subtasks/order",
+			body:   `{"order": [2]}`,
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("GetSubtasks", int64(1)).Return([]entity.Todos{subtask}, nil)
+				mockRepo.On("ReorderSubtasks", int64(1), []int64{2}).Return(nil)
+			},
+			snapshotTodos: 2,
+		},
+		{
+			name:   "move to another list",
+			method: http.MethodPut,
+			path:   "/todos/1/list",
+			body:   `{"list_id": 3}`,
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindList", int64(7), work).Return(&entity.Lists{ListID: work, UserID: 7}, nil)
+				mockRepo.On("LastPosition", &work).Return("", nil)
+				runTransactions(mockRepo)
+				mockRepo.On("TodoSnapshot", int64(1)).Return(tree, nil)
+				mockRepo.On("Update", int64(1), mock.Anything).Return(&entity.Todos{}, nil)
+				mockRepo.On("MoveSubtasks", int64(1), &work).Return(nil)
+			},
+			snapshotTodos: 2,
+		},
+		{
+			name:   "reposition next to a todo of another list",
+			method: http.MethodPost,
+			path:   "/todos/1/move",
+			body:   `{"after": 5}`,
+			mock: func(mockRepo *mocks.Repository) {
+				anchor := &entity.Todos{Id: 5, UserID: 7, ListID: &work, Position: "i0000001"}
+				mockRepo.On("GetID", int64(5)).Return(anchor, nil)
+				mockRepo.On("FindList", int64(7), work).Return(&entity.Lists{ListID: work, UserID: 7}, nil)
+				mockRepo.On("AdjacentPosition", anchor, true).Return("", nil)
+				runTransactions(mockRepo)
+				mockRepo.On("TodoSnapshot", int64(1)).Return(tree, nil)
+				mockRepo.On("Update", int64(1), mock.Anything).Return(&entity.Todos{}, nil)
+				mockRepo.On("MoveSubtasks", int64(1), &work).Return(nil)
+			},
+			snapshotTodos: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			current := todo
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			mockRepo.On("FindTodoForUser", int64(7), int64(1)).Return(&current, entity.ShareOwner, nil)
+			tc.mock(mockRepo)
+			mockRepo.On("GetID", int64(1)).Return(&current, nil)
+			mockRepo.On("CreateUndoOperation", mock.MatchedBy(func(operation *entity.UndoOperations) bool {
+				// only the todos come back, not tags or comments removed since
+				var stored entity.UndoSnapshot
+				return operation.Action == entity.AuditUpdate && operation.TodoID == 1 && operation.Result != nil &&
+					json.Unmarshal(operation.Snapshot, &stored) == nil &&
+					len(stored.Todos) == tc.snapshotTodos && stored.Todos[0].Id == 1 && len(stored.Comments) == 0
+			})).Run(func(args mock.Arguments) {
+				args.Get(0).(*entity.UndoOperations).OperationID = 9
+			}).Return(nil)
+			handler := NewHandlerImpl(mockRepo, WithConfig(undoConfig))
+
+			user := withUser(&entity.Users{UserID: 7})
+			router := gin.New()
+			router.POST("/todos/:todolistId/toggle", user, handler.ToggleTodoHandler)
+			router.POST("/todos/:todolistId/recurrence/skip", user, handler.SkipOccurrenceHandler)
+			router.DELETE("/todos/:todolistId/recurrence", user, handler.StopRecurrenceHandler)
+			router.PUT("/todos/:todolistId/subtasks/order", user, handler.ReorderSubtasksHandler)
+			router.PUT("/todos/:todolistId/list", user, handler.MoveTodoHandler)
+			router.POST("/todos/:todolistId/move", user, handler.RepositionTodoHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+
+			var result dto.TodolistResponseDelete
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.Equal(t, int64(9), result.OperationID)
+		})
+	}
+}
+
+func TestUndoSnapshotKeepsReminderRetry(t *testing.T) {
+	retryAt := time.Date(2026, 10, 19, 9, 5, 0, 0, time.UTC)
+	snapshot := entity.UndoSnapshot{Todos: []entity.Todos{
+		{Id: 1, Title: "sholat", ReminderAttempts: 2, ReminderRetryAt: &retryAt},
+		{Id: 2, Title: "wudhu"},
+	}}
+
+	data, err := json.Marshal(snapshot)
+	require.NoError(t, err)
+
+	var stored entity.UndoSnapshot
+	require.NoError(t, json.Unmarshal(data, &stored))
+
+	require.Len(t, stored.Todos, 2)
+	assert.Equal(t, "sholat", stored.Todos[0].Title)
+	assert.Equal(t, 2, stored.Todos[0].ReminderAttempts)
+	assert.True(t, retryAt.Equal(*stored.Todos[0].ReminderRetryAt))
+	assert.Equal(t, 0, stored.Todos[1].ReminderAttempts)
+	assert.Nil(t, stored.Todos[1].ReminderRetryAt)
+}
+
+func TestTableDrivenUndo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	before := entity.Todos{Id: 1, UserID: 7, Title: "sholat", Description: "sholat subuh"}
+	after := entity.Todos{Id: 1, UserID: 7, Title: "sholat tahajud", Description: "sholat subuh"}
+	remindedAt := time.Now()
+	reminded := after
+	reminded.RemindedAt = &remindedAt
+	modified := after
+	modified.Description = "sholat malam"
+	parentID := int64(9)
+	subtask := entity.Todos{Id: 1, UserID: 7, ParentID: &parentID, Title: "wudhu"}
+	shared := after
+	shared.UserID = 8
+	othersTodo := before
+	othersTodo.UserID = 8
+
+	restore := func(mockRepo *mocks.Repository, claimed bool) {
+		runTransactions(mockRepo)
+		mockRepo.On("ClaimUndoOperation", int64(5), mock.Anything).Return(claimed, nil)
+		if claimed {
+			mockRepo.On("RestoreTodos", mock.MatchedBy(func(snapshot *entity.UndoSnapshot) bool {
+				return snapshot.Todos[0].Id == 1
+			})).Return(nil)
+		}
+	}
+
+	testCases := []struct {
+		name         string
+		path         string
+		mock         func(mockRepo *mocks.Repository)
+		expectedCode int
+		expectedMsg  string
+	}{
+		{
+			name: "undo delete",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditDelete, []entity.Todos{before}, nil), nil)
+				mockRepo.On("GetID", int64(1)).Return(nil, nil).Once()
+				restore(mockRepo, true)
+				mockRepo.On("GetID", int64(1)).Return(&before, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedMsg:  "undo delete todolist successfully",
+		},
+		{
+			name: "undo update after a reminder",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditUpdate, []entity.Todos{before}, &after), nil)
+				mockRepo.On("GetID", int64(1)).Return(&reminded, nil).Once()
+				restore(mockRepo, true)
+				mockRepo.On("GetID", int64(1)).Return(&before, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedMsg:  "undo update todolist successfully",
+		},
+		{
+			name: "undo delete restores shares",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				operation := undoOperation(t, entity.AuditDelete, nil, nil)
+				var err error
+				operation.Snapshot, err = json.Marshal(entity.UndoSnapshot{
+					Todos:  []entity.Todos{before},
+					Shares: []entity.Shares{{ShareID: 3, OwnerID: 7, UserID: 8, TodoID: &before.Id, Role: entity.ShareEditor}},
+				})
+				require.NoError(t, err)
+
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(operation, nil)
+				mockRepo.On("GetID", int64(1)).Return(nil, nil).Once()
+				runTransactions(mockRepo)
+				mockRepo.On("ClaimUndoOperation", int64(5), mock.Anything).Return(true, nil)
+				mockRepo.On("RestoreTodos", mock.MatchedBy(func(snapshot *entity.UndoSnapshot) bool {
+					return len(snapshot.Shares) == 1 && snapshot.Shares[0].UserID == 8 && snapshot.Shares[0].Role == entity.ShareEditor
+				})).Return(nil)
+				mockRepo.On("GetID", int64(1)).Return(&before, nil).Once()
+			},
+			expectedCode: http.StatusOK,
+			expectedMsg:  "undo delete todolist successfully",
+		},
+		{
+			name: "no longer an editor",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditUpdate, []entity.Todos{before}, &after), nil)
+				runTransactions(mockRepo)
+				mockRepo.On("GetID", int64(1)).Return(&shared, nil)
+				mockRepo.On("FindTodoShareRole", int64(7), &shared).Return(entity.ShareViewer, nil)
+			},
+			expectedCode: http.StatusForbidden,
+			expectedMsg:  "your role on this todo does not allow it",
+		},
+		{
+			name: "delete undone by an editor",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditDelete, []entity.Todos{othersTodo}, nil), nil)
+				runTransactions(mockRepo)
+				mockRepo.On("GetID", int64(1)).Return(nil, nil)
+				mockRepo.On("FindTodoShareRole", int64(7), mock.Anything).Return(entity.ShareEditor, nil)
+			},
+			expectedCode: http.StatusForbidden,
+			expectedMsg:  "your role on this todo does not allow it",
+		},
+		{
+			name: "modified since",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditUpdate, []entity.Todos{before}, &after), nil)
+				runTransactions(mockRepo)
+				mockRepo.On("GetID", int64(1)).Return(&modified, nil)
+			},
+			expectedCode: http.StatusConflict,
+			expectedMsg:  "todo was modified since, it can't be undone",
+		},
+		{
+			name: "deleted since",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditUpdate, []entity.Todos{before}, &after), nil)
+				mockRepo.On("GetID", int64(1)).Return(nil, nil)
+				runTransactions(mockRepo)
+			},
+			expectedCode: http.StatusConflict,
+			expectedMsg:  "todo was deleted since, it can't be undone",
+		},
+		{
+			name: "parent deleted since",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditDelete, []entity.Todos{subtask}, nil), nil)
+				runTransactions(mockRepo)
+				mockRepo.On("GetID", int64(1)).Return(nil, nil)
+				mockRepo.On("GetID", int64(9)).Return(nil, nil)
+			},
+			expectedCode: http.StatusConflict,
+			expectedMsg:  "the parent todo no longer exists",
+		},
+		{
+			name: "already undone",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				operation := undoOperation(t, entity.AuditDelete, []entity.Todos{before}, nil)
+				operation.UndoneAt = &remindedAt
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(operation, nil)
+			},
+			expectedCode: http.StatusConflict,
+			expectedMsg:  "operation was already undone",
+		},
+		{
+			name: "undone meanwhile",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(undoOperation(t, entity.AuditDelete, []entity.Todos{before}, nil), nil)
+				mockRepo.On("GetID", int64(1)).Return(nil, nil)
+				restore(mockRepo, false)
+			},
+			expectedCode: http.StatusConflict,
+			expectedMsg:  "operation was already undone",
+		},
+		{
+			name: "expired",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				operation := undoOperation(t, entity.AuditDelete, []entity.Todos{before}, nil)
+				operation.ExpiresAt = time.Now().Add(-time.Second)
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(operation, nil)
+			},
+			expectedCode: http.StatusGone,
+			expectedMsg:  "the operation can no longer be undone",
+		},
+		{
+			name: "someone else's operation",
+			path: "/undo/5",
+			mock: func(mockRepo *mocks.Repository) {
+				mockRepo.On("FindUndoOperation", int64(7), int64(5)).Return(nil, nil)
+			},
+			expectedCode: http.StatusNotFound,
+			expectedMsg:  "operation not found",
+		},
+		{
+			name:         "invalid id",
+			path:         "/undo/latest",
+			mock:         func(mockRepo *mocks.Repository) {},
+			expectedCode: http.StatusBadRequest,
+			expectedMsg:  "invalid operation id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := mocks.NewRepository(t)
+			expectAudit(mockRepo)
+			tc.mock(mockRepo)
+			handler := NewHandlerImpl(mockRepo, WithConfig(undoConfig))
+
+			router := gin.New()
+			router.POST("/undo/:operationId", withUser(&entity.Users{UserID: 7}), handler.UndoHandler)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, tc.path, nil))
+
+			var result dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+
+			assert.Equal(t, tc.expectedCode, recorder.Code)
+			assert.Equal(t, tc.expectedMsg, result.Message)
+		})
+	}
+}
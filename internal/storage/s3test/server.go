@@ -0,0 +1,145 @@
+// Package s3test is a minimal in-memory stand-in for an S3-compatible
+// service, for tests and local development. It checks the Signature Version 4
+// of every request and supports PUT, GET with ranges, HEAD and DELETE of
+// objects addressed path style.
+package s3test
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+type object struct {
+	body        []byte
+	contentType string
+	modified    time.Time
+}
+
+type Server struct {
+	*httptest.Server
+
+	Bucket          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+
+	mutex   sync.Mutex
+	objects map[string]object
+}
+
+func NewServer(bucket string) *Server {
+	server := &Server{
+		Bucket:          bucket,
+		Region:          "us-east-1",
+		AccessKeyID:     "s3test",
+		SecretAccessKey: "s3test-secret",
+		objects:         map[string]object{},
+	}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serve))
+
+	return server
+}
+
+// Object returns the body stored under key.
+func (server *Server) Object(key string) ([]byte, bool) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	stored, ok := server.objects[key]
+	return stored.body, ok
+}
+
+func (server *Server) serve(w http.ResponseWriter, r *http.Request) {
+	if !server.validSignature(r) {
+		http.Error(w, "SignatureDoesNotMatch", http.StatusForbidden)
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != server.Bucket || key == "" {
+		http.Error(w, "NoSuchBucket", http.StatusNotFound)
+		return
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil || int64(len(body)) != r.ContentLength {
+			http.Error(w, "IncompleteBody", http.StatusBadRequest)
+			return
+		}
+		server.objects[key] = object{body: body, contentType: r.Header.Get("Content-Type"), modified: time.Now()}
+	case http.MethodGet, http.MethodHead:
+		stored, ok := server.objects[key]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", stored.contentType)
+		http.ServeContent(w, r, key, stored.modified, bytes.NewReader(stored.body))
+	case http.MethodDelete:
+		delete(server.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "MethodNotAllowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// validSignature recomputes the signature of the request from the headers
+// it says it signed.
+func (server *Server) validSignature(r *http.Request) bool {
+	authorization := r.Header.Get("Authorization")
+	fields := map[string]string{}
+	for _, field := range strings.Split(strings.TrimPrefix(authorization, "AWS4-HMAC-SHA256 "), ", ") {
+		name, value, _ := strings.Cut(field, "=")
+		fields[name] = value
+	}
+
+	accessKeyID, scope, _ := strings.Cut(fields["Credential"], "/")
+	scopeParts := strings.Split(scope, "/")
+	if accessKeyID != server.AccessKeyID || len(scopeParts) != 4 || scopeParts[1] != server.Region {
+		return false
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range strings.Split(fields["SignedHeaders"], ";") {
+		value := r.Header.Get(name)
+		if name == "host" {
+			value = r.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		r.URL.RawQuery,
+		canonicalHeaders.String(),
+		fields["SignedHeaders"],
+		r.Header.Get("X-Amz-Content-Sha256"),
+	}, "\n")
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + r.Header.Get("X-Amz-Date") + "\n" + scope + "\n" + hex.EncodeToString(hashedRequest[:])
+
+	key := []byte("AWS4" + server.SecretAccessKey)
+	for _, part := range scopeParts {
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(part))
+		key = mac.Sum(nil)
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(stringToSign))
+
+	return hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(fields["Signature"]))
+}
@@ -0,0 +1,43 @@
+// Package storage keeps the files attached to todos, on the local disk or in
+// an S3-compatible bucket.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"todolist_gin_gorm/internal/config"
+)
+
+// ErrNotFound is returned when no object is stored under a key.
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage stores objects under slash separated keys.
+type Storage interface {
+	// Put stores size bytes read from body under key.
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Open returns the object for reading. It can seek, so downloads can
+	// be served in ranges.
+	Open(ctx context.Context, key string) (io.ReadSeekCloser, error)
+	// Delete removes the object, a missing one is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// New returns the storage selected by cfg.StorageDriver.
+func New(cfg *config.Config) (Storage, error) {
+	switch cfg.StorageDriver {
+	case "local", "":
+		return NewLocal(cfg.StorageLocalDir), nil
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:        cfg.S3Endpoint,
+			Region:          cfg.S3Region,
+			Bucket:          cfg.S3Bucket,
+			AccessKeyID:     cfg.S3AccessKeyID,
+			SecretAccessKey: cfg.S3SecretAccessKey,
+		}, nil)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.StorageDriver)
+	}
+}
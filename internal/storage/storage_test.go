@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"todolist_gin_gorm/internal/storage/s3test"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exercise runs the same checks against every backend
+func exercise(t *testing.T, storage Storage) {
+	ctx := context.Background()
+	body := "hello attachment"
+
+	require.NoError(t, storage.Put(ctx, "todos/1/note", strings.NewReader(body), int64(len(body)), "text/plain"))
+
+	object, err := storage.Open(ctx, "todos/1/note")
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(object)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(content))
+
+	size, err := object.Seek(0, io.SeekEnd)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(body)), size)
+
+	_, err = object.Seek(6, io.SeekStart)
+	require.NoError(t, err)
+	part := make([]byte, 4)
+	_, err = io.ReadFull(object, part)
+	require.NoError(t, err)
+	assert.Equal(t, "atta", string(part))
+	require.NoError(t, object.Close())
+
+	require.NoError(t, storage.Delete(ctx, "todos/1/note"))
+	require.NoError(t, storage.Delete(ctx, "todos/1/note"))
+
+	_, err = storage.Open(ctx, "todos/1/note")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocal(t *testing.T) {
+	exercise(t, NewLocal(t.TempDir()))
+}
+
+func TestLocalRefusesEscapingKeys(t *testing.T) {
+	local := NewLocal(t.TempDir())
+
+	for _, key := range []string{"../secret", "/etc/passwd", "todos/../../x", ""} {
+		err := local.Put(context.Background(), key, strings.NewReader("x"), 1, "")
+		assert.Error(t, err, "key %q", key)
+	}
+}
+
+func TestLocalRefusesShortBody(t *testing.T) {
+	local := NewLocal(t.TempDir())
+
+	err := local.Put(context.Background(), "todos/1/short", strings.NewReader("abc"), 10, "")
+	require.Error(t, err)
+
+	_, err = local.Open(context.Background(), "todos/1/short")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestS3(t *testing.T) {
+	server := s3test.NewServer("attachments")
+	defer server.Close()
+
+	s3, err := NewS3(S3Config{
+		Endpoint:        server.URL,
+		Region:          server.Region,
+		Bucket:          server.Bucket,
+		AccessKeyID:     server.AccessKeyID,
+		SecretAccessKey: server.SecretAccessKey,
+	}, server.Client())
+	require.NoError(t, err)
+
+	exercise(t, s3)
+}
+
+func TestS3KeysAreEscaped(t *testing.T) {
+	server := s3test.NewServer("attachments")
+	defer server.Close()
+
+	s3, err := NewS3(S3Config{
+		Endpoint:        server.URL,
+		Bucket:          server.Bucket,
+		AccessKeyID:     server.AccessKeyID,
+		SecretAccessKey: server.SecretAccessKey,
+	}, server.Client())
+	require.NoError(t, err)
+
+	require.NoError(t, s3.Put(context.Background(), "todos/1/a b+c", strings.NewReader("x"), 1, ""))
+
+	stored, ok := server.Object("todos/1/a b+c")
+	require.True(t, ok)
+	assert.Equal(t, "x", string(stored))
+}
+
+func TestS3WrongSecret(t *testing.T) {
+	server := s3test.NewServer("attachments")
+	defer server.Close()
+
+	s3, err := NewS3(S3Config{
+		Endpoint:        server.URL,
+		Bucket:          server.Bucket,
+		AccessKeyID:     server.AccessKeyID,
+		SecretAccessKey: "wrong",
+	}, server.Client())
+	require.NoError(t, err)
+
+	err = s3.Put(context.Background(), "todos/1/x", strings.NewReader("x"), 1, "")
+	assert.ErrorContains(t, err, "403")
+}
@@ -11,6 +11,7 @@ import (
 	"testing"
 	"todolist_gin_gorm/cmd/router"
 	"todolist_gin_gorm/internal/database"
+	"todolist_gin_gorm/internal/model/entity"
 	"todolist_gin_gorm/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -39,7 +40,7 @@ func setupRouter(DB *gorm.DB) *gin.Engine {
 	todolistHandler := service.NewHandlerImpl(todolistRepository)
 
 	// initialize router
-	routeBuilder := router.NewRouteBuilder(todolistHandler)
+	routeBuilder := router.NewRouteBuilder(todolistHandler, todolistRepository)
 	routerInit := routeBuilder.RouteInit()
 
 	return routerInit
@@ -130,7 +131,8 @@ func TestUpdateTodolistSuccess(t *testing.T) {
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist, _ := todolistRepository.Create("sholat", "sholat tahajud")
+	todolist := &entity.Todos{Title: "sholat", Description: "sholat tahajud"}
+	_ = todolistRepository.Create(todolist)
 
 	tx.Commit()
 
@@ -173,7 +175,8 @@ func TestUpdateTodolistFailedBadRequest(t *testing.T) {
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist, _ := todolistRepository.Create("sholat", "sholat tahajud")
+	todolist := &entity.Todos{Title: "sholat", Description: "sholat tahajud"}
+	_ = todolistRepository.Create(todolist)
 
 	tx.Commit()
 
@@ -213,7 +216,8 @@ func TestGetTodolistByIDSuccess(t *testing.T) {
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist, _ := todolistRepository.Create("sholat", "sholat tahajud")
+	todolist := &entity.Todos{Title: "sholat", Description: "sholat tahajud"}
+	_ = todolistRepository.Create(todolist)
 
 	tx.Commit()
 
@@ -287,7 +291,8 @@ func TestDeleteTodolistSuccess(t *testing.T) {
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist, _ := todolistRepository.Create("sholat", "sholat tahajud")
+	todolist := &entity.Todos{Title: "sholat", Description: "sholat tahajud"}
+	_ = todolistRepository.Create(todolist)
 
 	tx.Commit()
 
@@ -358,8 +363,10 @@ func TestListTodolistsSuccess(t *testing.T) {
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist_satu, _ := todolistRepository.Create("sholat", "sholat tahajud")
-	todolist_dua, _ := todolistRepository.Create("qurban", "qurban sapi tahun depan amiinn")
+	todolist_satu := &entity.Todos{Title: "sholat", Description: "sholat tahajud"}
+	_ = todolistRepository.Create(todolist_satu)
+	todolist_dua := &entity.Todos{Title: "qurban", Description: "qurban sapi tahun depan amiinn"}
+	_ = todolistRepository.Create(todolist_dua)
 
 	tx.Commit()
 
@@ -3,7 +3,10 @@
 package mocks
 
 import (
+	time "time"
+	dto "todolist_gin_gorm/internal/model/dto"
 	entity "todolist_gin_gorm/internal/model/entity"
+	repository "todolist_gin_gorm/internal/repository"
 
 	mock "github.com/stretchr/testify/mock"
 )
@@ -13,25 +16,1538 @@ type Repository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: title, description
-func (_m *Repository) Create(title string, description string) (*entity.Todos, error) {
-	ret := _m.Called(title, description)
+// AdjacentPosition provides a mock function with given fields: todo, after
+func (_m *Repository) AdjacentPosition(todo *entity.Todos, after bool) (string, error) {
+	ret := _m.Called(todo, after)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*entity.Todos, bool) (string, error)); ok {
+		return rf(todo, after)
+	}
+	if rf, ok := ret.Get(0).(func(*entity.Todos, bool) string); ok {
+		r0 = rf(todo, after)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*entity.Todos, bool) error); ok {
+		r1 = rf(todo, after)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AdvanceTOTPStep provides a mock function with given fields: userID, step
+func (_m *Repository) AdvanceTOTPStep(userID int64, step int64) (bool, error) {
+	ret := _m.Called(userID, step)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (bool, error)); ok {
+		return rf(userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) bool); ok {
+		r0 = rf(userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AttachTag provides a mock function with given fields: todoID, tagID
+func (_m *Repository) AttachTag(todoID int64, tagID int64) error {
+	ret := _m.Called(todoID, tagID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
+		r0 = rf(todoID, tagID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AttachmentKeys provides a mock function with given fields: todoID
+func (_m *Repository) AttachmentKeys(todoID int64) ([]string, error) {
+	ret := _m.Called(todoID)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]string, error)); ok {
+		return rf(todoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []string); ok {
+		r0 = rf(todoID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(todoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimReminder provides a mock function with given fields: todoID, remindAt
+func (_m *Repository) ClaimReminder(todoID int64, remindAt time.Time) (bool, error) {
+	ret := _m.Called(todoID, remindAt)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time) (bool, error)); ok {
+		return rf(todoID, remindAt)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time) bool); ok {
+		r0 = rf(todoID, remindAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time) error); ok {
+		r1 = rf(todoID, remindAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimTwoFactorAttempt provides a mock function with given fields: jti, maxAttempts
+func (_m *Repository) ClaimTwoFactorAttempt(jti string, maxAttempts int) (bool, error) {
+	ret := _m.Called(jti, maxAttempts)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) (bool, error)); ok {
+		return rf(jti, maxAttempts)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) bool); ok {
+		r0 = rf(jti, maxAttempts)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(jti, maxAttempts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ClaimUndoOperation provides a mock function with given fields: operationID, now
+func (_m *Repository) ClaimUndoOperation(operationID int64, now time.Time) (bool, error) {
+	ret := _m.Called(operationID, now)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time) (bool, error)); ok {
+		return rf(operationID, now)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time) bool); ok {
+		r0 = rf(operationID, now)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time) error); ok {
+		r1 = rf(operationID, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CloseSubtasks provides a mock function with given fields: parentID, status, now
+func (_m *Repository) CloseSubtasks(parentID int64, status string, now time.Time) error {
+	ret := _m.Called(parentID, status, now)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string, time.Time) error); ok {
+		r0 = rf(parentID, status, now)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ConsumeTwoFactorChallenge provides a mock function with given fields: jti
+func (_m *Repository) ConsumeTwoFactorChallenge(jti string) (bool, error) {
+	ret := _m.Called(jti)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (bool, error)); ok {
+		return rf(jti)
+	}
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(jti)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: todo
+func (_m *Repository) Create(todo *entity.Todos) error {
+	ret := _m.Called(todo)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Todos) error); ok {
+		r0 = rf(todo)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateAPIKey provides a mock function with given fields: key
+func (_m *Repository) CreateAPIKey(key *entity.APIKeys) error {
+	ret := _m.Called(key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.APIKeys) error); ok {
+		r0 = rf(key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateAttachment provides a mock function with given fields: attachment
+func (_m *Repository) CreateAttachment(attachment *entity.Attachments) error {
+	ret := _m.Called(attachment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Attachments) error); ok {
+		r0 = rf(attachment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateAuditEvent provides a mock function with given fields: event
+func (_m *Repository) CreateAuditEvent(event *entity.AuditEvents) error {
+	ret := _m.Called(event)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.AuditEvents) error); ok {
+		r0 = rf(event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateComment provides a mock function with given fields: comment
+func (_m *Repository) CreateComment(comment *entity.Comments) error {
+	ret := _m.Called(comment)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Comments) error); ok {
+		r0 = rf(comment)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateEmailVerificationToken provides a mock function with given fields: token
+func (_m *Repository) CreateEmailVerificationToken(token *entity.EmailVerificationTokens) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.EmailVerificationTokens) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateList provides a mock function with given fields: list
+func (_m *Repository) CreateList(list *entity.Lists) error {
+	ret := _m.Called(list)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Lists) error); ok {
+		r0 = rf(list)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateNextOccurrence provides a mock function with given fields: next, previousID
+func (_m *Repository) CreateNextOccurrence(next *entity.Todos, previousID int64) error {
+	ret := _m.Called(next, previousID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Todos, int64) error); ok {
+		r0 = rf(next, previousID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreatePasswordResetToken provides a mock function with given fields: token
+func (_m *Repository) CreatePasswordResetToken(token *entity.PasswordResetTokens) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.PasswordResetTokens) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateSession provides a mock function with given fields: session
+func (_m *Repository) CreateSession(session *entity.Sessions) error {
+	ret := _m.Called(session)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Sessions) error); ok {
+		r0 = rf(session)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTag provides a mock function with given fields: tag
+func (_m *Repository) CreateTag(tag *entity.Tags) error {
+	ret := _m.Called(tag)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Tags) error); ok {
+		r0 = rf(tag)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateTwoFactorChallenge provides a mock function with given fields: challenge
+func (_m *Repository) CreateTwoFactorChallenge(challenge *entity.TwoFactorChallenges) error {
+	ret := _m.Called(challenge)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.TwoFactorChallenges) error); ok {
+		r0 = rf(challenge)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUndoOperation provides a mock function with given fields: operation
+func (_m *Repository) CreateUndoOperation(operation *entity.UndoOperations) error {
+	ret := _m.Called(operation)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.UndoOperations) error); ok {
+		r0 = rf(operation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUser provides a mock function with given fields: user
+func (_m *Repository) CreateUser(user *entity.Users) error {
+	ret := _m.Called(user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Users) error); ok {
+		r0 = rf(user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUserIdentity provides a mock function with given fields: identity
+func (_m *Repository) CreateUserIdentity(identity *entity.UserIdentities) error {
+	ret := _m.Called(identity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.UserIdentities) error); ok {
+		r0 = rf(identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CreateUserWithIdentity provides a mock function with given fields: user, identity
+func (_m *Repository) CreateUserWithIdentity(user *entity.Users, identity *entity.UserIdentities) error {
+	ret := _m.Called(user, identity)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Users, *entity.UserIdentities) error); ok {
+		r0 = rf(user, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: todoID
+func (_m *Repository) Delete(todoID int64) (int64, error) {
+	ret := _m.Called(todoID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(todoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(todoID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(todoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteAttachment provides a mock function with given fields: attachmentID
+func (_m *Repository) DeleteAttachment(attachmentID int64) (int64, error) {
+	ret := _m.Called(attachmentID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(attachmentID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(attachmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteComment provides a mock function with given fields: commentID
+func (_m *Repository) DeleteComment(commentID int64) (int64, error) {
+	ret := _m.Called(commentID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(commentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(commentID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteList provides a mock function with given fields: list
+func (_m *Repository) DeleteList(list *entity.Lists) error {
+	ret := _m.Called(list)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Lists) error); ok {
+		r0 = rf(list)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteShare provides a mock function with given fields: shareID
+func (_m *Repository) DeleteShare(shareID int64) (int64, error) {
+	ret := _m.Called(shareID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(shareID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(shareID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(shareID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteTag provides a mock function with given fields: tagID
+func (_m *Repository) DeleteTag(tagID int64) error {
+	ret := _m.Called(tagID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(tagID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteUser provides a mock function with given fields: userID
+func (_m *Repository) DeleteUser(userID int64) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachTag provides a mock function with given fields: todoID, tagID
+func (_m *Repository) DetachTag(todoID int64, tagID int64) (int64, error) {
+	ret := _m.Called(todoID, tagID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (int64, error)); ok {
+		return rf(todoID, tagID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) int64); ok {
+		r0 = rf(todoID, tagID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(todoID, tagID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableTwoFactor provides a mock function with given fields: userID
+func (_m *Repository) DisableTwoFactor(userID int64) error {
+	ret := _m.Called(userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTwoFactor provides a mock function with given fields: userID, recoveryCodeHashes
+func (_m *Repository) EnableTwoFactor(userID int64, recoveryCodeHashes []string) error {
+	ret := _m.Called(userID, recoveryCodeHashes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, []string) error); ok {
+		r0 = rf(userID, recoveryCodeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ExportTodos provides a mock function with given fields: query, fn
+func (_m *Repository) ExportTodos(query *dto.TodoQuery, fn func([]entity.Todos) error) error {
+	ret := _m.Called(query, fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*dto.TodoQuery, func([]entity.Todos) error) error); ok {
+		r0 = rf(query, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// FindAPIKeyByHash provides a mock function with given fields: keyHash
+func (_m *Repository) FindAPIKeyByHash(keyHash string) (*entity.APIKeys, error) {
+	ret := _m.Called(keyHash)
+
+	var r0 *entity.APIKeys
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.APIKeys, error)); ok {
+		return rf(keyHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.APIKeys); ok {
+		r0 = rf(keyHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.APIKeys)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(keyHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindAttachment provides a mock function with given fields: attachmentID
+func (_m *Repository) FindAttachment(attachmentID int64) (*entity.Attachments, error) {
+	ret := _m.Called(attachmentID)
+
+	var r0 *entity.Attachments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*entity.Attachments, error)); ok {
+		return rf(attachmentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *entity.Attachments); ok {
+		r0 = rf(attachmentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Attachments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(attachmentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindComment provides a mock function with given fields: commentID
+func (_m *Repository) FindComment(commentID int64) (*entity.Comments, error) {
+	ret := _m.Called(commentID)
+
+	var r0 *entity.Comments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*entity.Comments, error)); ok {
+		return rf(commentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *entity.Comments); ok {
+		r0 = rf(commentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Comments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(commentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindEmailVerificationToken provides a mock function with given fields: tokenHash
+func (_m *Repository) FindEmailVerificationToken(tokenHash string) (*entity.EmailVerificationTokens, error) {
+	ret := _m.Called(tokenHash)
+
+	var r0 *entity.EmailVerificationTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.EmailVerificationTokens, error)); ok {
+		return rf(tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.EmailVerificationTokens); ok {
+		r0 = rf(tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.EmailVerificationTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindList provides a mock function with given fields: userID, listID
+func (_m *Repository) FindList(userID int64, listID int64) (*entity.Lists, error) {
+	ret := _m.Called(userID, listID)
+
+	var r0 *entity.Lists
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.Lists, error)); ok {
+		return rf(userID, listID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) *entity.Lists); ok {
+		r0 = rf(userID, listID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Lists)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, listID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindPasswordResetToken provides a mock function with given fields: tokenHash
+func (_m *Repository) FindPasswordResetToken(tokenHash string) (*entity.PasswordResetTokens, error) {
+	ret := _m.Called(tokenHash)
+
+	var r0 *entity.PasswordResetTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.PasswordResetTokens, error)); ok {
+		return rf(tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.PasswordResetTokens); ok {
+		r0 = rf(tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.PasswordResetTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSessionByJTI provides a mock function with given fields: jti
+func (_m *Repository) FindSessionByJTI(jti string) (*entity.Sessions, error) {
+	ret := _m.Called(jti)
+
+	var r0 *entity.Sessions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.Sessions, error)); ok {
+		return rf(jti)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.Sessions); ok {
+		r0 = rf(jti)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Sessions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(jti)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindShare provides a mock function with given fields: shareID
+func (_m *Repository) FindShare(shareID int64) (*entity.Shares, error) {
+	ret := _m.Called(shareID)
+
+	var r0 *entity.Shares
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*entity.Shares, error)); ok {
+		return rf(shareID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *entity.Shares); ok {
+		r0 = rf(shareID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Shares)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(shareID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindSharedList provides a mock function with given fields: userID, listID
+func (_m *Repository) FindSharedList(userID int64, listID int64) (*entity.Lists, string, error) {
+	ret := _m.Called(userID, listID)
+
+	var r0 *entity.Lists
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.Lists, string, error)); ok {
+		return rf(userID, listID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) *entity.Lists); ok {
+		r0 = rf(userID, listID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Lists)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) string); ok {
+		r1 = rf(userID, listID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, int64) error); ok {
+		r2 = rf(userID, listID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindTag provides a mock function with given fields: userID, tagID
+func (_m *Repository) FindTag(userID int64, tagID int64) (*entity.Tags, error) {
+	ret := _m.Called(userID, tagID)
+
+	var r0 *entity.Tags
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.Tags, error)); ok {
+		return rf(userID, tagID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) *entity.Tags); ok {
+		r0 = rf(userID, tagID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Tags)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, tagID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTodoForUser provides a mock function with given fields: userID, todoID
+func (_m *Repository) FindTodoForUser(userID int64, todoID int64) (*entity.Todos, string, error) {
+	ret := _m.Called(userID, todoID)
+
+	var r0 *entity.Todos
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.Todos, string, error)); ok {
+		return rf(userID, todoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) *entity.Todos); ok {
+		r0 = rf(userID, todoID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Todos)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) string); ok {
+		r1 = rf(userID, todoID)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, int64) error); ok {
+		r2 = rf(userID, todoID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindTodoShareRole provides a mock function with given fields: userID, todo
+func (_m *Repository) FindTodoShareRole(userID int64, todo *entity.Todos) (string, error) {
+	ret := _m.Called(userID, todo)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, *entity.Todos) (string, error)); ok {
+		return rf(userID, todo)
+	}
+	if rf, ok := ret.Get(0).(func(int64, *entity.Todos) string); ok {
+		r0 = rf(userID, todo)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, *entity.Todos) error); ok {
+		r1 = rf(userID, todo)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUndoOperation provides a mock function with given fields: userID, operationID
+func (_m *Repository) FindUndoOperation(userID int64, operationID int64) (*entity.UndoOperations, error) {
+	ret := _m.Called(userID, operationID)
+
+	var r0 *entity.UndoOperations
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.UndoOperations, error)); ok {
+		return rf(userID, operationID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) *entity.UndoOperations); ok {
+		r0 = rf(userID, operationID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UndoOperations)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, operationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserByEmail provides a mock function with given fields: username
+func (_m *Repository) FindUserByEmail(username string) (*entity.Users, error) {
+	ret := _m.Called(username)
+
+	var r0 *entity.Users
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*entity.Users, error)); ok {
+		return rf(username)
+	}
+	if rf, ok := ret.Get(0).(func(string) *entity.Users); ok {
+		r0 = rf(username)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Users)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserByID provides a mock function with given fields: userID
+func (_m *Repository) FindUserByID(userID int64) (*entity.Users, error) {
+	ret := _m.Called(userID)
+
+	var r0 *entity.Users
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*entity.Users, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *entity.Users); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Users)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindUserIdentity provides a mock function with given fields: provider, subject
+func (_m *Repository) FindUserIdentity(provider string, subject string) (*entity.UserIdentities, error) {
+	ret := _m.Called(provider, subject)
+
+	var r0 *entity.UserIdentities
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) (*entity.UserIdentities, error)); ok {
+		return rf(provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) *entity.UserIdentities); ok {
+		r0 = rf(provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.UserIdentities)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: query
+func (_m *Repository) GetAll(query *dto.TodoQuery) ([]entity.Todos, error) {
+	ret := _m.Called(query)
+
+	var r0 []entity.Todos
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*dto.TodoQuery) ([]entity.Todos, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*dto.TodoQuery) []entity.Todos); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Todos)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*dto.TodoQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllByUser provides a mock function with given fields: userID
+func (_m *Repository) GetAllByUser(userID int64) ([]entity.Todos, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.Todos
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.Todos, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.Todos); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Todos)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAttachments provides a mock function with given fields: todoID
+func (_m *Repository) GetAttachments(todoID int64) ([]entity.Attachments, error) {
+	ret := _m.Called(todoID)
+
+	var r0 []entity.Attachments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.Attachments, error)); ok {
+		return rf(todoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.Attachments); ok {
+		r0 = rf(todoID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Attachments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(todoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetComments provides a mock function with given fields: todoID
+func (_m *Repository) GetComments(todoID int64) ([]entity.Comments, error) {
+	ret := _m.Called(todoID)
+
+	var r0 []entity.Comments
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.Comments, error)); ok {
+		return rf(todoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.Comments); ok {
+		r0 = rf(todoID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Comments)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(todoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetID provides a mock function with given fields: todoID
+func (_m *Repository) GetID(todoID int64) (*entity.Todos, error) {
+	ret := _m.Called(todoID)
 
 	var r0 *entity.Todos
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (*entity.Todos, error)); ok {
-		return rf(title, description)
+	if rf, ok := ret.Get(0).(func(int64) (*entity.Todos, error)); ok {
+		return rf(todoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *entity.Todos); ok {
+		r0 = rf(todoID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Todos)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(todoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLists provides a mock function with given fields: userID, includeArchived
+func (_m *Repository) GetLists(userID int64, includeArchived bool) ([]entity.Lists, error) {
+	ret := _m.Called(userID, includeArchived)
+
+	var r0 []entity.Lists
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, bool) ([]entity.Lists, error)); ok {
+		return rf(userID, includeArchived)
+	}
+	if rf, ok := ret.Get(0).(func(int64, bool) []entity.Lists); ok {
+		r0 = rf(userID, includeArchived)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Lists)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, bool) error); ok {
+		r1 = rf(userID, includeArchived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSubtasks provides a mock function with given fields: parentID
+func (_m *Repository) GetSubtasks(parentID int64) ([]entity.Todos, error) {
+	ret := _m.Called(parentID)
+
+	var r0 []entity.Todos
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.Todos, error)); ok {
+		return rf(parentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.Todos); ok {
+		r0 = rf(parentID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Todos)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTags provides a mock function with given fields: userID
+func (_m *Repository) GetTags(userID int64) ([]entity.Tags, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.Tags
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.Tags, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.Tags); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Tags)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportedExternalIDs provides a mock function with given fields: userID, externalIDs
+func (_m *Repository) ImportedExternalIDs(userID int64, externalIDs []string) ([]string, error) {
+	ret := _m.Called(userID, externalIDs)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, []string) ([]string, error)); ok {
+		return rf(userID, externalIDs)
+	}
+	if rf, ok := ret.Get(0).(func(int64, []string) []string); ok {
+		r0 = rf(userID, externalIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, []string) error); ok {
+		r1 = rf(userID, externalIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LastPosition provides a mock function with given fields: listID
+func (_m *Repository) LastPosition(listID *int64) (string, error) {
+	ret := _m.Called(listID)
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*int64) (string, error)); ok {
+		return rf(listID)
+	}
+	if rf, ok := ret.Get(0).(func(*int64) string); ok {
+		r0 = rf(listID)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*int64) error); ok {
+		r1 = rf(listID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAPIKeys provides a mock function with given fields: userID
+func (_m *Repository) ListAPIKeys(userID int64) ([]entity.APIKeys, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.APIKeys
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.APIKeys, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.APIKeys); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.APIKeys)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAuditEvents provides a mock function with given fields: query
+func (_m *Repository) ListAuditEvents(query *dto.AuditQuery) ([]entity.AuditEvents, error) {
+	ret := _m.Called(query)
+
+	var r0 []entity.AuditEvents
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*dto.AuditQuery) ([]entity.AuditEvents, error)); ok {
+		return rf(query)
+	}
+	if rf, ok := ret.Get(0).(func(*dto.AuditQuery) []entity.AuditEvents); ok {
+		r0 = rf(query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.AuditEvents)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*dto.AuditQuery) error); ok {
+		r1 = rf(query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListDueReminders provides a mock function with given fields: now, limit
+func (_m *Repository) ListDueReminders(now time.Time, limit int) ([]entity.Todos, error) {
+	ret := _m.Called(now, limit)
+
+	var r0 []entity.Todos
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time, int) ([]entity.Todos, error)); ok {
+		return rf(now, limit)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time, int) []entity.Todos); ok {
+		r0 = rf(now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Todos)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time, int) error); ok {
+		r1 = rf(now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSessions provides a mock function with given fields: userID
+func (_m *Repository) ListSessions(userID int64) ([]entity.Sessions, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.Sessions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.Sessions, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.Sessions); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Sessions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSharedWithUser provides a mock function with given fields: userID
+func (_m *Repository) ListSharedWithUser(userID int64) ([]entity.Shares, error) {
+	ret := _m.Called(userID)
+
+	var r0 []entity.Shares
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]entity.Shares, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []entity.Shares); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Shares)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListShares provides a mock function with given fields: todoID, listID
+func (_m *Repository) ListShares(todoID *int64, listID *int64) ([]entity.Shares, error) {
+	ret := _m.Called(todoID, listID)
+
+	var r0 []entity.Shares
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*int64, *int64) ([]entity.Shares, error)); ok {
+		return rf(todoID, listID)
+	}
+	if rf, ok := ret.Get(0).(func(*int64, *int64) []entity.Shares); ok {
+		r0 = rf(todoID, listID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Shares)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*int64, *int64) error); ok {
+		r1 = rf(todoID, listID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUsers provides a mock function with given fields: search, limit, offset
+func (_m *Repository) ListUsers(search string, limit int, offset int) ([]entity.Users, int64, error) {
+	ret := _m.Called(search, limit, offset)
+
+	var r0 []entity.Users
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, int, int) ([]entity.Users, int64, error)); ok {
+		return rf(search, limit, offset)
+	}
+	if rf, ok := ret.Get(0).(func(string, int, int) []entity.Users); ok {
+		r0 = rf(search, limit, offset)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Users)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int, int) int64); ok {
+		r1 = rf(search, limit, offset)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, int, int) error); ok {
+		r2 = rf(search, limit, offset)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MoveSubtasks provides a mock function with given fields: parentID, listID
+func (_m *Repository) MoveSubtasks(parentID int64, listID *int64) error {
+	ret := _m.Called(parentID, listID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, *int64) error); ok {
+		r0 = rf(parentID, listID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NextSubtaskOrder provides a mock function with given fields: parentID
+func (_m *Repository) NextSubtaskOrder(parentID int64) (int, error) {
+	ret := _m.Called(parentID)
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int, error)); ok {
+		return rf(parentID)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) *entity.Todos); ok {
-		r0 = rf(title, description)
+	if rf, ok := ret.Get(0).(func(int64) int); ok {
+		r0 = rf(parentID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.Todos)
-		}
+		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(title, description)
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(parentID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -39,13 +1555,13 @@ func (_m *Repository) Create(title string, description string) (*entity.Todos, e
 	return r0, r1
 }
 
-// CreateUser provides a mock function with given fields: user
-func (_m *Repository) CreateUser(user *entity.Users) error {
-	ret := _m.Called(user)
+// ReleaseReminder provides a mock function with given fields: todoID, retryAt
+func (_m *Repository) ReleaseReminder(todoID int64, retryAt time.Time) error {
+	ret := _m.Called(todoID, retryAt)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*entity.Users) error); ok {
-		r0 = rf(user)
+	if rf, ok := ret.Get(0).(func(int64, time.Time) error); ok {
+		r0 = rf(todoID, retryAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -53,23 +1569,79 @@ func (_m *Repository) CreateUser(user *entity.Users) error {
 	return r0
 }
 
-// Delete provides a mock function with given fields: todoID
-func (_m *Repository) Delete(todoID int64) (int64, error) {
-	ret := _m.Called(todoID)
+// RenameTag provides a mock function with given fields: tag, name
+func (_m *Repository) RenameTag(tag *entity.Tags, name string) error {
+	ret := _m.Called(tag, name)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Tags, string) error); ok {
+		r0 = rf(tag, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReorderSubtasks provides a mock function with given fields: parentID, order
+func (_m *Repository) ReorderSubtasks(parentID int64, order []int64) error {
+	ret := _m.Called(parentID, order)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, []int64) error); ok {
+		r0 = rf(parentID, order)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: token, passwordHash
+func (_m *Repository) ResetPassword(token *entity.PasswordResetTokens, passwordHash string) error {
+	ret := _m.Called(token, passwordHash)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.PasswordResetTokens, string) error); ok {
+		r0 = rf(token, passwordHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RestoreTodos provides a mock function with given fields: snapshot
+func (_m *Repository) RestoreTodos(snapshot *entity.UndoSnapshot) error {
+	ret := _m.Called(snapshot)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.UndoSnapshot) error); ok {
+		r0 = rf(snapshot)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeAPIKey provides a mock function with given fields: userID, keyID
+func (_m *Repository) RevokeAPIKey(userID int64, keyID int64) (int64, error) {
+	ret := _m.Called(userID, keyID)
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
-		return rf(todoID)
+	if rf, ok := ret.Get(0).(func(int64, int64) (int64, error)); ok {
+		return rf(userID, keyID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) int64); ok {
-		r0 = rf(todoID)
+	if rf, ok := ret.Get(0).(func(int64, int64) int64); ok {
+		r0 = rf(userID, keyID)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(todoID)
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, keyID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -77,25 +1649,23 @@ func (_m *Repository) Delete(todoID int64) (int64, error) {
 	return r0, r1
 }
 
-// FindUserByEmail provides a mock function with given fields: username
-func (_m *Repository) FindUserByEmail(username string) (*entity.Users, error) {
-	ret := _m.Called(username)
+// RevokeSession provides a mock function with given fields: userID, sessionID
+func (_m *Repository) RevokeSession(userID int64, sessionID int64) (int64, error) {
+	ret := _m.Called(userID, sessionID)
 
-	var r0 *entity.Users
+	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*entity.Users, error)); ok {
-		return rf(username)
+	if rf, ok := ret.Get(0).(func(int64, int64) (int64, error)); ok {
+		return rf(userID, sessionID)
 	}
-	if rf, ok := ret.Get(0).(func(string) *entity.Users); ok {
-		r0 = rf(username)
+	if rf, ok := ret.Get(0).(func(int64, int64) int64); ok {
+		r0 = rf(userID, sessionID)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.Users)
-		}
+		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(username)
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, sessionID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,25 +1673,39 @@ func (_m *Repository) FindUserByEmail(username string) (*entity.Users, error) {
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *Repository) GetAll() ([]entity.Todos, error) {
-	ret := _m.Called()
+// SaveShare provides a mock function with given fields: share
+func (_m *Repository) SaveShare(share *entity.Shares) error {
+	ret := _m.Called(share)
 
-	var r0 []entity.Todos
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.Shares) error); ok {
+		r0 = rf(share)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SearchTodos provides a mock function with given fields: query
+func (_m *Repository) SearchTodos(query *dto.SearchQuery) ([]entity.TodoMatch, error) {
+	ret := _m.Called(query)
+
+	var r0 []entity.TodoMatch
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]entity.Todos, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(*dto.SearchQuery) ([]entity.TodoMatch, error)); ok {
+		return rf(query)
 	}
-	if rf, ok := ret.Get(0).(func() []entity.Todos); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(*dto.SearchQuery) []entity.TodoMatch); ok {
+		r0 = rf(query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.Todos)
+			r0 = ret.Get(0).([]entity.TodoMatch)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(*dto.SearchQuery) error); ok {
+		r1 = rf(query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -129,20 +1713,86 @@ func (_m *Repository) GetAll() ([]entity.Todos, error) {
 	return r0, r1
 }
 
-// GetID provides a mock function with given fields: todoID
-func (_m *Repository) GetID(todoID int64) (*entity.Todos, error) {
+// SetTOTPSecret provides a mock function with given fields: userID, secret
+func (_m *Repository) SetTOTPSecret(userID int64, secret string) error {
+	ret := _m.Called(userID, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string) error); ok {
+		r0 = rf(userID, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserDisabled provides a mock function with given fields: userID, disabled
+func (_m *Repository) SetUserDisabled(userID int64, disabled bool) error {
+	ret := _m.Called(userID, disabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, bool) error); ok {
+		r0 = rf(userID, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetUserRole provides a mock function with given fields: userID, role
+func (_m *Repository) SetUserRole(userID int64, role string) error {
+	ret := _m.Called(userID, role)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string) error); ok {
+		r0 = rf(userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SubtaskProgress provides a mock function with given fields: parentID
+func (_m *Repository) SubtaskProgress(parentID int64) (entity.SubtaskProgress, error) {
+	ret := _m.Called(parentID)
+
+	var r0 entity.SubtaskProgress
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (entity.SubtaskProgress, error)); ok {
+		return rf(parentID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) entity.SubtaskProgress); ok {
+		r0 = rf(parentID)
+	} else {
+		r0 = ret.Get(0).(entity.SubtaskProgress)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(parentID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TodoSnapshot provides a mock function with given fields: todoID
+func (_m *Repository) TodoSnapshot(todoID int64) (*entity.UndoSnapshot, error) {
 	ret := _m.Called(todoID)
 
-	var r0 *entity.Todos
+	var r0 *entity.UndoSnapshot
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*entity.Todos, error)); ok {
+	if rf, ok := ret.Get(0).(func(int64) (*entity.UndoSnapshot, error)); ok {
 		return rf(todoID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *entity.Todos); ok {
+	if rf, ok := ret.Get(0).(func(int64) *entity.UndoSnapshot); ok {
 		r0 = rf(todoID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*entity.Todos)
+			r0 = ret.Get(0).(*entity.UndoSnapshot)
 		}
 	}
 
@@ -155,6 +1805,48 @@ func (_m *Repository) GetID(todoID int64) (*entity.Todos, error) {
 	return r0, r1
 }
 
+// TouchAPIKey provides a mock function with given fields: keyID
+func (_m *Repository) TouchAPIKey(keyID int64) error {
+	ret := _m.Called(keyID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(keyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TouchSession provides a mock function with given fields: sessionID
+func (_m *Repository) TouchSession(sessionID int64) error {
+	ret := _m.Called(sessionID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Transaction provides a mock function with given fields: fn
+func (_m *Repository) Transaction(fn func(repository.Repository) error) error {
+	ret := _m.Called(fn)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(func(repository.Repository) error) error); ok {
+		r0 = rf(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: todoID, updates
 func (_m *Repository) Update(todoID int64, updates map[string]interface{}) (*entity.Todos, error) {
 	ret := _m.Called(todoID, updates)
@@ -181,6 +1873,162 @@ func (_m *Repository) Update(todoID int64, updates map[string]interface{}) (*ent
 	return r0, r1
 }
 
+// UpdateComment provides a mock function with given fields: commentID, body, editedAt
+func (_m *Repository) UpdateComment(commentID int64, body string, editedAt time.Time) error {
+	ret := _m.Called(commentID, body, editedAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, string, time.Time) error); ok {
+		r0 = rf(commentID, body, editedAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateList provides a mock function with given fields: listID, updates
+func (_m *Repository) UpdateList(listID int64, updates map[string]interface{}) (*entity.Lists, error) {
+	ret := _m.Called(listID, updates)
+
+	var r0 *entity.Lists
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) (*entity.Lists, error)); ok {
+		return rf(listID, updates)
+	}
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) *entity.Lists); ok {
+		r0 = rf(listID, updates)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Lists)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, map[string]interface{}) error); ok {
+		r1 = rf(listID, updates)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdatePassword provides a mock function with given fields: userID, passwordHash
+func (_m *Repository) UpdatePassword(userID int64, passwordHash string) (*entity.Users, error) {
+	ret := _m.Called(userID, passwordHash)
+
+	var r0 *entity.Users
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, string) (*entity.Users, error)); ok {
+		return rf(userID, passwordHash)
+	}
+	if rf, ok := ret.Get(0).(func(int64, string) *entity.Users); ok {
+		r0 = rf(userID, passwordHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Users)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(userID, passwordHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateUser provides a mock function with given fields: userID, updates
+func (_m *Repository) UpdateUser(userID int64, updates map[string]interface{}) (*entity.Users, error) {
+	ret := _m.Called(userID, updates)
+
+	var r0 *entity.Users
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) (*entity.Users, error)); ok {
+		return rf(userID, updates)
+	}
+	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) *entity.Users); ok {
+		r0 = rf(userID, updates)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Users)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, map[string]interface{}) error); ok {
+		r1 = rf(userID, updates)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UseRecoveryCode provides a mock function with given fields: userID, codeHash
+func (_m *Repository) UseRecoveryCode(userID int64, codeHash string) (bool, error) {
+	ret := _m.Called(userID, codeHash)
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, string) (bool, error)); ok {
+		return rf(userID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(int64, string) bool); ok {
+		r0 = rf(userID, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(userID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserAttachmentKeys provides a mock function with given fields: userID
+func (_m *Repository) UserAttachmentKeys(userID int64) ([]string, error) {
+	ret := _m.Called(userID)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) ([]string, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) []string); ok {
+		r0 = rf(userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// VerifyEmail provides a mock function with given fields: token
+func (_m *Repository) VerifyEmail(token *entity.EmailVerificationTokens) error {
+	ret := _m.Called(token)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*entity.EmailVerificationTokens) error); ok {
+		r0 = rf(token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewRepository creates a new instance of Repository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewRepository(t interface {